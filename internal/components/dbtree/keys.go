@@ -17,6 +17,9 @@ type KeyMap struct {
 	Enter           key.Binding
 	Quit            key.Binding
 	OpenCommandBar  key.Binding
+	OpenNotify      key.Binding
+	Import          key.Binding
+	CreateFromFile  key.Binding
 	Escape          key.Binding
 }
 
@@ -78,6 +81,18 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("c"),
 		key.WithHelp("c", "open command bar"),
 	),
+	OpenNotify: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "monitor LISTEN/NOTIFY"),
+	),
+	Import: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "import file into table"),
+	),
+	CreateFromFile: key.NewBinding(
+		key.WithKeys("I"),
+		key.WithHelp("I", "create table from file"),
+	),
 }
 
 // ShortHelp returns keybindings for the short help view
@@ -89,8 +104,8 @@ func (k KeyMap) ShortHelp() []key.Binding {
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Left, k.Right},
-		{k.Space, k.Enter},
-		{k.ScrollUp, k.ScrollDown},
+		{k.Space, k.Enter, k.OpenCommandBar, k.OpenNotify},
+		{k.ScrollUp, k.ScrollDown, k.Import, k.CreateFromFile},
 		{k.Quit},
 	}
 }
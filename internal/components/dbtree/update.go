@@ -115,6 +115,33 @@ func (m DBTreeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
+		case key.Matches(msg, DefaultKeyMap.OpenNotify):
+			db := m.tree.CurrentDatabase()
+			if db == nil {
+				return m, nil
+			}
+			return m, func() tea.Msg {
+				return messages.OpenNotifyTabMsg{DatabaseID: db.id}
+			}
+
+		case key.Matches(msg, DefaultKeyMap.Import):
+			db, table := m.tree.CurrentDatabase(), m.tree.CurrentTable()
+			if table == nil {
+				return m, nil
+			}
+			return m, func() tea.Msg {
+				return messages.OpenImportMsg{DatabaseID: db.id, Table: table.table}
+			}
+
+		case key.Matches(msg, DefaultKeyMap.CreateFromFile):
+			db, schema := m.tree.CurrentDatabase(), m.tree.CurrentSchema()
+			if schema == nil {
+				return m, nil
+			}
+			return m, func() tea.Msg {
+				return messages.OpenImportMsg{DatabaseID: db.id, Schema: schema.schema}
+			}
+
 		case key.Matches(msg, DefaultKeyMap.Escape):
 			m.search.Clear()
 		case key.Matches(msg, DefaultKeyMap.Quit):
@@ -0,0 +1,175 @@
+package importwizard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/internal/components/logpanel"
+	"github.com/SavingFrame/dbettier/internal/components/notifications"
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/SavingFrame/dbettier/internal/importer"
+	"github.com/SavingFrame/dbettier/internal/messages"
+)
+
+// importBatchSize is the number of records read and copied per step
+const importBatchSize = 10_000
+
+// plan is an import as set up in the wizard
+type plan struct {
+	databaseID string
+	schema     *database.Schema
+	table      string
+	path       string
+	opts       importer.Options
+	// columns are the table columns copied into, each from the file field
+	// in fields, converted for the type in types
+	columns []string
+	fields  []int
+	types   []string
+	// createSQL creates the table first, if it is new
+	createSQL string
+}
+
+// job is an import in progress. Each step copies one batch and reports
+// back, so progress shows in the log panel; everything is committed at
+// the end, or nothing.
+type job struct {
+	plan
+	reader *importer.Reader
+	imp    *database.Import
+}
+
+// startImport opens the file and the transaction and copies the first batch
+func startImport(p plan) tea.Cmd {
+	return func() tea.Msg {
+		j := &job{plan: p}
+		var err error
+		if j.reader, err = importer.Open(p.path, p.opts); err != nil {
+			return j.finish(err)
+		}
+		if j.imp, err = p.schema.Database.BeginImport(context.Background(), p.schema.Name, p.table, p.columns, p.createSQL); err != nil {
+			return j.finish(err)
+		}
+		cmds := []tea.Cmd{
+			logpanel.AddLogCmd(fmt.Sprintf("Importing %s into %s", p.path, j.target()), messages.LogInfo),
+			j.step,
+		}
+		if p.createSQL != "" {
+			cmds = append([]tea.Cmd{logpanel.AddLogCmd(p.createSQL, messages.LogSQL)}, cmds...)
+		}
+		return tea.BatchMsg(cmds)
+	}
+}
+
+func (j *job) target() string {
+	return j.schema.Name + "." + j.table
+}
+
+// step reads and copies the next batch of records
+func (j *job) step() tea.Msg {
+	batch := make([][]any, 0, importBatchSize)
+	var readErr error
+	for len(batch) < importBatchSize {
+		record, err := j.reader.Read()
+		if err != nil {
+			readErr = err
+			break
+		}
+		row, err := j.convert(record)
+		if err != nil {
+			return j.finish(err)
+		}
+		batch = append(batch, row)
+	}
+	if readErr != nil && !errors.Is(readErr, io.EOF) {
+		return j.finish(readErr)
+	}
+	if len(batch) > 0 {
+		if err := j.imp.Copy(context.Background(), batch); err != nil {
+			return j.finish(err)
+		}
+	}
+	if readErr != nil {
+		return j.finish(nil)
+	}
+	return j.progress(false, nil, j.step)
+}
+
+// convert picks the fields of a record copied into the table and converts
+// them for their columns
+func (j *job) convert(record []any) ([]any, error) {
+	row := make([]any, len(j.fields))
+	for i, f := range j.fields {
+		if f >= len(record) {
+			continue
+		}
+		v, err := importer.Convert(record[f], j.types[i])
+		if err != nil {
+			return nil, fmt.Errorf("line %d, column %s: %w", j.reader.Line(), j.columns[i], err)
+		}
+		row[i] = v
+	}
+	return row, nil
+}
+
+// finish commits the import, or rolls it back if it failed
+func (j *job) finish(err error) tea.Msg {
+	if j.reader != nil {
+		_ = j.reader.Close()
+	}
+	if j.imp != nil {
+		if err == nil {
+			err = j.imp.Commit(context.Background())
+		} else {
+			_ = j.imp.Rollback(context.Background())
+		}
+	}
+	return j.progress(err == nil, err, nil)
+}
+
+func (j *job) progress(done bool, err error, next tea.Cmd) messages.ImportProgressMsg {
+	msg := messages.ImportProgressMsg{
+		DatabaseID: j.databaseID,
+		Path:       j.path,
+		Table:      j.target(),
+		Done:       done,
+		Err:        err,
+		Next:       next,
+	}
+	if j.imp != nil {
+		msg.Rows = j.imp.Copied()
+	}
+	if done && j.createSQL != "" {
+		msg.Created = &database.Table{Name: j.table, Schema: j.schema}
+	}
+	return msg
+}
+
+// HandleProgress logs an import's progress and continues it. A table
+// created for the import is opened once it is done.
+func HandleProgress(msg messages.ImportProgressMsg) tea.Cmd {
+	switch {
+	case msg.Err != nil:
+		text := fmt.Sprintf("Import of %s into %s failed, nothing was imported: %v", msg.Path, msg.Table, msg.Err)
+		return tea.Batch(
+			logpanel.AddLogCmd(text, messages.LogError),
+			notifications.ShowError("Import failed: "+msg.Err.Error()),
+		)
+	case msg.Done:
+		text := fmt.Sprintf("Imported %d rows from %s into %s", msg.Rows, msg.Path, msg.Table)
+		cmds := []tea.Cmd{logpanel.AddLogCmd(text, messages.LogSuccess), notifications.ShowSuccess(text)}
+		if msg.Created != nil {
+			created := messages.OpenTableAndExecuteMsg{Table: msg.Created, DatabaseID: msg.DatabaseID}
+			cmds = append(cmds, func() tea.Msg { return created })
+		}
+		return tea.Batch(cmds...)
+	default:
+		return tea.Batch(
+			logpanel.AddLogCmd(fmt.Sprintf("Imported %d rows into %s so far", msg.Rows, msg.Table), messages.LogInfo),
+			msg.Next,
+		)
+	}
+}
@@ -0,0 +1,49 @@
+package importwizard
+
+import "charm.land/bubbles/v2/key"
+
+// KeyMap defines keybindings for the import wizard
+type KeyMap struct {
+	Next   key.Binding
+	Prev   key.Binding
+	Change key.Binding
+	Submit key.Binding
+	Back   key.Binding
+}
+
+// DefaultKeyMap returns the default keybindings for the import wizard
+var DefaultKeyMap = KeyMap{
+	Next: key.NewBinding(
+		key.WithKeys("tab", "down"),
+		key.WithHelp("tab/↓", "next field"),
+	),
+	Prev: key.NewBinding(
+		key.WithKeys("shift+tab", "up"),
+		key.WithHelp("shift+tab/↑", "prev field"),
+	),
+	Change: key.NewBinding(
+		key.WithKeys("left", "right"),
+		key.WithHelp("←/→", "change choice"),
+	),
+	Submit: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "continue / import"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back / cancel"),
+	),
+}
+
+// ShortHelp returns keybindings for the short help view
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Submit, k.Next, k.Change, k.Back}
+}
+
+// FullHelp returns keybindings for the expanded help view
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Next, k.Prev, k.Change},
+		{k.Submit, k.Back},
+	}
+}
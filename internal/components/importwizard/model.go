@@ -0,0 +1,233 @@
+// Package importwizard provides the popup that loads a CSV, TSV or NDJSON
+// file into a table, or into a new table created from the file.
+package importwizard
+
+import (
+	"path/filepath"
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/SavingFrame/dbettier/internal/importer"
+	"github.com/SavingFrame/dbettier/internal/messages"
+)
+
+// inputWidth is the visible width of each text input
+const inputWidth = 48
+
+// sampleRows is the number of records read to preview the file and check
+// or infer column types
+const sampleRows = 1000
+
+// step is a page of the wizard
+type step int
+
+const (
+	// stepFile asks for the file and how to read it
+	stepFile step = iota
+	// stepColumns maps the fields of the file to columns
+	stepColumns
+)
+
+// field is a field of the file step
+type field int
+
+const (
+	fieldPath field = iota
+	fieldFormat
+	fieldDelimiter
+	fieldHeader
+	fieldNull
+	fieldTable
+	fieldCount
+)
+
+// Model is the state of the import wizard
+type Model struct {
+	request messages.OpenImportMsg
+	step    step
+	closed  bool
+
+	focus  field
+	inputs map[field]*textinput.Model
+	format int
+	header bool
+
+	// columns are the columns of the existing table, once loaded
+	columns    []*database.Column
+	columnsErr error
+	sample     importer.Sample
+	// mapping holds the file field copied into each column, -1 for none
+	mapping []int
+	// types holds the type of each file field in a new table, "" to leave
+	// the field out
+	types  []string
+	cursor int
+}
+
+// New creates a wizard for the request
+func New(req messages.OpenImportMsg) Model {
+	m := Model{request: req, header: true, inputs: map[field]*textinput.Model{}}
+	placeholders := map[field]string{
+		fieldPath:      "path/to/file.csv",
+		fieldDelimiter: ",",
+		fieldNull:      "empty field",
+		fieldTable:     "table name",
+	}
+	for f, placeholder := range placeholders {
+		t := textinput.New()
+		t.Prompt = ""
+		t.CharLimit = 0
+		t.SetWidth(inputWidth)
+		t.SetStyles(inputStyles())
+		t.Placeholder = placeholder
+		m.inputs[f] = &t
+	}
+	m.inputs[fieldDelimiter].SetValue(",")
+	return m
+}
+
+// Init focuses the path input and loads the columns of the table
+func (m *Model) Init() tea.Cmd {
+	cmd := m.setFocus(fieldPath)
+	if m.creating() {
+		return cmd
+	}
+	table := m.request.Table
+	return tea.Batch(cmd, func() tea.Msg {
+		columns, err := table.LoadColumnsForTable()
+		return columnsLoadedMsg{table: table, columns: columns, err: err}
+	})
+}
+
+// Closed reports whether the import was started or cancelled
+func (m Model) Closed() bool {
+	return m.closed
+}
+
+// columnsLoadedMsg carries the columns of the table imported into
+type columnsLoadedMsg struct {
+	table   *database.Table
+	columns []*database.Column
+	err     error
+}
+
+// sampleLoadedMsg carries the start of the file
+type sampleLoadedMsg struct {
+	sample importer.Sample
+	err    error
+}
+
+// creating reports whether the import creates a new table
+func (m Model) creating() bool {
+	return m.request.Table == nil
+}
+
+func (m Model) schema() *database.Schema {
+	if m.request.Table != nil {
+		return m.request.Table.Schema
+	}
+	return m.request.Schema
+}
+
+func (m Model) path() string {
+	return strings.TrimSpace(m.inputs[fieldPath].Value())
+}
+
+// tableName returns the table imported into. A new table is named after
+// the file unless a name was given.
+func (m Model) tableName() string {
+	if !m.creating() {
+		return m.request.Table.Name
+	}
+	if name := strings.TrimSpace(m.inputs[fieldTable].Value()); name != "" {
+		return name
+	}
+	base := filepath.Base(m.path())
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+func (m Model) selected() importer.Format {
+	return importer.Formats[m.format]
+}
+
+// visible reports whether a field of the file step applies
+func (m Model) visible(f field) bool {
+	switch f {
+	case fieldDelimiter:
+		return m.selected() == importer.CSV
+	case fieldHeader, fieldNull:
+		return m.selected() != importer.NDJSON
+	case fieldTable:
+		return m.creating()
+	}
+	return true
+}
+
+// setFocus moves the focus to a field, focusing its text input if it has one
+func (m *Model) setFocus(f field) tea.Cmd {
+	m.focus = f
+	var cmd tea.Cmd
+	for name, input := range m.inputs {
+		if name == f {
+			cmd = input.Focus()
+		} else {
+			input.Blur()
+		}
+	}
+	return cmd
+}
+
+// move focuses the next or previous visible field
+func (m *Model) move(delta int) tea.Cmd {
+	f := m.focus
+	for {
+		f = (f + field(delta) + fieldCount) % fieldCount
+		if m.visible(f) {
+			return m.setFocus(f)
+		}
+	}
+}
+
+// setSample shows the start of the file and pairs its fields with columns
+func (m *Model) setSample(sample importer.Sample) {
+	m.sample = sample
+	m.cursor = 0
+	m.step = stepColumns
+	m.setFocus(fieldCount)
+	if m.creating() {
+		m.types = make([]string, len(sample.Columns))
+		for i := range sample.Columns {
+			m.types[i] = importer.Infer(sample.Values(i))
+		}
+		return
+	}
+	m.matchColumns()
+}
+
+// matchColumns pairs the table columns with the file fields of the same
+// name, leaving out columns of types that cannot be imported
+func (m *Model) matchColumns() {
+	if m.columns == nil || m.sample.Columns == nil {
+		return
+	}
+	names := make([]string, len(m.columns))
+	for i, col := range m.columns {
+		names[i] = col.Name
+	}
+	m.mapping = importer.Match(names, m.sample.Columns)
+	for i, col := range m.columns {
+		if !importer.Supported(col.DataType) {
+			m.mapping[i] = -1
+		}
+	}
+}
+
+// rowCount returns the number of rows of the column step
+func (m Model) rowCount() int {
+	if m.creating() {
+		return len(m.types)
+	}
+	return len(m.mapping)
+}
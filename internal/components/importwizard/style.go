@@ -0,0 +1,83 @@
+package importwizard
+
+import (
+	"charm.land/bubbles/v2/textinput"
+	"charm.land/lipgloss/v2"
+	"github.com/SavingFrame/dbettier/internal/theme"
+)
+
+// inputStyles returns the text input styles using current theme
+func inputStyles() textinput.Styles {
+	colors := theme.Current().Colors
+	inputTextStyle := lipgloss.NewStyle().Background(colors.Base).Foreground(colors.Text)
+	inputPlaceholderStyle := lipgloss.NewStyle().Background(colors.Base).Foreground(colors.Muted)
+
+	return textinput.Styles{
+		Focused: textinput.StyleState{
+			Text:        inputTextStyle,
+			Placeholder: inputPlaceholderStyle,
+		},
+		Blurred: textinput.StyleState{
+			Text:        inputTextStyle.Foreground(colors.Subtle),
+			Placeholder: inputPlaceholderStyle,
+		},
+	}
+}
+
+func surfaceStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Background(theme.Current().Colors.Surface)
+}
+
+func popupStyle() lipgloss.Style {
+	colors := theme.Current().Colors
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colors.BorderFocused).
+		BorderBackground(colors.Surface).
+		Background(colors.Surface).
+		Padding(1, 2)
+}
+
+func titleStyle() lipgloss.Style {
+	return surfaceStyle().Foreground(theme.Current().Colors.Primary).Bold(true)
+}
+
+func labelStyle(focused bool) lipgloss.Style {
+	colors := theme.Current().Colors
+	style := surfaceStyle().Foreground(colors.Info).Bold(true)
+	if focused {
+		style = style.Foreground(colors.Primary)
+	}
+	return style
+}
+
+func typeStyle() lipgloss.Style {
+	return surfaceStyle().Foreground(theme.Current().Colors.Teal)
+}
+
+func choiceStyle() lipgloss.Style {
+	colors := theme.Current().Colors
+	return lipgloss.NewStyle().Background(colors.Overlay).Foreground(colors.Info)
+}
+
+func fieldStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Background(theme.Current().Colors.Base).
+		Padding(0, 1)
+}
+
+func okStyle() lipgloss.Style {
+	return surfaceStyle().Foreground(theme.Current().Colors.Success)
+}
+
+func badStyle() lipgloss.Style {
+	return surfaceStyle().Foreground(theme.Current().Colors.Error)
+}
+
+func previewStyle() lipgloss.Style {
+	return surfaceStyle().Foreground(theme.Current().Colors.Subtle)
+}
+
+func hintStyle() lipgloss.Style {
+	return surfaceStyle().Foreground(theme.Current().Colors.Muted)
+}
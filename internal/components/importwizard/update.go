@@ -0,0 +1,241 @@
+package importwizard
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/internal/components/logpanel"
+	"github.com/SavingFrame/dbettier/internal/components/notifications"
+	"github.com/SavingFrame/dbettier/internal/importer"
+	"github.com/SavingFrame/dbettier/internal/messages"
+)
+
+// Update handles key input and the loaded columns and file sample
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case columnsLoadedMsg:
+		if msg.table == m.request.Table {
+			m.columns, m.columnsErr = msg.columns, msg.err
+			m.matchColumns()
+		}
+		return m, nil
+	case sampleLoadedMsg:
+		if msg.err != nil {
+			return m, notifications.ShowError("Cannot read " + m.path() + ": " + msg.err.Error())
+		}
+		m.setSample(msg.sample)
+		return m, nil
+	case tea.KeyMsg:
+		if m.step == stepColumns {
+			return m.updateColumns(msg)
+		}
+		return m.updateFile(msg)
+	}
+
+	input, ok := m.inputs[m.focus]
+	if !ok {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	*input, cmd = input.Update(msg)
+	return m, cmd
+}
+
+// updateFile handles keys on the file step; enter reads the file
+func (m Model) updateFile(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Back):
+		m.closed = true
+		return m, logpanel.AddLogCmd("Import cancelled", messages.LogInfo)
+	case key.Matches(msg, DefaultKeyMap.Submit):
+		return m, m.loadSample()
+	case key.Matches(msg, DefaultKeyMap.Next):
+		return m, m.move(1)
+	case key.Matches(msg, DefaultKeyMap.Prev):
+		return m, m.move(-1)
+	}
+
+	if input, ok := m.inputs[m.focus]; ok {
+		var cmd tea.Cmd
+		*input, cmd = input.Update(msg)
+		if m.focus == fieldPath {
+			m.format = int(importer.FormatForPath(m.path()))
+		}
+		return m, cmd
+	}
+	delta := 1
+	if msg.String() == "left" {
+		delta = -1
+	}
+	switch msg.String() {
+	case "left", "right", "space", " ":
+		switch m.focus {
+		case fieldFormat:
+			m.format = (m.format + delta + len(importer.Formats)) % len(importer.Formats)
+		case fieldHeader:
+			m.header = !m.header
+		}
+	}
+	return m, nil
+}
+
+// options returns how to read the file, or an error to show
+func (m Model) options() (importer.Options, error) {
+	opts := importer.Options{
+		Format:   m.selected(),
+		Header:   m.header,
+		NullText: m.inputs[fieldNull].Value(),
+	}
+	delimiter := m.inputs[fieldDelimiter].Value()
+	if delimiter == `\t` {
+		delimiter = "\t"
+	}
+	if opts.Format == importer.CSV {
+		r, size := utf8.DecodeRuneInString(delimiter)
+		if size == 0 || size != len(delimiter) || r == '"' || r == '\r' || r == '\n' {
+			return opts, errors.New("the delimiter must be one character other than a quote or line break")
+		}
+		opts.Delimiter = r
+	}
+	return opts, nil
+}
+
+// loadSample reads the start of the file for the column step
+func (m Model) loadSample() tea.Cmd {
+	opts, err := m.options()
+	if err != nil {
+		return notifications.ShowError(err.Error())
+	}
+	path := m.path()
+	if path == "" {
+		return notifications.ShowError("name the file to import")
+	}
+	if m.creating() && m.tableName() == "" {
+		return notifications.ShowError("name the table to create")
+	}
+	return func() tea.Msg {
+		sample, err := importer.ReadSample(path, opts, sampleRows)
+		return sampleLoadedMsg{sample: sample, err: err}
+	}
+}
+
+// updateColumns handles keys on the column step; enter starts the import
+func (m Model) updateColumns(msg tea.KeyMsg) (Model, tea.Cmd) {
+	rows := m.rowCount()
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Back):
+		m.step = stepFile
+		return m, m.setFocus(fieldPath)
+	case key.Matches(msg, DefaultKeyMap.Submit):
+		return m.start()
+	case key.Matches(msg, DefaultKeyMap.Next):
+		if rows > 0 {
+			m.cursor = (m.cursor + 1) % rows
+		}
+		return m, nil
+	case key.Matches(msg, DefaultKeyMap.Prev):
+		if rows > 0 {
+			m.cursor = (m.cursor - 1 + rows) % rows
+		}
+		return m, nil
+	case key.Matches(msg, DefaultKeyMap.Change):
+		if rows == 0 {
+			return m, nil
+		}
+		delta := 1
+		if msg.String() == "left" {
+			delta = -1
+		}
+		m.cycle(delta)
+	}
+	return m, nil
+}
+
+// cycle changes the field copied into the column under the cursor, or the
+// type of the field under the cursor in a new table
+func (m *Model) cycle(delta int) {
+	if m.creating() {
+		// Types, then "" for leaving the field out
+		choices := append(importer.Types[:len(importer.Types):len(importer.Types)], "")
+		i := 0
+		for j, t := range choices {
+			if t == m.types[m.cursor] {
+				i = j
+			}
+		}
+		m.types[m.cursor] = choices[(i+delta+len(choices))%len(choices)]
+		return
+	}
+	if !importer.Supported(m.columns[m.cursor].DataType) {
+		return
+	}
+	// Fields, then -1 for no field
+	n := len(m.sample.Columns) + 1
+	m.mapping[m.cursor] = (m.mapping[m.cursor]+1+delta+n)%n - 1
+}
+
+// check reports why the sampled values of a file field do not convert to
+// a column type, or "" if they all do
+func (m Model) check(fieldIndex int, dataType string) string {
+	if !importer.Supported(dataType) {
+		return dataType + " is not supported"
+	}
+	bad, err := importer.Check(m.sample.Values(fieldIndex), dataType)
+	if bad == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d of %d sampled values fail: %v", bad, len(m.sample.Rows), err)
+}
+
+// start checks the mapping and starts the import
+func (m Model) start() (Model, tea.Cmd) {
+	p := plan{
+		databaseID: m.request.DatabaseID,
+		schema:     m.schema(),
+		table:      m.tableName(),
+		path:       m.path(),
+	}
+	p.opts, _ = m.options()
+
+	if m.creating() {
+		for i, t := range m.types {
+			if t == "" {
+				continue
+			}
+			if problem := m.check(i, t); problem != "" {
+				return m, notifications.ShowError(m.sample.Columns[i] + ": " + problem)
+			}
+			p.columns = append(p.columns, m.sample.Columns[i])
+			p.fields = append(p.fields, i)
+			p.types = append(p.types, t)
+		}
+		p.createSQL = importer.CreateTableSQL(p.schema.Name, p.table, p.columns, p.types)
+	} else {
+		if m.columns == nil {
+			if m.columnsErr != nil {
+				return m, notifications.ShowError("Cannot load columns: " + m.columnsErr.Error())
+			}
+			return m, notifications.ShowWarning("The columns are still loading")
+		}
+		for i, fieldIndex := range m.mapping {
+			if fieldIndex < 0 {
+				continue
+			}
+			col := m.columns[i]
+			if problem := m.check(fieldIndex, col.DataType); problem != "" {
+				return m, notifications.ShowError(col.Name + ": " + problem)
+			}
+			p.columns = append(p.columns, col.Name)
+			p.fields = append(p.fields, fieldIndex)
+			p.types = append(p.types, col.DataType)
+		}
+	}
+	if len(p.columns) == 0 {
+		return m, notifications.ShowError("choose at least one column to import")
+	}
+	m.closed = true
+	return m, startImport(p)
+}
@@ -0,0 +1,223 @@
+package importwizard
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/SavingFrame/dbettier/internal/importer"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	// maxVisibleRows limits how many columns are listed at once
+	maxVisibleRows = 12
+	// previewRows is the number of file records shown under the columns
+	previewRows = 5
+	// previewCellWidth is the width of each field of the preview
+	previewCellWidth = 14
+	// popupWidth is the width of the popup content
+	popupWidth = inputWidth + 40
+)
+
+// View renders the wizard as a popup
+func (m Model) View() string {
+	var rows []string
+	if m.step == stepFile {
+		rows = m.renderFile()
+	} else {
+		rows = m.renderColumns()
+	}
+	return popupStyle().Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+func (m Model) title() string {
+	if m.creating() {
+		return fmt.Sprintf("New table in %s from a file", m.schema().Name)
+	}
+	return fmt.Sprintf("Import a file into %s.%s", m.schema().Name, m.request.Table.Name)
+}
+
+// renderFile renders the file step
+func (m Model) renderFile() []string {
+	choice := func(text string) string {
+		return choiceStyle().Render("‹ " + text + " ›")
+	}
+	yesNo := map[bool]string{true: "yes", false: "no"}
+	fields := []struct {
+		field field
+		name  string
+		value string
+	}{
+		{fieldPath, "File", fieldStyle().Render(m.inputs[fieldPath].View())},
+		{fieldFormat, "Format", choice(m.selected().String())},
+		{fieldDelimiter, "Delimiter", fieldStyle().Render(m.inputs[fieldDelimiter].View())},
+		{fieldHeader, "Header", choice(yesNo[m.header])},
+		{fieldNull, "NULL as", fieldStyle().Render(m.inputs[fieldNull].View())},
+		{fieldTable, "Table", fieldStyle().Render(m.inputs[fieldTable].View())},
+	}
+
+	rows := []string{titleStyle().Render(m.title()), ""}
+	for _, f := range fields {
+		if !m.visible(f.field) {
+			continue
+		}
+		focused := m.focus == f.field
+		marker := "  "
+		if focused {
+			marker = "▸ "
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top,
+			labelStyle(focused).Render(marker),
+			labelStyle(focused).Width(12).Render(f.name),
+			f.value,
+		))
+	}
+	return append(rows, "", hintStyle().Render("enter read file • tab/↑↓ move • ←/→ change • esc cancel"))
+}
+
+// renderColumns renders the column step: how fields map to columns, and
+// the first records of the file
+func (m Model) renderColumns() []string {
+	rows := []string{titleStyle().Render(m.title()), ""}
+	switch {
+	case !m.creating() && m.columnsErr != nil:
+		rows = append(rows, badStyle().Render("Cannot load columns: "+m.columnsErr.Error()))
+	case !m.creating() && m.columns == nil:
+		rows = append(rows, hintStyle().Render("Loading columns…"))
+	default:
+		rows = append(rows, m.renderMapping()...)
+	}
+	rows = append(rows, "")
+	rows = append(rows, m.renderPreview()...)
+
+	hint := "enter import • ↑↓ move • ←/→ choose field • esc back"
+	if m.creating() {
+		hint = "enter create and import • ↑↓ move • ←/→ choose type • esc back"
+	}
+	return append(rows, "", hintStyle().Render(hint))
+}
+
+// renderMapping lists the columns around the cursor with the field or type
+// chosen for each and whether the sampled values convert
+func (m Model) renderMapping() []string {
+	n := m.rowCount()
+	start := max(0, min(m.cursor-maxVisibleRows/2, n-maxVisibleRows))
+	end := min(n, start+maxVisibleRows)
+
+	nameWidth, typeWidth, choiceWidth := 0, 0, 0
+	for i := range n {
+		name, dataType, choice := m.mappingRow(i)
+		nameWidth = max(nameWidth, lipgloss.Width(name))
+		typeWidth = max(typeWidth, lipgloss.Width(dataType))
+		choiceWidth = max(choiceWidth, lipgloss.Width(choice))
+	}
+
+	var rows []string
+	if start > 0 {
+		rows = append(rows, hintStyle().Render(fmt.Sprintf("  … %d more", start)))
+	}
+	for i := start; i < end; i++ {
+		focused := i == m.cursor
+		marker := "  "
+		if focused {
+			marker = "▸ "
+		}
+		name, dataType, choice := m.mappingRow(i)
+		arrow := " ← "
+		if m.creating() {
+			arrow = " → "
+		}
+		line := lipgloss.JoinHorizontal(lipgloss.Top,
+			labelStyle(focused).Render(marker),
+			labelStyle(focused).Width(nameWidth+1).Render(name),
+			typeStyle().Width(typeWidth).Render(dataType),
+			surfaceStyle().Render(arrow),
+			choiceStyle().Width(choiceWidth+4).Render("‹ "+choice+" ›"),
+			surfaceStyle().Render(" "),
+			m.renderStatus(i),
+		)
+		rows = append(rows, ansi.Truncate(line, popupWidth, "…"))
+	}
+	if end < n {
+		rows = append(rows, hintStyle().Render(fmt.Sprintf("  … %d more", n-end)))
+	}
+	return rows
+}
+
+// mappingRow returns the name, type and choice shown for row i. For an
+// existing table a row is a column and its choice the file field; for a
+// new table a row is a file field and its choice the column type.
+func (m Model) mappingRow(i int) (name, dataType, choice string) {
+	if m.creating() {
+		choice = m.types[i]
+		if choice == "" {
+			choice = "(skip)"
+		}
+		return m.sample.Columns[i], "", choice
+	}
+	col := m.columns[i]
+	choice = "(skip)"
+	if f := m.mapping[i]; f >= 0 {
+		choice = m.sample.Columns[f]
+	}
+	return col.Name, col.DataType, choice
+}
+
+// renderStatus shows whether the sampled values of row i convert
+func (m Model) renderStatus(i int) string {
+	var problem string
+	if m.creating() {
+		if m.types[i] == "" {
+			return ""
+		}
+		problem = m.check(i, m.types[i])
+	} else {
+		col := m.columns[i]
+		switch {
+		case !importer.Supported(col.DataType):
+			problem = col.DataType + " is not supported"
+		case m.mapping[i] >= 0:
+			problem = m.check(m.mapping[i], col.DataType)
+		case !col.Nullable && !col.ColumnDefault.Valid && !col.IsPrimaryKey:
+			return badStyle().Render("NOT NULL without default")
+		default:
+			return ""
+		}
+	}
+	if problem != "" {
+		return badStyle().Render("✗ " + problem)
+	}
+	return okStyle().Render("✓")
+}
+
+// renderPreview shows the first records of the file
+func (m Model) renderPreview() []string {
+	cell := func(v any) string {
+		s := "NULL"
+		if text, ok := v.(string); ok {
+			s = strings.ReplaceAll(text, "\n", "↵")
+		}
+		s = ansi.Truncate(s, previewCellWidth, "…")
+		return s + strings.Repeat(" ", previewCellWidth-ansi.StringWidth(s))
+	}
+	line := func(cells []string) string {
+		return previewStyle().Render(ansi.Truncate(strings.Join(cells, " │ "), popupWidth, "…"))
+	}
+
+	shown := min(previewRows, len(m.sample.Rows))
+	rows := []string{labelStyle(false).Render(fmt.Sprintf("Preview: %d of %d sampled rows", shown, len(m.sample.Rows)))}
+	header := make([]string, len(m.sample.Columns))
+	for i, name := range m.sample.Columns {
+		header[i] = cell(name)
+	}
+	rows = append(rows, line(header))
+	for _, record := range m.sample.Rows[:shown] {
+		cells := make([]string, len(record))
+		for i, v := range record {
+			cells[i] = cell(v)
+		}
+		rows = append(rows, line(cells))
+	}
+	return rows
+}
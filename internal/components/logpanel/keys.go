@@ -12,6 +12,8 @@ type KeyMap struct {
 	PageDown key.Binding
 	Top      key.Binding
 	Bottom   key.Binding
+	YankLast key.Binding
+	YankAll  key.Binding
 }
 
 // DefaultKeyMap returns the default keybindings for log panel
@@ -40,6 +42,14 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("end", "G"),
 		key.WithHelp("G/end", "go to bottom"),
 	),
+	YankLast: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy last entry"),
+	),
+	YankAll: key.NewBinding(
+		key.WithKeys("Y"),
+		key.WithHelp("Y", "copy whole log"),
+	),
 }
 
 // ShortHelp returns keybindings for the short help view
@@ -52,5 +62,6 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.PageUp, k.PageDown},
 		{k.Top, k.Bottom},
+		{k.YankLast, k.YankAll},
 	}
 }
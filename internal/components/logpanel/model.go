@@ -13,6 +13,7 @@ const MaxLogEntries = 1000
 type LogEntry struct {
 	Message string
 	Level   messages.LogLevel
+	Source  string
 }
 
 // LogPanelModel handles the log display viewport
@@ -105,6 +106,9 @@ func (m *LogPanelModel) refreshContent() {
 			}
 		}
 		line := style.Render(message)
+		if entry.Source != "" {
+			line = sourceStyle().Render("["+entry.Source+"] ") + line
+		}
 		if i > 0 {
 			content += "\n"
 		}
@@ -122,3 +126,14 @@ func AddLogCmd(message string, level messages.LogLevel) tea.Cmd {
 		}
 	}
 }
+
+// AddSourceLogCmd adds a log entry attributed to a tab.
+func AddSourceLogCmd(message string, level messages.LogLevel, source string) tea.Cmd {
+	return func() tea.Msg {
+		return messages.AddLogMsg{
+			Message: message,
+			Level:   level,
+			Source:  source,
+		}
+	}
+}
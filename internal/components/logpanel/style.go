@@ -21,7 +21,15 @@ func getStyleForLevel(level messages.LogLevel) lipgloss.Style {
 		return base.Foreground(colors.Error)
 	case messages.LogSQL:
 		return base.Foreground(colors.Info)
+	case messages.LogNotice:
+		return base.Foreground(colors.Teal)
 	default:
 		return base
 	}
 }
+
+// sourceStyle returns the style for the tab name prefix of an entry
+func sourceStyle() lipgloss.Style {
+	colors := theme.Current().Colors
+	return lipgloss.NewStyle().Background(colors.Base).Foreground(colors.Muted)
+}
@@ -3,10 +3,13 @@ package logpanel
 import (
 	"bytes"
 	"fmt"
+	"strings"
 
+	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 	"github.com/SavingFrame/dbettier/internal/messages"
 	"github.com/SavingFrame/dbettier/internal/theme"
+	"github.com/SavingFrame/dbettier/pkgs/clipboard"
 	"github.com/alecthomas/chroma/v2/quick"
 )
 
@@ -15,9 +18,26 @@ func (m LogPanelModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case messages.AddLogMsg:
-		m.AddLog(convertLogLevel(msg.Level), msg.Message)
+		m.AddEntry(LogEntry{
+			Message: msg.Message,
+			Level:   convertLogLevel(msg.Level),
+			Source:  msg.Source,
+		})
 		return m, nil
-	case tea.KeyPressMsg, tea.MouseMsg, tea.MouseWheelMsg:
+	case tea.KeyPressMsg:
+		switch {
+		case key.Matches(msg, DefaultKeyMap.YankLast):
+			if len(m.entries) > 0 {
+				return m, clipboard.Copy(m.entries[len(m.entries)-1].Message)
+			}
+			return m, nil
+		case key.Matches(msg, DefaultKeyMap.YankAll):
+			return m, clipboard.Copy(m.plainText())
+		}
+		if m.ready {
+			m.viewport, cmd = m.viewport.Update(msg)
+		}
+	case tea.MouseMsg, tea.MouseWheelMsg:
 		// Forward to viewport for scrolling
 		if m.ready {
 			m.viewport, cmd = m.viewport.Update(msg)
@@ -27,6 +47,18 @@ func (m LogPanelModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// plainText returns the log without styling, one entry per line
+func (m LogPanelModel) plainText() string {
+	lines := make([]string, len(m.entries))
+	for i, entry := range m.entries {
+		lines[i] = entry.Message
+		if entry.Source != "" {
+			lines[i] = "[" + entry.Source + "] " + entry.Message
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 // convertLogLevel converts shared log level to local log level
 func convertLogLevel(level messages.LogLevel) messages.LogLevel {
 	switch level {
@@ -40,6 +72,8 @@ func convertLogLevel(level messages.LogLevel) messages.LogLevel {
 		return messages.LogError
 	case messages.LogSQL:
 		return messages.LogSQL
+	case messages.LogNotice:
+		return messages.LogNotice
 	default:
 		return messages.LogInfo
 	}
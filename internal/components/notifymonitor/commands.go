@@ -0,0 +1,123 @@
+package notifymonitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/internal/database"
+)
+
+// TabMsg is implemented by messages addressed to a specific monitor tab,
+// so the workspace can deliver them even when the tab is in the background.
+type TabMsg interface {
+	MonitorTabID() string
+}
+
+// ListenerConnectedMsg is sent when the listener connection is established
+type ListenerConnectedMsg struct {
+	TabID    string
+	Listener *database.Listener
+	Err      error
+}
+
+// NotificationMsg carries a notification received on a subscribed channel
+type NotificationMsg struct {
+	TabID      string
+	Channel    string
+	Payload    string
+	PID        uint32
+	ReceivedAt time.Time
+}
+
+// ListenerStoppedMsg is sent when waiting for notifications fails
+type ListenerStoppedMsg struct {
+	TabID string
+	Err   error
+}
+
+// SubscriptionChangedMsg is sent after LISTEN or UNLISTEN completes
+type SubscriptionChangedMsg struct {
+	TabID     string
+	Channel   string
+	Listening bool
+	Err       error
+}
+
+// NotifySentMsg is sent after a NOTIFY completes
+type NotifySentMsg struct {
+	TabID   string
+	Channel string
+	Err     error
+}
+
+func (m ListenerConnectedMsg) MonitorTabID() string   { return m.TabID }
+func (m NotificationMsg) MonitorTabID() string        { return m.TabID }
+func (m ListenerStoppedMsg) MonitorTabID() string     { return m.TabID }
+func (m SubscriptionChangedMsg) MonitorTabID() string { return m.TabID }
+func (m NotifySentMsg) MonitorTabID() string          { return m.TabID }
+
+func connectCmd(tabID string, r *database.DBRegistry, databaseID string) tea.Cmd {
+	return func() tea.Msg {
+		db := r.GetByID(databaseID)
+		if db == nil {
+			return ListenerConnectedMsg{TabID: tabID, Err: fmt.Errorf("database with ID %s not found", databaseID)}
+		}
+		l, err := db.NewListener(context.Background())
+		return ListenerConnectedMsg{TabID: tabID, Listener: l, Err: err}
+	}
+}
+
+// waitCmd waits for the next notification. The model issues it again after
+// every notification, so there is at most one wait per listener.
+func waitCmd(tabID string, l *database.Listener) tea.Cmd {
+	return func() tea.Msg {
+		n, err := l.Wait(context.Background())
+		if err != nil {
+			return ListenerStoppedMsg{TabID: tabID, Err: err}
+		}
+		return NotificationMsg{
+			TabID:      tabID,
+			Channel:    n.Channel,
+			Payload:    n.Payload,
+			PID:        n.PID,
+			ReceivedAt: time.Now(),
+		}
+	}
+}
+
+func listenCmd(tabID string, l *database.Listener, channel string, listen bool) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if listen {
+			err = l.Listen(context.Background(), channel)
+		} else {
+			err = l.Unlisten(context.Background(), channel)
+		}
+		return SubscriptionChangedMsg{TabID: tabID, Channel: channel, Listening: listen, Err: err}
+	}
+}
+
+func notifyCmd(tabID string, l *database.Listener, channel, payload string) tea.Cmd {
+	return func() tea.Msg {
+		err := l.Notify(context.Background(), channel, payload)
+		return NotifySentMsg{TabID: tabID, Channel: channel, Err: err}
+	}
+}
+
+// Discard closes the listener of a message whose tab was closed while it
+// connected
+func (m ListenerConnectedMsg) Discard() {
+	if m.Listener != nil {
+		go closeListener(m.Listener)
+	}
+}
+
+func closeListener(l *database.Listener) {
+	if err := l.Close(context.Background()); err != nil && !errors.Is(err, database.ErrListenerClosed) {
+		log.Printf("Error closing listener: %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package notifymonitor
+
+import "charm.land/bubbles/v2/key"
+
+// KeyMap defines keybindings for the notify monitor
+type KeyMap struct {
+	Up        key.Binding
+	Down      key.Binding
+	Top       key.Binding
+	Bottom    key.Binding
+	Clear     key.Binding
+	Reconnect key.Binding
+	NextField key.Binding
+	PrevField key.Binding
+	Submit    key.Binding
+}
+
+// DefaultKeyMap returns the default keybindings for the notify monitor
+var DefaultKeyMap = KeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("k/up", "scroll up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("j/down", "scroll down"),
+	),
+	Top: key.NewBinding(
+		key.WithKeys("home", "g"),
+		key.WithHelp("g/home", "go to top"),
+	),
+	Bottom: key.NewBinding(
+		key.WithKeys("end", "G"),
+		key.WithHelp("G/end", "go to bottom"),
+	),
+	Clear: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "clear stream"),
+	),
+	Reconnect: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "reconnect"),
+	),
+	NextField: key.NewBinding(
+		key.WithKeys("tab", "down"),
+		key.WithHelp("tab", "next field"),
+	),
+	PrevField: key.NewBinding(
+		key.WithKeys("shift+tab", "up"),
+		key.WithHelp("shift+tab", "prev field"),
+	),
+	Submit: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "listen/unlisten or send"),
+	),
+}
+
+// ShortHelp returns keybindings for the short help view
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Submit, k.NextField, k.Clear}
+}
+
+// FullHelp returns keybindings for the expanded help view
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Top, k.Bottom},
+		{k.Clear, k.Reconnect},
+		{k.NextField, k.PrevField, k.Submit},
+	}
+}
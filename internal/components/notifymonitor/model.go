@@ -0,0 +1,190 @@
+// Package notifymonitor provides a workspace tab for watching and sending
+// PostgreSQL LISTEN/NOTIFY notifications.
+package notifymonitor
+
+import (
+	"slices"
+	"time"
+
+	"charm.land/bubbles/v2/textinput"
+	"charm.land/bubbles/v2/viewport"
+	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/internal/database"
+)
+
+// MaxEvents is the number of notifications kept in the stream
+const MaxEvents = 1000
+
+// event is a single notification shown in the stream
+type event struct {
+	receivedAt time.Time
+	channel    string
+	payload    string
+	pid        uint32
+}
+
+// controlField identifies an input in the controls pane
+type controlField int
+
+const (
+	fieldChannel controlField = iota
+	fieldNotifyChannel
+	fieldPayload
+	fieldCount
+)
+
+// Model is the state of a notify monitor tab. The stream of notifications is
+// rendered in the tab's table area and the controls in the SQL command bar area.
+type Model struct {
+	tabID      string
+	tabName    string
+	databaseID string
+	registry   *database.DBRegistry
+
+	listener   *database.Listener
+	connecting bool
+	channels   []string
+
+	// Stream
+	events   []event
+	viewport viewport.Model
+	ready    bool
+
+	// Controls
+	inputs          []textinput.Model
+	focusIndex      controlField
+	controlsFocused bool
+	status          string
+	statusIsError   bool
+
+	streamWidth    int
+	streamHeight   int
+	controlsWidth  int
+	controlsHeight int
+}
+
+// New creates a monitor for the given tab and database
+func New(tabID, tabName, databaseID string, registry *database.DBRegistry) Model {
+	inputs := make([]textinput.Model, fieldCount)
+	for i := range inputs {
+		t := textinput.New()
+		t.CharLimit = 256
+		t.SetStyles(inputStyles())
+		switch controlField(i) {
+		case fieldChannel:
+			t.Placeholder = "channel to listen"
+			t.CharLimit = 63 // NAMEDATALEN - 1
+		case fieldNotifyChannel:
+			t.Placeholder = "channel"
+			t.CharLimit = 63
+		case fieldPayload:
+			t.Placeholder = "payload"
+			t.CharLimit = 8000 // pg_notify payload limit
+		}
+		inputs[i] = t
+	}
+
+	return Model{
+		tabID:      tabID,
+		tabName:    tabName,
+		databaseID: databaseID,
+		registry:   registry,
+		inputs:     inputs,
+		connecting: true,
+	}
+}
+
+// Init opens the listener connection
+func (m Model) Init() tea.Cmd {
+	return connectCmd(m.tabID, m.registry, m.databaseID)
+}
+
+// SetSize sets the sizes of the stream and controls panes
+func (m *Model) SetSize(streamWidth, streamHeight, controlsWidth, controlsHeight int) {
+	m.streamWidth = streamWidth
+	m.streamHeight = streamHeight
+	m.controlsWidth = controlsWidth
+	m.controlsHeight = controlsHeight
+
+	// One line is taken by the stream header
+	vpHeight := max(1, streamHeight-1)
+	if !m.ready {
+		m.viewport = viewport.New(
+			viewport.WithWidth(streamWidth),
+			viewport.WithHeight(vpHeight),
+		)
+		m.ready = true
+	} else {
+		m.viewport.SetWidth(streamWidth)
+		m.viewport.SetHeight(vpHeight)
+	}
+
+	inputWidth := max(8, (controlsWidth-24)/2)
+	for i := range m.inputs {
+		m.inputs[i].SetWidth(inputWidth)
+	}
+	m.refreshStream()
+}
+
+// Focus focuses the controls
+func (m *Model) Focus() tea.Cmd {
+	m.controlsFocused = true
+	return m.inputs[m.focusIndex].Focus()
+}
+
+// Blur blurs the controls
+func (m *Model) Blur() {
+	m.controlsFocused = false
+	for i := range m.inputs {
+		m.inputs[i].Blur()
+	}
+}
+
+// IsTyping returns true while one of the control inputs has focus
+func (m Model) IsTyping() bool {
+	return m.controlsFocused
+}
+
+// Channels returns the channels the monitor is subscribed to
+func (m Model) Channels() []string {
+	return m.channels
+}
+
+// Close closes the listener connection in the background
+func (m *Model) Close() {
+	if m.listener == nil {
+		return
+	}
+	l := m.listener
+	m.listener = nil
+	go closeListener(l)
+}
+
+// addEvent appends a notification and trims the stream to MaxEvents
+func (m *Model) addEvent(e event) {
+	atBottom := m.viewport.AtBottom()
+	m.events = append(m.events, e)
+	if len(m.events) > MaxEvents {
+		m.events = m.events[len(m.events)-MaxEvents:]
+	}
+	m.refreshStream()
+	if atBottom {
+		m.viewport.GotoBottom()
+	}
+}
+
+// setSubscribed adds or removes a channel from the subscribed list
+func (m *Model) setSubscribed(channel string, listening bool) {
+	idx := slices.Index(m.channels, channel)
+	switch {
+	case listening && idx == -1:
+		m.channels = append(m.channels, channel)
+	case !listening && idx != -1:
+		m.channels = slices.Delete(m.channels, idx, idx+1)
+	}
+}
+
+func (m *Model) setStatus(status string, isError bool) {
+	m.status = status
+	m.statusIsError = isError
+}
@@ -0,0 +1,87 @@
+package notifymonitor
+
+import (
+	"charm.land/bubbles/v2/textinput"
+	"charm.land/lipgloss/v2"
+	"github.com/SavingFrame/dbettier/internal/theme"
+)
+
+// inputStyles returns the text input styles using current theme
+func inputStyles() textinput.Styles {
+	colors := theme.Current().Colors
+	inputTextStyle := lipgloss.NewStyle().Background(colors.Surface).Foreground(colors.Text)
+	inputPlaceholderStyle := lipgloss.NewStyle().Background(colors.Surface).Foreground(colors.Muted)
+	inputPromptStyle := lipgloss.NewStyle().Background(colors.Surface).Foreground(colors.Info)
+
+	return textinput.Styles{
+		Focused: textinput.StyleState{
+			Text:        inputTextStyle,
+			Placeholder: inputPlaceholderStyle,
+			Prompt:      inputPromptStyle,
+		},
+		Blurred: textinput.StyleState{
+			Text:        inputTextStyle,
+			Placeholder: inputPlaceholderStyle,
+			Prompt:      inputPromptStyle.Foreground(colors.Muted),
+		},
+	}
+}
+
+func baseStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Background(theme.Current().Colors.Base)
+}
+
+func headerLabelStyle() lipgloss.Style {
+	return baseStyle().Foreground(theme.Current().Colors.Subtle)
+}
+
+func headerValueStyle() lipgloss.Style {
+	return baseStyle().Foreground(theme.Current().Colors.Text).Bold(true)
+}
+
+func timestampStyle() lipgloss.Style {
+	return baseStyle().Foreground(theme.Current().Colors.Muted)
+}
+
+func channelStyle() lipgloss.Style {
+	return baseStyle().Foreground(theme.Current().Colors.Teal).Bold(true)
+}
+
+func pidStyle() lipgloss.Style {
+	return baseStyle().Foreground(theme.Current().Colors.Subtle)
+}
+
+func payloadStyle() lipgloss.Style {
+	return baseStyle().Foreground(theme.Current().Colors.Text)
+}
+
+func placeholderStyle() lipgloss.Style {
+	return baseStyle().Foreground(theme.Current().Colors.Muted).Italic(true)
+}
+
+func fieldLabelStyle(focused bool) lipgloss.Style {
+	colors := theme.Current().Colors
+	style := baseStyle().Foreground(colors.Info).Bold(true)
+	if focused {
+		style = style.Foreground(colors.Primary)
+	}
+	return style
+}
+
+func fieldInputStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Background(theme.Current().Colors.Surface).
+		Padding(0, 1)
+}
+
+func hintStyle() lipgloss.Style {
+	return baseStyle().Foreground(theme.Current().Colors.Muted)
+}
+
+func statusStyle(isError bool) lipgloss.Style {
+	colors := theme.Current().Colors
+	if isError {
+		return baseStyle().Foreground(colors.Error)
+	}
+	return baseStyle().Foreground(colors.Success)
+}
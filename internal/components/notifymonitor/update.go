@@ -0,0 +1,169 @@
+package notifymonitor
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/internal/components/logpanel"
+	"github.com/SavingFrame/dbettier/internal/components/notifications"
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/SavingFrame/dbettier/internal/messages"
+)
+
+// Update handles the monitor's own messages (see TabMsg)
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ListenerConnectedMsg:
+		m.connecting = false
+		if msg.Err != nil {
+			m.setStatus("Connection failed: "+msg.Err.Error(), true)
+			return m, notifications.ShowError("Notify monitor: " + msg.Err.Error())
+		}
+		m.listener = msg.Listener
+		m.setStatus(fmt.Sprintf("Connected (PID %d)", m.listener.PID()), false)
+		// Restore subscriptions after a reconnect
+		cmds := []tea.Cmd{waitCmd(m.tabID, m.listener)}
+		for _, ch := range m.channels {
+			cmds = append(cmds, listenCmd(m.tabID, m.listener, ch, true))
+		}
+		return m, tea.Batch(cmds...)
+
+	case NotificationMsg:
+		m.addEvent(event{
+			receivedAt: msg.ReceivedAt,
+			channel:    msg.Channel,
+			payload:    msg.Payload,
+			pid:        msg.PID,
+		})
+		if m.listener == nil {
+			return m, nil
+		}
+		return m, waitCmd(m.tabID, m.listener)
+
+	case ListenerStoppedMsg:
+		if errors.Is(msg.Err, database.ErrListenerClosed) {
+			return m, nil
+		}
+		m.Close()
+		m.setStatus("Listener stopped: "+msg.Err.Error()+" (press r to reconnect)", true)
+		return m, logpanel.AddSourceLogCmd("Listener stopped: "+msg.Err.Error(), messages.LogError, m.tabName)
+
+	case SubscriptionChangedMsg:
+		statement := "LISTEN"
+		if !msg.Listening {
+			statement = "UNLISTEN"
+		}
+		if msg.Err != nil {
+			m.setStatus(statement+" failed: "+msg.Err.Error(), true)
+			return m, logpanel.AddSourceLogCmd(statement+" "+msg.Channel+" failed: "+msg.Err.Error(), messages.LogError, m.tabName)
+		}
+		m.setSubscribed(msg.Channel, msg.Listening)
+		m.setStatus(statement+" "+msg.Channel, false)
+		return m, logpanel.AddSourceLogCmd(statement+" "+msg.Channel, messages.LogSQL, m.tabName)
+
+	case NotifySentMsg:
+		if msg.Err != nil {
+			m.setStatus("NOTIFY failed: "+msg.Err.Error(), true)
+			return m, notifications.ShowError("NOTIFY failed: " + msg.Err.Error())
+		}
+		m.inputs[fieldPayload].SetValue("")
+		m.setStatus("Sent notification to "+msg.Channel, false)
+		return m, nil
+	}
+	return m, nil
+}
+
+// UpdateStream handles input while the stream (table area) is focused
+func (m Model) UpdateStream(msg tea.Msg) (Model, tea.Cmd) {
+	if !m.ready {
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, DefaultKeyMap.Top):
+			m.viewport.GotoTop()
+			return m, nil
+		case key.Matches(msg, DefaultKeyMap.Bottom):
+			m.viewport.GotoBottom()
+			return m, nil
+		case key.Matches(msg, DefaultKeyMap.Clear):
+			m.events = nil
+			m.refreshStream()
+			return m, nil
+		case key.Matches(msg, DefaultKeyMap.Reconnect):
+			if m.listener != nil || m.connecting {
+				return m, nil
+			}
+			m.connecting = true
+			m.setStatus("Connecting...", false)
+			return m, connectCmd(m.tabID, m.registry, m.databaseID)
+		}
+		m.viewport, cmd = m.viewport.Update(msg)
+	case tea.MouseWheelMsg:
+		m.viewport, cmd = m.viewport.Update(msg)
+	}
+	return m, cmd
+}
+
+// UpdateControls handles input while the controls (SQL command bar area) are focused
+func (m Model) UpdateControls(msg tea.Msg) (Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.controlsFocused {
+		switch {
+		case key.Matches(keyMsg, DefaultKeyMap.NextField):
+			return m, m.moveFocus(1)
+		case key.Matches(keyMsg, DefaultKeyMap.PrevField):
+			return m, m.moveFocus(-1)
+		case key.Matches(keyMsg, DefaultKeyMap.Submit):
+			return m, m.submit()
+		}
+	}
+
+	var cmd tea.Cmd
+	m.inputs[m.focusIndex], cmd = m.inputs[m.focusIndex].Update(msg)
+	return m, cmd
+}
+
+// moveFocus cycles focus between the control inputs
+func (m *Model) moveFocus(delta int) tea.Cmd {
+	m.inputs[m.focusIndex].Blur()
+	m.focusIndex = (m.focusIndex + controlField(delta) + fieldCount) % fieldCount
+	return m.inputs[m.focusIndex].Focus()
+}
+
+// submit runs the action for the focused input: toggling a subscription on
+// the channel field, sending a notification on the notify fields.
+func (m *Model) submit() tea.Cmd {
+	if m.listener == nil {
+		m.setStatus("Not connected", true)
+		return nil
+	}
+
+	switch m.focusIndex {
+	case fieldChannel:
+		channel := strings.TrimSpace(m.inputs[fieldChannel].Value())
+		if channel == "" {
+			return nil
+		}
+		m.inputs[fieldChannel].SetValue("")
+		listening := slices.Contains(m.channels, channel)
+		return listenCmd(m.tabID, m.listener, channel, !listening)
+	default:
+		channel := strings.TrimSpace(m.inputs[fieldNotifyChannel].Value())
+		if channel == "" {
+			// Default to the only subscribed channel, the common case when testing
+			if len(m.channels) != 1 {
+				m.setStatus("Enter a channel to notify", true)
+				return nil
+			}
+			channel = m.channels[0]
+		}
+		return notifyCmd(m.tabID, m.listener, channel, m.inputs[fieldPayload].Value())
+	}
+}
@@ -0,0 +1,98 @@
+package notifymonitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// RenderStream renders the notification stream for the tab's table area
+func (m Model) RenderStream() string {
+	if !m.ready {
+		return placeholderStyle().Render("Notify monitor")
+	}
+	return m.renderHeader() + "\n" + m.viewport.View()
+}
+
+func (m Model) renderHeader() string {
+	sep := timestampStyle().Render(" │ ")
+
+	var parts []string
+	switch {
+	case m.connecting:
+		parts = append(parts, headerLabelStyle().Render("Connecting..."))
+	case m.listener == nil:
+		parts = append(parts, statusStyle(true).Render("Disconnected"))
+	case len(m.channels) == 0:
+		parts = append(parts, headerLabelStyle().Render("Not listening to any channel"))
+	default:
+		parts = append(parts, headerLabelStyle().Render("Listening ")+headerValueStyle().Render(strings.Join(m.channels, ", ")))
+	}
+	parts = append(parts, headerLabelStyle().Render("Received ")+headerValueStyle().Render(fmt.Sprintf("%d", len(m.events))))
+
+	line := strings.Join(parts, sep)
+	return baseStyle().Width(m.streamWidth).Render(line)
+}
+
+// refreshStream rebuilds the viewport content from events
+func (m *Model) refreshStream() {
+	if !m.ready {
+		return
+	}
+	if len(m.events) == 0 {
+		m.viewport.SetContent(placeholderStyle().Render("Waiting for notifications..."))
+		return
+	}
+
+	var b strings.Builder
+	for i, e := range m.events {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(timestampStyle().Render(e.receivedAt.Format("15:04:05.000")))
+		b.WriteString(baseStyle().Render("  "))
+		b.WriteString(channelStyle().Render(e.channel))
+		b.WriteString(pidStyle().Render(fmt.Sprintf("  pid %d", e.pid)))
+		for line := range strings.SplitSeq(formatPayload(e.payload), "\n") {
+			b.WriteString("\n")
+			b.WriteString(payloadStyle().Render("  " + line))
+		}
+	}
+	m.viewport.SetContent(b.String())
+}
+
+// formatPayload pretty-prints JSON payloads and leaves anything else as is
+func formatPayload(payload string) string {
+	if payload == "" {
+		return "(empty payload)"
+	}
+	trimmed := strings.TrimSpace(payload)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return payload
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(trimmed), "", "  "); err != nil {
+		return payload
+	}
+	return buf.String()
+}
+
+// RenderControls renders the subscribe/notify form for the SQL command bar area
+func (m Model) RenderControls() string {
+	field := func(f controlField, label string) string {
+		focused := m.controlsFocused && m.focusIndex == f
+		return fieldLabelStyle(focused).Render(label) + fieldInputStyle().Render(m.inputs[f].View())
+	}
+	space := baseStyle().Render("  ")
+
+	lines := []string{
+		field(fieldChannel, " Listen  ") + space + hintStyle().Render("enter: listen / unlisten"),
+		field(fieldNotifyChannel, " Notify  ") + space + field(fieldPayload, "Payload ") + space + hintStyle().Render("enter: send"),
+		"",
+		statusStyle(m.statusIsError).Render(" " + m.status),
+	}
+
+	content := strings.Join(lines, "\n")
+	return baseStyle().Width(max(0, m.controlsWidth)).Render(content)
+}
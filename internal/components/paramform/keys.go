@@ -0,0 +1,49 @@
+package paramform
+
+import "charm.land/bubbles/v2/key"
+
+// KeyMap defines keybindings for the parameter form
+type KeyMap struct {
+	NextField  key.Binding
+	PrevField  key.Binding
+	ToggleNull key.Binding
+	Submit     key.Binding
+	Cancel     key.Binding
+}
+
+// DefaultKeyMap returns the default keybindings for the parameter form
+var DefaultKeyMap = KeyMap{
+	NextField: key.NewBinding(
+		key.WithKeys("tab", "down"),
+		key.WithHelp("tab", "next parameter"),
+	),
+	PrevField: key.NewBinding(
+		key.WithKeys("shift+tab", "up"),
+		key.WithHelp("shift+tab", "prev parameter"),
+	),
+	ToggleNull: key.NewBinding(
+		key.WithKeys("ctrl+n"),
+		key.WithHelp("ctrl+n", "toggle NULL"),
+	),
+	Submit: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "execute"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "cancel"),
+	),
+}
+
+// ShortHelp returns keybindings for the short help view
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Submit, k.NextField, k.ToggleNull, k.Cancel}
+}
+
+// FullHelp returns keybindings for the expanded help view
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.NextField, k.PrevField},
+		{k.ToggleNull, k.Submit, k.Cancel},
+	}
+}
@@ -0,0 +1,86 @@
+// Package paramform provides the popup that asks for bind parameter values
+// before a statement with placeholders is executed.
+package paramform
+
+import (
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/internal/messages"
+	"github.com/SavingFrame/dbettier/internal/query"
+)
+
+// inputWidth is the visible width of each value input
+const inputWidth = 40
+
+// Model is the state of the parameter form
+type Model struct {
+	request messages.QueryParamsRequestMsg
+	inputs  []textinput.Model
+	nulls   []bool
+	focus   int
+	closed  bool
+}
+
+// New creates a form for the request, prefilled with the values last used
+// for the same statement.
+func New(req messages.QueryParamsRequestMsg) Model {
+	labels := req.Query.Params.Labels
+	last, known := query.LastParamValues(req.Query.Query, labels)
+
+	m := Model{
+		request: req,
+		inputs:  make([]textinput.Model, len(labels)),
+		nulls:   make([]bool, len(labels)),
+	}
+	for i := range labels {
+		t := textinput.New()
+		t.Prompt = ""
+		t.CharLimit = 0
+		t.SetWidth(inputWidth)
+		t.SetStyles(inputStyles())
+		if i < len(req.Types) && req.Types[i] != "" {
+			t.Placeholder = req.Types[i]
+		}
+		if known[i] {
+			if last[i] == nil {
+				m.nulls[i] = true
+			} else {
+				t.SetValue(*last[i])
+			}
+		}
+		m.inputs[i] = t
+	}
+	return m
+}
+
+// Init focuses the first input
+func (m *Model) Init() tea.Cmd {
+	return m.setFocus(0)
+}
+
+// Closed reports whether the form was submitted or cancelled
+func (m Model) Closed() bool {
+	return m.closed
+}
+
+func (m *Model) setFocus(i int) tea.Cmd {
+	if len(m.inputs) == 0 {
+		return nil
+	}
+	m.inputs[m.focus].Blur()
+	m.focus = (i + len(m.inputs)) % len(m.inputs)
+	return m.inputs[m.focus].Focus()
+}
+
+// values returns the entered values, nil meaning NULL
+func (m Model) values() []*string {
+	values := make([]*string, len(m.inputs))
+	for i, input := range m.inputs {
+		if m.nulls[i] {
+			continue
+		}
+		v := input.Value()
+		values[i] = &v
+	}
+	return values
+}
@@ -0,0 +1,73 @@
+package paramform
+
+import (
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/internal/components/logpanel"
+	"github.com/SavingFrame/dbettier/internal/messages"
+	"github.com/SavingFrame/dbettier/internal/query"
+)
+
+// Update handles key input for the form
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		if len(m.inputs) == 0 {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+		return m, cmd
+	}
+
+	switch {
+	case key.Matches(keyMsg, DefaultKeyMap.Cancel):
+		m.closed = true
+		return m, logpanel.AddLogCmd("Query execution cancelled", messages.LogInfo)
+	case key.Matches(keyMsg, DefaultKeyMap.Submit):
+		return m.submit()
+	case key.Matches(keyMsg, DefaultKeyMap.NextField):
+		return m, m.setFocus(m.focus + 1)
+	case key.Matches(keyMsg, DefaultKeyMap.PrevField):
+		return m, m.setFocus(m.focus - 1)
+	case key.Matches(keyMsg, DefaultKeyMap.ToggleNull):
+		if len(m.nulls) > 0 {
+			m.nulls[m.focus] = !m.nulls[m.focus]
+		}
+		return m, nil
+	}
+
+	if len(m.inputs) == 0 {
+		return m, nil
+	}
+	// Typing into a NULL field replaces the NULL with a value
+	m.nulls[m.focus] = false
+	var cmd tea.Cmd
+	m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	return m, cmd
+}
+
+// submit binds the entered values to the query and executes it
+func (m Model) submit() (Model, tea.Cmd) {
+	m.closed = true
+	q := m.request.Query
+	values := m.values()
+	query.RememberParamValues(q.Query, q.Params.Labels, values)
+
+	args := make([]any, len(values))
+	for i, v := range values {
+		if v != nil {
+			args[i] = *v
+		}
+	}
+	q.SetArgs(args)
+
+	req := m.request
+	return m, func() tea.Msg {
+		return messages.ExecuteQueryParamsMsg{
+			Query:      req.Query,
+			DatabaseID: req.DatabaseID,
+			TabID:      req.TabID,
+		}
+	}
+}
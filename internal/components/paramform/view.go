@@ -0,0 +1,78 @@
+package paramform
+
+import (
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// maxQueryLines limits how much of the statement is shown above the inputs
+const maxQueryLines = 4
+
+// View renders the form as a popup
+func (m Model) View() string {
+	labels := m.request.Query.Params.Labels
+
+	labelWidth := 0
+	for _, label := range labels {
+		labelWidth = max(labelWidth, lipgloss.Width(label))
+	}
+	typeWidth := 0
+	for _, t := range m.request.Types {
+		typeWidth = max(typeWidth, lipgloss.Width(t))
+	}
+
+	rows := []string{titleStyle().Render("Query parameters"), ""}
+	rows = append(rows, m.renderQuery()...)
+	rows = append(rows, "")
+
+	for i, label := range labels {
+		focused := i == m.focus
+		marker := "  "
+		if focused {
+			marker = "▸ "
+		}
+		typeName := ""
+		if i < len(m.request.Types) {
+			typeName = m.request.Types[i]
+		}
+
+		var value string
+		if m.nulls[i] {
+			value = nullStyle().Width(inputWidth).Render("NULL")
+		} else {
+			value = m.inputs[i].View()
+		}
+
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top,
+			labelStyle(focused).Render(marker),
+			labelStyle(focused).Width(labelWidth+2).Render(label),
+			typeStyle().Width(typeWidth+2).Render(typeName),
+			fieldStyle().Render(value),
+		))
+	}
+
+	rows = append(rows, "", hintStyle().Render("enter execute • tab next • ctrl+n NULL • esc cancel"))
+	return popupStyle().Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+// renderQuery renders the first lines of the statement for context
+func (m Model) renderQuery() []string {
+	lines := strings.Split(strings.TrimSpace(m.request.Query.Query), "\n")
+	truncated := len(lines) > maxQueryLines
+	if truncated {
+		lines = lines[:maxQueryLines]
+	}
+	width := inputWidth + 24
+	out := make([]string, 0, len(lines)+1)
+	for _, line := range lines {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		line = ansi.Truncate(line, width, "…")
+		out = append(out, queryStyle().Render(line))
+	}
+	if truncated {
+		out = append(out, queryStyle().Render("…"))
+	}
+	return out
+}
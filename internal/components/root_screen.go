@@ -9,13 +9,19 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/SavingFrame/dbettier/internal/components/dbtree"
+	"github.com/SavingFrame/dbettier/internal/components/importwizard"
 	"github.com/SavingFrame/dbettier/internal/components/logpanel"
 	"github.com/SavingFrame/dbettier/internal/components/notifications"
+	"github.com/SavingFrame/dbettier/internal/components/notifymonitor"
+	"github.com/SavingFrame/dbettier/internal/components/paramform"
 	sharedcomponents "github.com/SavingFrame/dbettier/internal/components/shared_components"
 	"github.com/SavingFrame/dbettier/internal/components/statusbar"
+	"github.com/SavingFrame/dbettier/internal/components/tableview"
 	"github.com/SavingFrame/dbettier/internal/components/workspace"
 	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/SavingFrame/dbettier/internal/messages"
 	"github.com/SavingFrame/dbettier/internal/theme"
+	"github.com/SavingFrame/dbettier/pkgs/clipboard"
 	zone "github.com/lrstanley/bubblezone/v2"
 )
 
@@ -62,6 +68,8 @@ type rootScreenModel struct {
 	// State
 	focusedPane  FocusedPane
 	notification *notifications.Notification
+	paramForm    *paramform.Model
+	importWizard *importwizard.Model
 	width        int
 	height       int
 	layout       rootLayout
@@ -195,8 +203,29 @@ func (m rootScreenModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.notification = nil
 		return m, nil
 
+	case clipboard.CopiedMsg:
+		if msg.Err != nil {
+			return m, notifications.ShowError("Copy failed: " + msg.Err.Error())
+		}
+		return m, nil
+
+	case messages.QueryParamsRequestMsg:
+		form := paramform.New(msg)
+		cmd = form.Init()
+		m.paramForm = &form
+		return m, cmd
+
+	case messages.OpenImportMsg:
+		wizard := importwizard.New(msg)
+		cmd = wizard.Init()
+		m.importWizard = &wizard
+		return m, cmd
+
+	case messages.ImportProgressMsg:
+		return m, importwizard.HandleProgress(msg)
+
 	case tea.MouseReleaseMsg:
-		if msg.Button != tea.MouseLeft {
+		if msg.Button != tea.MouseLeft || m.paramForm != nil || m.importWizard != nil {
 			return m, nil
 		}
 
@@ -224,6 +253,24 @@ func (m rootScreenModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		// The parameter form is modal and takes all key input while open
+		if m.paramForm != nil {
+			form, formCmd := m.paramForm.Update(msg)
+			m.paramForm = &form
+			if form.Closed() {
+				m.paramForm = nil
+			}
+			return m, formCmd
+		}
+		if m.importWizard != nil {
+			wizard, wizardCmd := m.importWizard.Update(msg)
+			m.importWizard = &wizard
+			if wizard.Closed() {
+				m.importWizard = nil
+			}
+			return m, wizardCmd
+		}
+
 		// Handle help toggle first
 		if key.Matches(msg, m.keys.Help) {
 			m.help.ShowAll = !m.help.ShowAll
@@ -255,6 +302,9 @@ func (m rootScreenModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			m.focusedPane = paneOrder[(oldFocusIndex+1)%len(paneOrder)]
+			if oldFocus == FocusSQLCommandBar {
+				m.workspace.Blur()
+			}
 			if m.focusedPane == FocusSQLCommandBar {
 				return m, m.workspace.Focus()
 			}
@@ -282,6 +332,16 @@ func (m rootScreenModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 	default:
+		if m.paramForm != nil {
+			form, formCmd := m.paramForm.Update(msg)
+			m.paramForm = &form
+			cmds = append(cmds, formCmd)
+		}
+		if m.importWizard != nil {
+			wizard, wizardCmd := m.importWizard.Update(msg)
+			m.importWizard = &wizard
+			cmds = append(cmds, wizardCmd)
+		}
 		routedCmds := m.routeToComponents(msg)
 		if len(routedCmds) > 0 {
 			return m, tea.Batch(routedCmds...)
@@ -415,6 +475,13 @@ func (m rootScreenModel) View() tea.View {
 
 	fullView := lipgloss.JoinVertical(lipgloss.Left, baseView, shortHelpView, statusBarView)
 
+	if m.paramForm != nil && m.width > 0 && m.height > 0 {
+		fullView = m.renderWithPopup(fullView, m.paramForm.View())
+	}
+	if m.importWizard != nil && m.width > 0 && m.height > 0 {
+		fullView = m.renderWithPopup(fullView, m.importWizard.View())
+	}
+
 	// If full help is toggled, render it as a centered popup overlay
 	if m.help.ShowAll && m.width > 0 && m.height > 0 {
 		fullView = m.renderWithHelpPopup(fullView)
@@ -465,8 +532,13 @@ func (m rootScreenModel) renderWithHelpPopup(baseView string) string {
 	title := titleStyle.Render("Keyboard Shortcuts")
 	helpPopup := popupStyle.Render(lipgloss.JoinVertical(lipgloss.Left, title, fullHelpContent))
 
-	popupWidth := lipgloss.Width(helpPopup)
-	popupHeight := lipgloss.Height(helpPopup)
+	return m.renderWithPopup(baseView, helpPopup)
+}
+
+// renderWithPopup composites popup centered over baseView
+func (m rootScreenModel) renderWithPopup(baseView, popup string) string {
+	popupWidth := lipgloss.Width(popup)
+	popupHeight := lipgloss.Height(popup)
 	x := (m.width - popupWidth) / 2
 	y := (m.height - popupHeight) / 2
 	x = max(0, x)
@@ -474,7 +546,7 @@ func (m rootScreenModel) renderWithHelpPopup(baseView string) string {
 
 	compositor := lipgloss.NewCompositor(
 		lipgloss.NewLayer(baseView),
-		lipgloss.NewLayer(helpPopup).X(x).Y(y),
+		lipgloss.NewLayer(popup).X(x).Y(y),
 	)
 
 	return compositor.Render()
@@ -622,6 +694,19 @@ func (m rootScreenModel) getContextKeyMap() combinedKeyMap {
 		global: m.keys,
 	}
 
+	if m.paramForm != nil {
+		keys := paramform.DefaultKeyMap
+		combined.paneKeys = keys.ShortHelp()
+		combined.fullPaneKeys = keys.FullHelp()
+		return combined
+	}
+	if m.importWizard != nil {
+		keys := importwizard.DefaultKeyMap
+		combined.paneKeys = keys.ShortHelp()
+		combined.fullPaneKeys = keys.FullHelp()
+		return combined
+	}
+
 	// Tab keys are always available
 	tabKeys := workspace.DefaultKeyMap
 
@@ -632,14 +717,21 @@ func (m rootScreenModel) getContextKeyMap() combinedKeyMap {
 		combined.paneKeys = append(combined.paneKeys, tabKeys.ShortHelp()...)
 		combined.fullPaneKeys = keys.FullHelp()
 		combined.fullPaneKeys = append(combined.fullPaneKeys, tabKeys.FullHelp()...)
-	case FocusTableView:
-		// Combine tableview keys with tab navigation keys
-		combined.paneKeys = tabKeys.ShortHelp()
-		combined.fullPaneKeys = tabKeys.FullHelp()
-	case FocusSQLCommandBar:
-		// Combine sqlcommandbar keys with tab navigation keys
+	case FocusTableView, FocusSQLCommandBar:
+		if tab := m.workspace.ActiveTab(); tab != nil && tab.Type == workspace.TabTypeNotify {
+			keys := notifymonitor.DefaultKeyMap
+			combined.paneKeys = keys.ShortHelp()
+			combined.paneKeys = append(combined.paneKeys, tabKeys.ShortHelp()...)
+			combined.fullPaneKeys = keys.FullHelp()
+			combined.fullPaneKeys = append(combined.fullPaneKeys, tabKeys.FullHelp()...)
+			break
+		}
+		// Combine tableview/sqlcommandbar keys with tab navigation keys
 		combined.paneKeys = tabKeys.ShortHelp()
 		combined.fullPaneKeys = tabKeys.FullHelp()
+		if m.focusedPane == FocusTableView {
+			combined.fullPaneKeys = append(tableview.DefaultKeyMap.FullHelp(), combined.fullPaneKeys...)
+		}
 	case FocusLogPanel:
 		keys := logpanel.DefaultKeyMap
 		combined.paneKeys = keys.ShortHelp()
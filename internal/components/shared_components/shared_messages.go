@@ -27,7 +27,34 @@ var MessageRoutes = map[string]ComponentTarget{
 	"editor.EditorModeChangedMsg":     TargetStatusBar,
 	"editor.EditorCursorMovedMsg":     TargetStatusBar,
 	"messages.OpenQueryTabMsg":        TargetWorkspace,
-	"query.UpdateTableMsg":            TargetTableView,
+	"query.UpdateTableMsg":            TargetWorkspace,
+	"query.FetchingRowsMsg":           TargetWorkspace,
+	"query.FetchFailedMsg":            TargetWorkspace,
+	"query.SortFilterFailedMsg":       TargetWorkspace,
+	"messages.QueryNoticesMsg":        TargetWorkspace,
+	"messages.OpenNotifyTabMsg":       TargetWorkspace,
+	"messages.ExecuteQueryParamsMsg":  TargetWorkspace,
+	"messages.CountRowsMsg":           TargetWorkspace,
+	"messages.RowCountMsg":            TargetWorkspace,
+	"messages.ApplyEditsMsg":          TargetWorkspace,
+	"messages.EditsAppliedMsg":        TargetWorkspace,
+	"messages.CSVEditedMsg":           TargetWorkspace,
+	"messages.FollowReferenceMsg":     TargetWorkspace,
+	"messages.ReferencesMsg":          TargetWorkspace,
+	"messages.JumpToTableMsg":         TargetWorkspace,
+	"messages.JumpHistoryMsg":         TargetWorkspace,
+	"messages.LoadLabelsMsg":          TargetWorkspace,
+	"messages.LabelsLoadedMsg":        TargetWorkspace,
+	"messages.ColumnStatsMsg":         TargetWorkspace,
+	"messages.ColumnStatsLoadedMsg":   TargetWorkspace,
+	"messages.ExportMsg":              TargetWorkspace,
+	"messages.ExportProgressMsg":      TargetWorkspace,
+
+	"notifymonitor.ListenerConnectedMsg":   TargetWorkspace,
+	"notifymonitor.NotificationMsg":        TargetWorkspace,
+	"notifymonitor.ListenerStoppedMsg":     TargetWorkspace,
+	"notifymonitor.SubscriptionChangedMsg": TargetWorkspace,
+	"notifymonitor.NotifySentMsg":          TargetWorkspace,
 }
 
 func GetMessageType(msg tea.Msg) string {
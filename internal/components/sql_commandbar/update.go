@@ -45,7 +45,7 @@ func (m SQLCommandBarModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 
 	case query.SQLResultMsg:
-		m.textarea.SetValue(msg.Query.Compile())
+		m.textarea.SetValue(query.DisplayText(msg.Query))
 		m.query = msg.Query
 		return m, nil
 	case tea.KeyMsg:
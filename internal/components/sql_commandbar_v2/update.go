@@ -23,7 +23,7 @@ func (m SQLCommandBarModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 	case query.SQLResultMsg:
-		m.SetContent(msg.Query.Compile())
+		m.SetContent(query.DisplayText(msg.Query))
 	}
 	m.editor, cmd = m.editor.Update(msg)
 	cmds = append(cmds, cmd)
@@ -0,0 +1,202 @@
+package tableview
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/SavingFrame/dbettier/internal/components/notifications"
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/SavingFrame/dbettier/internal/query"
+	"github.com/SavingFrame/dbettier/pkgs/chart"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// chartView plots numeric columns of the loaded rows against another
+// column, in place of the grid
+type chartView struct {
+	kind chart.Kind
+	// x is the column along the X axis, y the columns plotted against it
+	x int
+	y []int
+	// cursor is the column focused in the bar of columns
+	cursor int
+	// data is the chart of the loaded rows, built again when they or the
+	// columns plotted change
+	data chart.Chart
+}
+
+// toggleChart switches between the grid and a chart of the loaded rows.
+// The X axis starts on the first date or text column and the Y axis on
+// the first numeric column.
+func (m *TableViewModel) toggleChart() tea.Cmd {
+	if m.chart != nil {
+		m.chart = nil
+		return nil
+	}
+	if !m.data.HasQuery() || m.data.Query().GetSQLResult() == nil {
+		return nil
+	}
+	columns := m.data.Query().GetSQLResult().Columns
+	x := slices.IndexFunc(columns, database.ResultColumn.IsTime)
+	if x < 0 {
+		x = max(0, slices.IndexFunc(columns, func(c database.ResultColumn) bool { return !c.IsNumeric() }))
+	}
+	y := -1
+	for i, c := range columns {
+		if i != x && c.IsNumeric() {
+			y = i
+			break
+		}
+	}
+	if y < 0 {
+		return notifications.ShowWarning("There is no numeric column to plot")
+	}
+	_, focused := m.table.FocusedPosition()
+	m.record = nil
+	m.chart = &chartView{x: x, y: []int{y}, cursor: min(focused, len(columns)-1)}
+	m.refreshChart()
+	return nil
+}
+
+// updateChart handles a key in the chart view. It reports false for keys
+// left to the table view, such as quitting.
+func (m *TableViewModel) updateChart(msg tea.KeyMsg) (tea.Cmd, bool) {
+	v := m.chart
+	columns := m.data.Query().GetSQLResult().Columns
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Chart, DefaultKeyMap.Escape):
+		m.chart = nil
+	case key.Matches(msg, DefaultKeyMap.Quit):
+		return nil, false
+	case msg.String() == "c" || msg.String() == "tab":
+		v.kind = chart.Kinds[(slices.Index(chart.Kinds, v.kind)+1)%len(chart.Kinds)]
+		v.data.Kind = v.kind
+	case msg.String() == "h" || msg.String() == "left":
+		v.cursor = max(0, v.cursor-1)
+	case msg.String() == "l" || msg.String() == "right":
+		v.cursor = min(len(columns)-1, v.cursor+1)
+	case msg.String() == "x":
+		v.x = v.cursor
+		v.y = slices.DeleteFunc(v.y, func(i int) bool { return i == v.x })
+		m.refreshChart()
+	case msg.String() == "space" || msg.String() == "y":
+		switch {
+		case slices.Contains(v.y, v.cursor):
+			v.y = slices.DeleteFunc(v.y, func(i int) bool { return i == v.cursor })
+		case !columns[v.cursor].IsNumeric():
+			return notifications.ShowWarning("Only numeric columns can be plotted"), true
+		case v.cursor != v.x:
+			v.y = append(v.y, v.cursor)
+			slices.Sort(v.y)
+		}
+		m.refreshChart()
+	}
+	return nil, true
+}
+
+// refreshChart builds the chart again from the loaded rows, and leaves the
+// chart view when its columns are gone
+func (m *TableViewModel) refreshChart() {
+	v := m.chart
+	if v == nil {
+		return
+	}
+	columns := m.data.Query().GetSQLResult().Columns
+	if v.x >= len(columns) || slices.ContainsFunc(v.y, func(i int) bool { return i >= len(columns) }) {
+		m.chart = nil
+		return
+	}
+	rows := m.data.Rows()
+	xcol := columns[v.x]
+	v.data = chart.Chart{
+		Kind:     v.kind,
+		XKind:    chart.XCategory,
+		Labels:   make([]string, len(rows)),
+		Location: query.Display.Location,
+	}
+	switch {
+	case xcol.IsTime():
+		v.data.XKind = chart.XTime
+	case xcol.IsNumeric():
+		v.data.XKind = chart.XNumber
+	}
+	if v.data.XKind != chart.XCategory {
+		v.data.X = make([]float64, len(rows))
+	}
+	for i, row := range rows {
+		value := row[v.x]
+		v.data.Labels[i] = query.FormatCell(value, xcol)
+		switch v.data.XKind {
+		case chart.XTime:
+			v.data.X[i] = math.NaN()
+			if t, ok := value.(time.Time); ok {
+				v.data.X[i] = float64(t.UnixNano()) / 1e9
+			}
+		case chart.XNumber:
+			v.data.X[i] = chartValue(value)
+		}
+	}
+	for _, col := range v.y {
+		series := chart.Series{Name: columns[col].Name, Values: make([]float64, len(rows))}
+		for i, row := range rows {
+			series.Values[i] = chartValue(row[col])
+		}
+		v.data.Series = append(v.data.Series, series)
+	}
+}
+
+// chartValue returns a number to plot, or NaN for NULL and other values
+func chartValue(v any) float64 {
+	if f, ok := query.NumberValue(v); ok {
+		return f
+	}
+	return math.NaN()
+}
+
+// chartTitles name the kinds of charts
+var chartTitles = map[chart.Kind]string{
+	chart.Line:      "Line chart",
+	chart.Bar:       "Bar chart",
+	chart.Histogram: "Histogram of the first Y column",
+}
+
+// renderChart draws the chart view: a title, the bar of columns and the
+// chart under them
+func (m TableViewModel) renderChart(width, height int) string {
+	v := m.chart
+	columns := m.data.Query().GetSQLResult().Columns
+	title := ansi.Truncate(reviewTitleStyle().Render(chartTitles[v.kind])+
+		reviewDimStyle().Render("  c kind · h/l column · x set X · space toggle Y · P close"), width, "…")
+
+	// The bar of columns scrolls to keep the cursor in view
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		name := c.Name
+		switch {
+		case i == v.x:
+			name = "X " + name
+		case slices.Contains(v.y, i):
+			name = "Y " + name
+		}
+		style := reviewDimStyle()
+		switch {
+		case i == v.cursor:
+			style = referenceSelectedStyle()
+		case i == v.x || slices.Contains(v.y, i):
+			style = reviewTitleStyle()
+		}
+		names[i] = style.Render(" " + name + " ")
+	}
+	start := 0
+	for start < v.cursor && lipgloss.Width(strings.Join(names[start:v.cursor+1], " ")) > width {
+		start++
+	}
+	bar := ansi.Truncate(strings.Join(names[start:], " "), width, "…")
+
+	return title + "\n" + bar + "\n" + v.data.Render(width, max(1, height-2))
+}
@@ -1,17 +1,19 @@
 package tableview
 
 import (
-	"fmt"
+	"slices"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/internal/database"
 	"github.com/SavingFrame/dbettier/internal/query"
 	"github.com/SavingFrame/dbettier/pkgs/table"
-	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const (
 	minColWidth = 3
 	maxColWidth = 50
+	// widthSampleRows is the number of rows measured to size the columns
+	widthSampleRows = 200
 )
 
 // DataState manages query results and table data
@@ -19,6 +21,16 @@ type DataState struct {
 	query         query.ExecutableQuery
 	databaseID    string
 	canFetchTotal bool
+	// inserted are rows written from this tab, shown after the page until
+	// the next result after the one they were saved before
+	inserted    [][]any
+	insertedKey func([]any) string
+	keepInsert  bool
+	// labels resolve foreign keys of a table tab to a label of the
+	// referenced row, shown next to the key while showLabels is set; nil
+	// until the foreign keys were looked up
+	labels     []query.LabelLookup
+	showLabels bool
 }
 
 func (d *DataState) Query() query.ExecutableQuery {
@@ -58,6 +70,24 @@ func (d *DataState) PageOffset() int {
 	return d.query.PageOffset()
 }
 
+// Total returns the total row count of the result and whether it is an
+// estimate. total is -1 when unknown.
+func (d *DataState) Total() (total int, estimate bool) {
+	if d.query == nil || d.query.GetSQLResult() == nil {
+		return -1, false
+	}
+	result := d.query.GetSQLResult()
+	return result.Total, result.TotalIsEstimate
+}
+
+// SortFilterMode returns where the current result is sorted and filtered
+func (d *DataState) SortFilterMode() query.SortFilterMode {
+	if d.query == nil {
+		return query.SortFilterServer
+	}
+	return d.query.SortFilterMode()
+}
+
 func (d *DataState) GetSortOrders() query.OrderByClauses {
 	if d.query == nil {
 		return nil
@@ -66,68 +96,193 @@ func (d *DataState) GetSortOrders() query.OrderByClauses {
 }
 
 func (d *DataState) SetFromSQLResult(msg query.SQLResultMsg) *query.SQLResult {
+	if msg.Query != d.query {
+		d.inserted = nil
+		d.keepInsert = false
+		d.labels = nil
+		d.showLabels = false
+	}
 	d.query = msg.Query
 	d.databaseID = msg.DatabaseID
-	return msg.Query.SetSQLResult(&msg)
+	result := msg.Query.SetSQLResult(&msg)
+	d.dropInserted()
+	return result
+}
+
+// KeepInserted shows rows just inserted from this tab after the next page
+// read, unless they are on it. key identifies a row.
+func (d *DataState) KeepInserted(rows [][]any, key func([]any) string) {
+	d.inserted = rows
+	d.insertedKey = key
+	d.keepInsert = len(rows) > 0
+}
+
+// dropInserted forgets inserted rows after the result read following their
+// save, and drops those the result shows anyway
+func (d *DataState) dropInserted() {
+	if !d.keepInsert {
+		d.inserted = nil
+		return
+	}
+	d.keepInsert = false
+	onPage := map[string]bool{}
+	for _, row := range d.query.Rows() {
+		onPage[d.insertedKey(row)] = true
+	}
+	d.inserted = slices.DeleteFunc(d.inserted, func(row []any) bool { return onPage[d.insertedKey(row)] })
+}
+
+// Rows returns the rows shown in the grid: the current page followed by
+// rows just inserted from this tab
+func (d *DataState) Rows() [][]any {
+	if d.query == nil || d.query.GetSQLResult() == nil {
+		return nil
+	}
+	if len(d.inserted) == 0 {
+		return d.query.Rows()
+	}
+	return append(slices.Clip(d.query.Rows()), d.inserted...)
+}
+
+// Close releases the server-side cursor of a streamed result, if any
+func (d *DataState) Close() {
+	if bq, ok := d.query.(*query.BasicSQLQuery); ok {
+		bq.Close()
+	}
 }
 
 func (d *DataState) SetQuery(query query.ExecutableQuery) {
+	if query != d.query {
+		d.inserted = nil
+		d.keepInsert = false
+		d.labels = nil
+		d.showLabels = false
+	}
 	d.query = query
 }
 
-func (d *DataState) BuildTableData(result *query.SQLResult) ([]table.Column, []table.Row) {
+// BuildTableData sets up the current page for the table. Its cells are
+// formatted as they are shown; columns are made wide enough for their
+// header and the values of the first widthSampleRows rows.
+func (d *DataState) BuildTableData(result *query.SQLResult, showTypes bool) ([]table.Column, *pageSource) {
 	if result == nil || d.query == nil {
 		return nil, nil
 	}
 
-	// Calculate column sizes based on data
+	rows := d.Rows()
+	source := &pageSource{rows: rows, columns: result.Columns, label: d.label}
+
+	// Calculate column sizes based on the sampled rows
 	colSizes := make([]int, len(result.Columns))
 	for i := range colSizes {
 		colSizes[i] = minColWidth
 	}
-
-	var rows []table.Row
-	for _, rowData := range d.query.Rows() {
-		var rowCells []string
-		for cellIdx, cell := range rowData {
-			text := formatCellValue(cell)
-			rowCells = append(rowCells, text)
-			if len(text) > colSizes[cellIdx] {
-				colSizes[cellIdx] = len(text)
+	for rowIdx := range min(len(rows), widthSampleRows) {
+		for colIdx := range colSizes {
+			text := source.Cell(rowIdx, colIdx)
+			if text == table.Null {
+				text = query.Display.NullText
 			}
+			colSizes[colIdx] = max(colSizes[colIdx], table.TextWidth(text))
 		}
-		rows = append(rows, table.Row(rowCells))
 	}
 
 	// Build columns with calculated widths
 	var columns []table.Column
-	for colIdx, colName := range result.Columns {
-		width := max(colSizes[colIdx], len(colName)) + 5
+	for colIdx, col := range result.Columns {
+		header := col.Name
+		if showTypes && col.TypeName != "" {
+			header += " " + col.TypeName
+		}
+		width := max(colSizes[colIdx], table.TextWidth(header)) + 5
 		width = min(width, maxColWidth)
 		width = max(width, minColWidth)
 
 		columns = append(columns, table.Column{
-			Title: colName,
-			Width: width,
+			Title:      col.Name,
+			Width:      width,
+			Type:       col.TypeName,
+			AlignRight: col.IsNumeric(),
 		})
 	}
 
 	d.canFetchTotal = result.CanFetchTotal
 
-	return columns, rows
+	return columns, source
+}
+
+// CopySource returns the text copied from the cells of the current page: in
+// PostgreSQL's text format as when editing, without labels
+func (d *DataState) CopySource(result *query.SQLResult) table.RowSource {
+	if result == nil || d.query == nil {
+		return nil
+	}
+	rows := d.Rows()
+	return table.NewCachedSource(len(rows), len(result.Columns), func(row, col int) string {
+		if col >= len(rows[row]) || rows[row][col] == nil {
+			return table.Null
+		}
+		return query.EditText(rows[row][col], result.Columns[col])
+	})
+}
+
+// pageSource formats the cells of the current page for the grid, with the
+// staged changes over them
+type pageSource struct {
+	rows    [][]any
+	columns []database.ResultColumn
+	label   func(row []any, col int) (string, bool)
+	// edited holds the new text of edited cells, and inserted the rows
+	// added after the page
+	edited   map[table.Cell]string
+	inserted []table.Row
+}
+
+func (s *pageSource) Len() int {
+	return len(s.rows) + len(s.inserted)
 }
 
-func formatCellValue(cell any) string {
-	switch v := cell.(type) {
-	case pgtype.Numeric:
-		val, err := v.Value()
-		if err != nil {
-			return fmt.Sprintf("ERR: %v", err)
+func (s *pageSource) Cell(row, col int) string {
+	if row >= len(s.rows) {
+		if inserted := s.inserted[row-len(s.rows)]; col < len(inserted) {
+			return inserted[col]
+		}
+		return ""
+	}
+	if text, ok := s.edited[table.Cell{Row: row, Col: col}]; ok {
+		return text
+	}
+	rowData := s.rows[row]
+	if col >= len(rowData) {
+		return ""
+	}
+	if rowData[col] == nil {
+		return table.Null
+	}
+	text := query.FormatCell(rowData[col], s.columns[col])
+	if label, ok := s.label(rowData, col); ok {
+		text += " → " + label
+	}
+	return text
+}
+
+// cached returns the source as shown in the grid, where each cell is
+// formatted once
+func (s *pageSource) cached() table.RowSource {
+	return table.NewCachedSource(s.Len(), len(s.columns), s.Cell)
+}
+
+// label returns the label shown next to a foreign key cell
+func (d *DataState) label(row []any, col int) (string, bool) {
+	if !d.showLabels {
+		return "", false
+	}
+	for i := range d.labels {
+		if d.labels[i].Column == col {
+			return d.labels[i].Label(row)
 		}
-		return fmt.Sprintf("%v", val)
-	default:
-		return fmt.Sprintf("%v", v)
 	}
+	return "", false
 }
 
 func (d *DataState) HandleSortChange(columns []table.Column, sortOrders []table.OrderCol) query.OrderByClauses {
@@ -0,0 +1,535 @@
+package tableview
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/SavingFrame/dbettier/internal/components/notifications"
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/SavingFrame/dbettier/internal/messages"
+	"github.com/SavingFrame/dbettier/internal/query"
+	"github.com/SavingFrame/dbettier/pkgs/table"
+)
+
+// cellEditor edits the focused cell in place: a text input for most types,
+// a picker for enums and booleans
+type cellEditor struct {
+	row, col int
+	column   database.ResultColumn
+	input    textinput.Model
+	// options are the values to pick from, nil for free text
+	options []string
+	option  int
+	null    bool
+}
+
+func newCellEditor(row, col int, column database.ResultColumn, value any, staged *query.CellEdit, width int) (*cellEditor, tea.Cmd) {
+	e := &cellEditor{row: row, col: col, column: column, options: query.EditOptions(column)}
+
+	text, null := query.EditText(value, column), value == nil
+	if staged != nil {
+		null = staged.Value == nil
+		text = ""
+		if staged.Value != nil {
+			text = *staged.Value
+		}
+	}
+	e.null = null
+
+	e.input = textinput.New()
+	e.input.Prompt = ""
+	e.input.SetStyles(editorInputStyles())
+	e.input.SetWidth(max(1, width-1))
+	e.input.SetValue(text)
+	e.input.CursorEnd()
+
+	if i := slices.Index(e.options, text); i >= 0 {
+		e.option = i
+	}
+	return e, e.input.Focus()
+}
+
+// value returns the edited value, nil for NULL
+func (e *cellEditor) value() *string {
+	if e.null {
+		return nil
+	}
+	v := e.input.Value()
+	if e.options != nil {
+		v = e.options[e.option]
+	}
+	return &v
+}
+
+// blink passes a non-key message, such as the cursor blinking, to the text
+// input
+func (e *cellEditor) blink(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	e.input, cmd = e.input.Update(msg)
+	return cmd
+}
+
+// update handles a key. done is set when editing ends; staged tells whether
+// the value is to be kept.
+func (e *cellEditor) update(msg tea.KeyMsg) (cmd tea.Cmd, done, staged bool) {
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Escape):
+		return nil, true, false
+	case key.Matches(msg, DefaultKeyMap.Enter):
+		if v := e.value(); v != nil {
+			if err := query.ValidateInput(e.column, *v); err != nil {
+				return notifications.ShowError(err.Error()), false, false
+			}
+		}
+		return nil, true, true
+	case key.Matches(msg, DefaultKeyMap.ToggleNull):
+		if !e.null && e.column.NotNull {
+			return notifications.ShowWarning(fmt.Sprintf("Column %q is NOT NULL", e.column.Name)), false, false
+		}
+		e.null = !e.null
+		return nil, false, false
+	}
+
+	if e.options != nil {
+		switch msg.String() {
+		case "down", "tab", "j":
+			e.option = (e.option + 1) % len(e.options)
+			e.null = false
+		case "up", "shift+tab", "k":
+			e.option = (e.option + len(e.options) - 1) % len(e.options)
+			e.null = false
+		default:
+			// jump to the next option starting with the typed letter
+			typed := strings.ToLower(msg.String())
+			for i := 1; i <= len(e.options); i++ {
+				j := (e.option + i) % len(e.options)
+				if strings.HasPrefix(strings.ToLower(e.options[j]), typed) {
+					e.option = j
+					e.null = false
+					break
+				}
+			}
+		}
+		return nil, false, false
+	}
+
+	e.null = false
+	e.input, cmd = e.input.Update(msg)
+	return cmd, false, false
+}
+
+// view renders the editor for drawing in the cell
+func (e *cellEditor) view() string {
+	switch {
+	case e.null:
+		return editorNullStyle().Render(query.Display.NullText)
+	case e.options != nil:
+		return editorPickerStyle().Render("‹ " + e.options[e.option] + " ›")
+	}
+	return e.input.View()
+}
+
+// editReview lists the pending UPDATEs before they are saved
+type editReview struct {
+	lines  []string
+	offset int
+}
+
+// editSet returns the tab's staged changes, creating them on first use if
+// the tab's table can be written back to
+func (m *TableViewModel) editSet() (*query.EditSet, tea.Cmd) {
+	if m.edits != nil {
+		return m.edits, nil
+	}
+	tq, ok := m.data.Query().(*query.TableQuery)
+	if !ok {
+		return nil, notifications.ShowWarning("Only table tabs can be edited")
+	}
+	target, err := tq.EditTarget()
+	if err != nil {
+		return nil, notifications.ShowWarning("Cannot edit this table: " + err.Error())
+	}
+	m.edits = query.NewEditSet(target)
+	return m.edits, nil
+}
+
+// focusedInsert returns the index of the new row focused in the grid, if a
+// new row is focused. New rows follow the rows read from the database.
+func (m *TableViewModel) focusedInsert() (int, bool) {
+	row, _ := m.table.FocusedPosition()
+	i := row - len(m.data.Rows())
+	return i, m.edits != nil && i >= 0 && i < len(m.edits.Inserts())
+}
+
+// startEdit opens the editor on the focused cell
+func (m *TableViewModel) startEdit() tea.Cmd {
+	edits, cmd := m.editSet()
+	if edits == nil {
+		return cmd
+	}
+	row, col := m.table.FocusedPosition()
+	column := edits.Target().Column(col)
+	width := m.table.FocusedCellWidth()
+
+	if i, ok := m.focusedInsert(); ok {
+		// A column left to its default starts out empty
+		empty := ""
+		staged := &query.CellEdit{Column: col, Value: &empty}
+		if v, set := edits.Inserts()[i].Values[col]; set {
+			staged.Value = v
+		}
+		m.editor, cmd = newCellEditor(row, col, column, nil, staged, width)
+	} else {
+		rows := m.data.Rows()
+		if row >= len(rows) || col >= len(rows[row]) {
+			return nil
+		}
+		var staged *query.CellEdit
+		if e, ok := edits.Lookup(rows[row], col); ok {
+			staged = &e
+		}
+		m.editor, cmd = newCellEditor(row, col, column, rows[row][col], staged, width)
+	}
+	m.table.SetCellEditor(m.editor.view())
+	return cmd
+}
+
+// updateEditor passes a key to the cell editor and stages its value when
+// it is confirmed
+func (m *TableViewModel) updateEditor(msg tea.KeyMsg) tea.Cmd {
+	cmd, done, staged := m.editor.update(msg)
+	if !done {
+		m.table.SetCellEditor(m.editor.view())
+		return cmd
+	}
+	if staged {
+		if i, ok := m.focusedInsert(); ok {
+			m.edits.SetInsertValue(i, m.editor.col, m.editor.value())
+		} else {
+			m.edits.Set(m.data.Rows()[m.editor.row], m.editor.col, m.editor.value())
+		}
+	}
+	m.editor = nil
+	m.table.ClearCellEditor()
+	m.refreshEdits()
+	return cmd
+}
+
+// revertCell drops the staged edit of the focused cell, or leaves a new
+// row's cell to its default
+func (m *TableViewModel) revertCell() {
+	if m.edits == nil {
+		return
+	}
+	row, col := m.table.FocusedPosition()
+	if i, ok := m.focusedInsert(); ok {
+		m.edits.ResetInsertValue(i, col)
+	} else if rows := m.data.Rows(); row < len(rows) {
+		m.edits.Revert(rows[row], col)
+	}
+	m.refreshEdits()
+}
+
+// addRow stages a new row with every column at its default and focuses it
+func (m *TableViewModel) addRow() tea.Cmd {
+	edits, cmd := m.editSet()
+	if edits == nil {
+		return cmd
+	}
+	m.focusInsert(edits.Insert(nil))
+	return nil
+}
+
+// duplicateRow stages a copy of the focused row as a new row and focuses it
+func (m *TableViewModel) duplicateRow() tea.Cmd {
+	edits, cmd := m.editSet()
+	if edits == nil {
+		return cmd
+	}
+	row, _ := m.table.FocusedPosition()
+	var values map[int]*string
+	if i, ok := m.focusedInsert(); ok {
+		values = maps.Clone(edits.Inserts()[i].Values)
+	} else if rows := m.data.Rows(); row < len(rows) {
+		values = edits.DuplicateRow(rows[row])
+	} else {
+		return nil
+	}
+	m.focusInsert(edits.Insert(values))
+	return nil
+}
+
+// focusInsert shows the staged rows and focuses new row i
+func (m *TableViewModel) focusInsert(i int) {
+	m.refreshEdits()
+	_, col := m.table.FocusedPosition()
+	m.table.SetCursor(len(m.data.Rows())+i, col)
+}
+
+// toggleDelete marks the focused row for deletion, or unmarks it. A new
+// row is dropped instead.
+func (m *TableViewModel) toggleDelete() tea.Cmd {
+	edits, cmd := m.editSet()
+	if edits == nil {
+		return cmd
+	}
+	row, _ := m.table.FocusedPosition()
+	if i, ok := m.focusedInsert(); ok {
+		edits.RemoveInsert(i)
+	} else if rows := m.data.Rows(); row < len(rows) {
+		edits.ToggleDelete(rows[row])
+	}
+	m.refreshEdits()
+	return nil
+}
+
+// bulkEdit writes the grid's rows to a CSV file and opens it in the
+// user's editor. The changes made there are staged when it exits.
+func (m *TableViewModel) bulkEdit() tea.Cmd {
+	edits, cmd := m.editSet()
+	if edits == nil {
+		return cmd
+	}
+	if edits.Len() > 0 {
+		return notifications.ShowWarning("Save or discard the pending changes first")
+	}
+	f, err := os.CreateTemp("", "dbettier-*.csv")
+	if err != nil {
+		return notifications.ShowError("Could not create a file to edit: " + err.Error())
+	}
+	err = edits.Target().WriteCSV(f, m.data.Rows())
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return notifications.ShowError("Could not write the rows to edit: " + err.Error())
+	}
+
+	editor := strings.Fields(externalEditor())
+	c := exec.Command(editor[0], append(editor[1:], f.Name())...)
+	tq := m.data.Query().(*query.TableQuery)
+	path := f.Name()
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return messages.CSVEditedMsg{Query: tq, Path: path, Err: err}
+	})
+}
+
+// externalEditor returns the user's editor command from $VISUAL or $EDITOR
+func externalEditor() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+			return v
+		}
+	}
+	return "vi"
+}
+
+// handleCSVEdited stages the changes made to the CSV file and shows them
+// for review
+func (m *TableViewModel) handleCSVEdited(msg messages.CSVEditedMsg) tea.Cmd {
+	defer func() { _ = os.Remove(msg.Path) }()
+	if msg.Err != nil {
+		return notifications.ShowError("The editor failed: " + msg.Err.Error())
+	}
+	if m.edits == nil {
+		return nil
+	}
+	f, err := os.Open(msg.Path)
+	if err != nil {
+		return notifications.ShowError("Could not read the edited rows: " + err.Error())
+	}
+	defer func() { _ = f.Close() }()
+	if err := m.edits.ApplyCSV(m.data.Rows(), f); err != nil {
+		return notifications.ShowError("Could not apply the edited rows: " + err.Error())
+	}
+	if m.edits.Len() == 0 {
+		return notifications.ShowInfo("No changes")
+	}
+	m.refreshEdits()
+	m.openReview()
+	return nil
+}
+
+// discardEdits drops all staged changes
+func (m *TableViewModel) discardEdits() tea.Cmd {
+	if m.edits == nil || m.edits.Len() == 0 {
+		return nil
+	}
+	n := m.edits.Len()
+	m.edits.Clear()
+	m.refreshEdits()
+	return notifications.ShowInfo(fmt.Sprintf("Discarded %d changes", n))
+}
+
+// resetEdits forgets the staged edits and any open editor, for when
+// another query is shown
+func (m *TableViewModel) resetEdits() {
+	m.edits = nil
+	m.editor = nil
+	m.review = nil
+	m.saving = false
+	m.table.ClearCellEditor()
+	m.table.SetModifiedCells(nil)
+	m.table.SetDeletedRows(nil)
+}
+
+// openReview shows the pending UPDATEs
+func (m *TableViewModel) openReview() {
+	if m.edits == nil || m.edits.Len() == 0 {
+		return
+	}
+	m.review = &editReview{lines: m.edits.Preview()}
+}
+
+// updateReview handles keys in the review popup; enter saves the edits
+func (m *TableViewModel) updateReview(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Escape), key.Matches(msg, DefaultKeyMap.ReviewEdits):
+		m.review = nil
+	case key.Matches(msg, DefaultKeyMap.Enter):
+		return m.saveEdits()
+	case msg.String() == "j" || msg.String() == "down":
+		m.review.offset = min(m.review.offset+1, max(0, len(m.review.lines)-1))
+	case msg.String() == "k" || msg.String() == "up":
+		m.review.offset = max(0, m.review.offset-1)
+	}
+	return nil
+}
+
+// saveEdits asks the workspace to run the staged edits in one transaction
+func (m *TableViewModel) saveEdits() tea.Cmd {
+	tq, ok := m.data.Query().(*query.TableQuery)
+	if !ok || m.edits == nil || m.edits.Len() == 0 || m.saving {
+		return nil
+	}
+	m.saving = true
+	msg := messages.ApplyEditsMsg{Query: tq, DatabaseID: m.data.DatabaseID(), Statements: m.edits.Statements()}
+	return func() tea.Msg { return msg }
+}
+
+// handleEditsApplied clears the saved changes and reloads the rows, keeping
+// the inserted ones in view, or keeps the changes to retry when saving
+// failed
+func (m *TableViewModel) handleEditsApplied(msg messages.EditsAppliedMsg) tea.Cmd {
+	m.saving = false
+	if msg.Err != nil || m.edits == nil {
+		return nil
+	}
+	m.review = nil
+	m.data.KeepInserted(msg.Inserted, m.edits.Target().RowKey)
+	m.edits.Clear()
+	m.refreshEdits()
+	return m.data.RefreshQuery()
+}
+
+// refreshEdits redraws the grid's rows with the staged changes
+func (m *TableViewModel) refreshEdits() {
+	if !m.data.HasQuery() || m.data.Query().GetSQLResult() == nil {
+		return
+	}
+	_, source := m.data.BuildTableData(m.data.Query().GetSQLResult(), m.table.ShowTypes())
+	m.table.SetRowSource(m.overlayEdits(source))
+	m.refreshRecordView()
+}
+
+// overlayEdits shows the staged changes in the page: edited cells get
+// their new values, rows to delete are struck through and new rows are
+// added at the end, with their defaults where no value was given
+func (m *TableViewModel) overlayEdits(source *pageSource) table.RowSource {
+	if m.edits == nil || m.edits.Len() == 0 {
+		m.table.SetModifiedCells(nil)
+		m.table.SetDeletedRows(nil)
+		return source.cached()
+	}
+	var modified []table.Cell
+	var deleted []int
+	source.edited = make(map[table.Cell]string)
+	for i, row := range source.rows {
+		if m.edits.Deleted(row) {
+			deleted = append(deleted, i)
+		}
+		for _, e := range m.edits.RowEdits(row) {
+			cell := table.Cell{Row: i, Col: e.Column}
+			source.edited[cell] = cellText(e.Value)
+			modified = append(modified, cell)
+		}
+	}
+
+	target := m.edits.Target()
+	columns := len(source.columns)
+	for _, insert := range m.edits.Inserts() {
+		i := source.Len()
+		row := make(table.Row, columns)
+		for col := range row {
+			if v, ok := insert.Values[col]; ok {
+				row[col] = cellText(v)
+			} else if expr, ok := target.Default(col); ok {
+				row[col] = "DEFAULT " + expr
+			} else {
+				row[col] = table.Null
+			}
+			modified = append(modified, table.Cell{Row: i, Col: col})
+		}
+		source.inserted = append(source.inserted, row)
+	}
+	m.table.SetModifiedCells(modified)
+	m.table.SetDeletedRows(deleted)
+	return source.cached()
+}
+
+// cellText returns the grid text of a staged value, nil for NULL
+func cellText(v *string) string {
+	if v == nil {
+		return table.Null
+	}
+	return *v
+}
+
+// pendingEdits returns the number of staged changes
+func (m TableViewModel) pendingEdits() int {
+	if m.edits == nil {
+		return 0
+	}
+	return m.edits.Len()
+}
+
+// renderReview draws the review popup centered over content
+func (m TableViewModel) renderReview(content string) string {
+	width := max(20, m.viewport.Width()*9/10)
+	height := max(3, m.viewport.Height()-4)
+	bodyWidth, bodyHeight := width-4, height-4
+
+	var body []string
+	for _, line := range m.review.lines[m.review.offset:] {
+		body = append(body, strings.Split(lipgloss.Wrap(line, bodyWidth, " ,"), "\n")...)
+		if len(body) >= bodyHeight {
+			break
+		}
+	}
+	body = body[:min(len(body), bodyHeight)]
+
+	title := reviewTitleStyle().Render(fmt.Sprintf("%d pending changes", m.edits.Len()))
+	footer := reviewDimStyle().Render("enter save · j/k scroll · esc close")
+	if m.saving {
+		footer = reviewDimStyle().Render("saving…")
+	}
+	popup := reviewStyle().Width(width).Height(height).Render(
+		title + "\n\n" + strings.Join(body, "\n") + strings.Repeat("\n", bodyHeight-len(body)+1) + footer)
+
+	base := lipgloss.NewStyle().Width(m.viewport.Width()).Height(m.viewport.Height()).Render(content)
+	x := max(0, (lipgloss.Width(base)-lipgloss.Width(popup))/2)
+	y := max(0, (lipgloss.Height(base)-lipgloss.Height(popup))/2)
+	return lipgloss.NewCompositor(
+		lipgloss.NewLayer(base),
+		lipgloss.NewLayer(popup).X(x).Y(y),
+	).Render()
+}
@@ -0,0 +1,304 @@
+package tableview
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/SavingFrame/dbettier/internal/components/notifications"
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/SavingFrame/dbettier/internal/export"
+	"github.com/SavingFrame/dbettier/internal/messages"
+	"github.com/SavingFrame/dbettier/internal/query"
+)
+
+// exportField is a field of the export form
+type exportField int
+
+const (
+	exportFieldFormat exportField = iota
+	exportFieldRows
+	exportFieldPath
+	exportFieldDelimiter
+	exportFieldHeader
+	exportFieldNull
+	exportFieldTable
+	exportFieldCount
+)
+
+// exportForm asks where and how to export the result
+type exportForm struct {
+	format int
+	header bool
+	// all re-runs the query for every row instead of writing the page shown
+	all   bool
+	focus exportField
+	// inputs holds the text fields, by exportField
+	inputs map[exportField]*textinput.Model
+}
+
+func newExportForm(columns []database.ResultColumn) (*exportForm, tea.Cmd) {
+	f := &exportForm{header: true, inputs: map[exportField]*textinput.Model{}}
+	name, table := "result", "exported_rows"
+	if schema, t, ok := resultTable(columns); ok {
+		name = t
+		table = query.QualifiedName(schema, t)
+	}
+	defaults := map[exportField]string{
+		exportFieldPath:      name + "-" + time.Now().Format("20060102-150405") + export.CSV.Extension(),
+		exportFieldDelimiter: ",",
+		exportFieldNull:      "",
+		exportFieldTable:     table,
+	}
+	for field, value := range defaults {
+		input := textinput.New()
+		input.Prompt = ""
+		input.SetStyles(editorInputStyles())
+		input.SetWidth(40)
+		input.SetValue(value)
+		input.CursorEnd()
+		f.inputs[field] = &input
+	}
+	return f, f.setFocus(exportFieldFormat)
+}
+
+// resultTable returns the table all columns of a result were read from
+func resultTable(columns []database.ResultColumn) (schema, table string, ok bool) {
+	for _, col := range columns {
+		if !col.FromTable() || (table != "" && (col.TableSchema != schema || col.TableName != table)) {
+			return "", "", false
+		}
+		schema, table = col.TableSchema, col.TableName
+	}
+	return schema, table, table != ""
+}
+
+func (f *exportForm) selected() export.Format {
+	return export.Formats[f.format]
+}
+
+// visible reports whether a field applies to the selected format
+func (f *exportForm) visible(field exportField) bool {
+	switch field {
+	case exportFieldDelimiter:
+		return f.selected() == export.CSV
+	case exportFieldHeader:
+		return f.selected().UsesHeader()
+	case exportFieldNull:
+		return f.selected().UsesNullText()
+	case exportFieldTable:
+		return f.selected() == export.SQL
+	}
+	return true
+}
+
+// setFocus moves the focus to a field, focusing its text input if it has one
+func (f *exportForm) setFocus(field exportField) tea.Cmd {
+	f.focus = field
+	var cmd tea.Cmd
+	for name, input := range f.inputs {
+		if name == field {
+			cmd = input.Focus()
+		} else {
+			input.Blur()
+		}
+	}
+	return cmd
+}
+
+// move focuses the next or previous visible field
+func (f *exportForm) move(step int) tea.Cmd {
+	field := f.focus
+	for {
+		field = (field + exportField(step) + exportFieldCount) % exportFieldCount
+		if f.visible(field) {
+			return f.setFocus(field)
+		}
+	}
+}
+
+// cycle changes the choice of the focused field
+func (f *exportForm) cycle(step int) {
+	switch f.focus {
+	case exportFieldFormat:
+		old := f.selected()
+		f.format = (f.format + step + len(export.Formats)) % len(export.Formats)
+		path := f.inputs[exportFieldPath]
+		if strings.HasSuffix(path.Value(), old.Extension()) {
+			path.SetValue(strings.TrimSuffix(path.Value(), old.Extension()) + f.selected().Extension())
+			path.CursorEnd()
+		}
+	case exportFieldRows:
+		f.all = !f.all
+	case exportFieldHeader:
+		f.header = !f.header
+	}
+}
+
+// blink passes a non-key message, such as the cursor blinking, to the
+// focused text input
+func (f *exportForm) blink(msg tea.Msg) tea.Cmd {
+	input, ok := f.inputs[f.focus]
+	if !ok {
+		return nil
+	}
+	var cmd tea.Cmd
+	*input, cmd = input.Update(msg)
+	return cmd
+}
+
+// options returns the export options filled in, or an error to show
+func (f *exportForm) options() (export.Options, error) {
+	opts := export.Options{
+		Format:   f.selected(),
+		Header:   f.header,
+		NullText: f.inputs[exportFieldNull].Value(),
+		Table:    strings.TrimSpace(f.inputs[exportFieldTable].Value()),
+	}
+	delimiter := f.inputs[exportFieldDelimiter].Value()
+	if delimiter == `\t` {
+		delimiter = "\t"
+	}
+	if opts.Format == export.CSV {
+		r, size := utf8.DecodeRuneInString(delimiter)
+		if size == 0 || size != len(delimiter) || r == '"' || r == '\r' || r == '\n' {
+			return opts, fmt.Errorf("the delimiter must be one character other than a quote or line break")
+		}
+		opts.Delimiter = r
+	}
+	if opts.Format == export.SQL && opts.Table == "" {
+		return opts, fmt.Errorf("name the table to INSERT into")
+	}
+	return opts, nil
+}
+
+// openExport shows the export form
+func (m *TableViewModel) openExport() tea.Cmd {
+	if !m.data.HasQuery() || m.data.Query().GetSQLResult() == nil {
+		return nil
+	}
+	var cmd tea.Cmd
+	m.export, cmd = newExportForm(m.data.Query().GetSQLResult().Columns)
+	return cmd
+}
+
+// updateExport handles keys in the export form; enter starts the export
+func (m *TableViewModel) updateExport(msg tea.KeyMsg) tea.Cmd {
+	f := m.export
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Escape):
+		m.export = nil
+		return nil
+	case key.Matches(msg, DefaultKeyMap.Enter):
+		return m.startExport()
+	}
+	switch msg.String() {
+	case "tab", "down":
+		return f.move(1)
+	case "shift+tab", "up":
+		return f.move(-1)
+	}
+	if input, ok := f.inputs[f.focus]; ok {
+		var cmd tea.Cmd
+		*input, cmd = input.Update(msg)
+		return cmd
+	}
+	switch msg.String() {
+	case "right", "l", "space", " ":
+		f.cycle(1)
+	case "left", "h":
+		f.cycle(-1)
+	}
+	return nil
+}
+
+// startExport asks the workspace to write the result as set up in the
+// export form
+func (m *TableViewModel) startExport() tea.Cmd {
+	f := m.export
+	opts, err := f.options()
+	if err != nil {
+		return notifications.ShowError(err.Error())
+	}
+	path := strings.TrimSpace(f.inputs[exportFieldPath].Value())
+	if path == "" {
+		return notifications.ShowError("name the file to export to")
+	}
+	msg := messages.ExportMsg{
+		DatabaseID: m.data.DatabaseID(),
+		Columns:    m.data.Query().GetSQLResult().Columns,
+		Path:       path,
+		Options:    opts,
+	}
+	switch q := m.data.Query().(type) {
+	case *query.TableQuery:
+		if f.all {
+			msg.SQL = q.UnpagedQuery()
+		}
+	case *query.BasicSQLQuery:
+		if f.all {
+			if rows, ok := q.CompleteRows(); ok {
+				msg.Rows = rows
+			} else {
+				msg.SQL, msg.Args = q.Compile(), q.Args()
+			}
+		}
+	}
+	if msg.SQL == "" && msg.Rows == nil {
+		msg.Rows = slices.Clone(m.data.Rows())
+	}
+	m.export = nil
+	return func() tea.Msg { return msg }
+}
+
+// renderExport draws the export form centered over content
+func (m TableViewModel) renderExport(content string) string {
+	f := m.export
+	label := func(field exportField, text string) string {
+		style := reviewDimStyle()
+		if f.focus == field {
+			style = reviewTitleStyle()
+		}
+		return style.Width(12).Render(text)
+	}
+	choice := func(text string) string {
+		return editorPickerStyle().Render("‹ " + text + " ›")
+	}
+	yesNo := map[bool]string{true: "yes", false: "no"}
+	rows := map[bool]string{false: "shown page", true: "all rows"}
+	fields := []struct {
+		field exportField
+		name  string
+		value string
+	}{
+		{exportFieldFormat, "Format", choice(f.selected().String())},
+		{exportFieldRows, "Rows", choice(rows[f.all])},
+		{exportFieldPath, "File", f.inputs[exportFieldPath].View()},
+		{exportFieldDelimiter, "Delimiter", f.inputs[exportFieldDelimiter].View()},
+		{exportFieldHeader, "Header", choice(yesNo[f.header])},
+		{exportFieldNull, "NULL as", f.inputs[exportFieldNull].View()},
+		{exportFieldTable, "Table", f.inputs[exportFieldTable].View()},
+	}
+	lines := []string{reviewTitleStyle().Render("Export result"), ""}
+	for _, field := range fields {
+		if f.visible(field.field) {
+			lines = append(lines, label(field.field, field.name)+field.value)
+		}
+	}
+	lines = append(lines, "", reviewDimStyle().Render("enter export · tab/↑↓ move · ←/→ change · esc close"))
+	popup := reviewStyle().Render(strings.Join(lines, "\n"))
+
+	base := lipgloss.NewStyle().Width(m.viewport.Width()).Height(m.viewport.Height()).Render(content)
+	x := max(0, (lipgloss.Width(base)-lipgloss.Width(popup))/2)
+	y := max(0, (lipgloss.Height(base)-lipgloss.Height(popup))/2)
+	return lipgloss.NewCompositor(
+		lipgloss.NewLayer(base),
+		lipgloss.NewLayer(popup).X(x).Y(y),
+	).Render()
+}
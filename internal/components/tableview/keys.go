@@ -9,6 +9,34 @@ type KeyMap struct {
 	NextPage     key.Binding
 	PreviousPage key.Binding
 	Escape       key.Binding
+	CountRows    key.Binding
+	JumpToPage   key.Binding
+	ToggleTypes  key.Binding
+	Inspect      key.Binding
+	ToggleRecord key.Binding
+	PrevRecord   key.Binding
+	NextRecord   key.Binding
+	EditCell     key.Binding
+	ToggleNull   key.Binding
+	RevertCell   key.Binding
+	DiscardEdits key.Binding
+	ReviewEdits  key.Binding
+	AddRow       key.Binding
+	DuplicateRow key.Binding
+	DeleteRow    key.Binding
+	BulkEdit     key.Binding
+	FollowRef    key.Binding
+	ReferencedBy key.Binding
+	JumpBack     key.Binding
+	JumpForward  key.Binding
+	ToggleLabels key.Binding
+	Export       key.Binding
+	Select       key.Binding
+	Yank         key.Binding
+	Columns      key.Binding
+	Resize       key.Binding
+	Stats        key.Binding
+	Chart        key.Binding
 }
 
 // DefaultKeyMap returns the default keybindings for the table view
@@ -33,6 +61,118 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("esc"),
 		key.WithHelp("esc", "clear"),
 	),
+	CountRows: key.NewBinding(
+		key.WithKeys("#"),
+		key.WithHelp("#", "count rows/cancel"),
+	),
+	JumpToPage: key.NewBinding(
+		key.WithKeys(":"),
+		key.WithHelp(":", "jump to page"),
+	),
+	ToggleTypes: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "show/hide column types"),
+	),
+	Inspect: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "inspect cell"),
+	),
+	ToggleRecord: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "record/grid view"),
+	),
+	PrevRecord: key.NewBinding(
+		key.WithKeys("h", "left"),
+		key.WithHelp("h", "previous row (record view)"),
+	),
+	NextRecord: key.NewBinding(
+		key.WithKeys("l", "right"),
+		key.WithHelp("l", "next row (record view)"),
+	),
+	EditCell: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "edit cell"),
+	),
+	ToggleNull: key.NewBinding(
+		key.WithKeys("ctrl+n"),
+		key.WithHelp("ctrl+n", "set/unset NULL (editing)"),
+	),
+	RevertCell: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "revert cell"),
+	),
+	DiscardEdits: key.NewBinding(
+		key.WithKeys("U"),
+		key.WithHelp("U", "discard all changes"),
+	),
+	ReviewEdits: key.NewBinding(
+		key.WithKeys("W"),
+		key.WithHelp("W", "review and save changes"),
+	),
+	AddRow: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "add row"),
+	),
+	DuplicateRow: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "duplicate row"),
+	),
+	DeleteRow: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "delete/undelete row"),
+	),
+	BulkEdit: key.NewBinding(
+		key.WithKeys("E"),
+		key.WithHelp("E", "edit page as CSV in $EDITOR"),
+	),
+	FollowRef: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "open referenced row"),
+	),
+	ReferencedBy: key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "open rows referencing this row"),
+	),
+	JumpBack: key.NewBinding(
+		key.WithKeys("["),
+		key.WithHelp("[", "back along jumps"),
+	),
+	JumpForward: key.NewBinding(
+		key.WithKeys("]"),
+		key.WithHelp("]", "forward along jumps"),
+	),
+	ToggleLabels: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "show/hide foreign key labels"),
+	),
+	Export: key.NewBinding(
+		key.WithKeys("X"),
+		key.WithHelp("X", "export result to a file"),
+	),
+	Select: key.NewBinding(
+		key.WithKeys("v", "V", "ctrl+v"),
+		key.WithHelp("v/V/ctrl+v", "select cells/rows/columns"),
+	),
+	Yank: key.NewBinding(
+		key.WithKeys("y", "Y"),
+		key.WithHelp("y/Y", "copy cell/copy selection as..."),
+	),
+	Columns: key.NewBinding(
+		key.WithKeys("C"),
+		key.WithHelp("C", "hide/reorder/freeze columns"),
+	),
+	Resize: key.NewBinding(
+		key.WithKeys("<", ">", "="),
+		key.WithHelp("</>/=", "narrow/widen/fit column"),
+	),
+	Stats: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "column statistics"),
+	),
+	Chart: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "chart/grid view"),
+	),
 }
 
 // ShortHelp returns keybindings for the short help view
@@ -43,7 +183,16 @@ func (k KeyMap) ShortHelp() []key.Binding {
 // FullHelp returns keybindings for the expanded help view
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.NextPage, k.PreviousPage},
+		{k.NextPage, k.PreviousPage, k.JumpToPage},
+		{k.CountRows, k.ToggleTypes, k.Inspect, k.Stats},
+		{k.Select, k.Yank},
+		{k.Columns, k.Resize},
+		{k.ToggleRecord, k.PrevRecord, k.NextRecord, k.Chart},
+		{k.EditCell, k.ToggleNull, k.RevertCell},
+		{k.AddRow, k.DuplicateRow, k.DeleteRow},
+		{k.DiscardEdits, k.ReviewEdits, k.BulkEdit},
+		{k.FollowRef, k.ReferencedBy, k.ToggleLabels},
+		{k.JumpBack, k.JumpForward, k.Export},
 		{k.Quit},
 	}
 }
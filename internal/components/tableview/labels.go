@@ -0,0 +1,103 @@
+package tableview
+
+import (
+	"maps"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/internal/components/notifications"
+	"github.com/SavingFrame/dbettier/internal/messages"
+	"github.com/SavingFrame/dbettier/internal/query"
+)
+
+// toggleLabels shows or hides the labels of foreign key cells
+func (m *TableViewModel) toggleLabels() tea.Cmd {
+	if !m.data.IsTableQuery() {
+		return notifications.ShowWarning("Foreign key labels are shown in table tabs only")
+	}
+	m.data.showLabels = !m.data.showLabels
+	m.rebuildGrid()
+	return m.loadLabels()
+}
+
+// loadLabels asks for the labels of the keys on the page that were not
+// looked up yet, and for the foreign keys to label on first use
+func (m *TableViewModel) loadLabels() tea.Cmd {
+	tq, ok := m.data.Query().(*query.TableQuery)
+	if !ok || !m.data.showLabels || tq.GetSQLResult() == nil {
+		return nil
+	}
+	rows := m.data.Rows()
+	var lookups []query.LabelLookup
+	if m.data.labels != nil {
+		// The workspace gets the lookups without their labels, which only
+		// this model touches
+		lookups = make([]query.LabelLookup, len(m.data.labels))
+		for i, l := range m.data.labels {
+			l.Labels = nil
+			lookups[i] = l
+		}
+		var missing [][]any
+		for _, row := range rows {
+			for i := range m.data.labels {
+				if len(m.data.labels[i].MissingKeys([][]any{row})) > 0 {
+					missing = append(missing, row)
+					break
+				}
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		rows = missing
+	}
+	msg := messages.LoadLabelsMsg{
+		Query:      tq,
+		DatabaseID: m.data.DatabaseID(),
+		Columns:    tq.GetSQLResult().Columns,
+		Rows:       rows,
+		Lookups:    lookups,
+	}
+	return func() tea.Msg { return msg }
+}
+
+// handleLabelsLoaded adds the labels read to those known and shows them
+func (m *TableViewModel) handleLabelsLoaded(msg messages.LabelsLoadedMsg) tea.Cmd {
+	if msg.Query != m.data.Query() {
+		return nil
+	}
+	if msg.Err != nil {
+		if m.data.labels == nil {
+			m.data.showLabels = false
+		}
+		return nil
+	}
+	if msg.Lookups != nil {
+		m.data.labels = msg.Lookups
+		if len(m.data.labels) == 0 {
+			m.data.showLabels = false
+			return notifications.ShowInfo("No foreign keys here reference a table with a label column")
+		}
+	}
+	for i, labels := range msg.Labels {
+		if i >= len(m.data.labels) {
+			break
+		}
+		if m.data.labels[i].Labels == nil {
+			m.data.labels[i].Labels = map[string]string{}
+		}
+		maps.Copy(m.data.labels[i].Labels, labels)
+	}
+	m.rebuildGrid()
+	return nil
+}
+
+// rebuildGrid formats the result again, resizing the columns to fit
+func (m *TableViewModel) rebuildGrid() {
+	if !m.data.HasQuery() || m.data.Query().GetSQLResult() == nil {
+		return
+	}
+	columns, source := m.data.BuildTableData(m.data.Query().GetSQLResult(), m.table.ShowTypes())
+	m.table.SetColumns(columns)
+	m.table.SetRowSource(m.overlayEdits(source))
+	m.refreshRecordView()
+}
@@ -0,0 +1,128 @@
+package tableview
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/internal/components/notifications"
+	"github.com/SavingFrame/dbettier/internal/query"
+	"github.com/SavingFrame/dbettier/pkgs/table"
+	zone "github.com/lrstanley/bubblezone/v2"
+)
+
+// Column layouts of tables, by database and table, kept in layoutsPath
+// between runs
+var (
+	layouts     = make(map[string]table.Layout)
+	layoutsPath string
+	layoutsMu   sync.Mutex
+)
+
+// LoadLayouts reads the saved column layouts of tables from a JSON file,
+// and saves changed layouts to it. A missing file is not an error.
+func LoadLayouts(path string) error {
+	layoutsMu.Lock()
+	defer layoutsMu.Unlock()
+	layoutsPath = path
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return json.Unmarshal(file, &layouts)
+}
+
+// layoutKey returns the key of a table's layout, or "" if the result shown
+// is not read from a single table
+func (m TableViewModel) layoutKey(result *query.SQLResult) string {
+	if result == nil {
+		return ""
+	}
+	schema, t, ok := resultTable(result.Columns)
+	if !ok {
+		return ""
+	}
+	return m.data.DatabaseID() + "/" + query.QualifiedName(schema, t)
+}
+
+// savedLayout returns the saved layout of a table, empty if there is none
+func savedLayout(key string) table.Layout {
+	layoutsMu.Lock()
+	defer layoutsMu.Unlock()
+	return layouts[key]
+}
+
+// saveLayout remembers the layout of a table and writes all layouts to the
+// file they were loaded from
+func saveLayout(key string, layout table.Layout) tea.Cmd {
+	if key == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		layoutsMu.Lock()
+		defer layoutsMu.Unlock()
+		if layout.Empty() {
+			delete(layouts, key)
+		} else {
+			layouts[key] = layout
+		}
+		if layoutsPath == "" {
+			return nil
+		}
+		data, err := json.MarshalIndent(layouts, "", "  ")
+		if err == nil {
+			err = os.WriteFile(layoutsPath, data, 0o644)
+		}
+		if err != nil {
+			return notifications.ShowError("Cannot save the column layout: " + err.Error())()
+		}
+		return nil
+	}
+}
+
+// columnDrag is a column being resized by dragging its header's right edge
+type columnDrag struct {
+	col   int
+	x     int
+	width int
+}
+
+// startDrag starts resizing a column if the click is on the edge of a
+// column in the grid's header
+func (m *TableViewModel) startDrag(msg tea.MouseClickMsg) {
+	if msg.Button != tea.MouseLeft || m.record != nil {
+		return
+	}
+	// The header is its titles and the line under them
+	x, y := zone.Get("grid").Pos(msg)
+	if y < 0 || y > 1 {
+		return
+	}
+	if col, ok := m.table.ColumnEdgeAt(x); ok {
+		m.drag = &columnDrag{col: col, x: msg.Mouse().X, width: m.table.ColumnWidth(col)}
+	}
+}
+
+// updateDrag resizes the dragged column to follow the mouse
+func (m *TableViewModel) updateDrag(msg tea.MouseMotionMsg) {
+	if m.drag != nil {
+		m.table.SetColumnWidth(m.drag.col, m.drag.width+msg.Mouse().X-m.drag.x)
+	}
+}
+
+// endDrag ends resizing a column and saves the new width
+func (m *TableViewModel) endDrag() tea.Cmd {
+	if m.drag == nil {
+		return nil
+	}
+	m.drag = nil
+	if !m.data.HasQuery() {
+		return nil
+	}
+	return saveLayout(m.layoutKey(m.data.Query().GetSQLResult()), m.table.Layout())
+}
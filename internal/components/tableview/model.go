@@ -2,8 +2,11 @@
 package tableview
 
 import (
+	"context"
+
 	"charm.land/bubbles/v2/spinner"
 	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/internal/query"
 	"github.com/SavingFrame/dbettier/pkgs/table"
 )
 
@@ -14,6 +17,37 @@ type TableViewModel struct {
 	table     table.Model
 	spinner   spinner.Model
 	isLoading bool
+	// fetchedRows is the number of rows read so far while a streamed
+	// result fetches another page
+	fetchedRows int
+	// countCtx and countCancel belong to the exact row count running in the
+	// background, nil when not counting
+	countCtx    context.Context
+	countCancel context.CancelFunc
+	// record shows the focused row as a list of fields instead of the grid,
+	// nil when the grid is shown
+	record *RecordView
+	// chart plots columns of the loaded rows instead of the grid, nil when
+	// the grid is shown
+	chart *chartView
+	// edits are the staged cell edits of a table tab, nil until the first
+	// edit
+	edits *query.EditSet
+	// editor edits the focused cell, nil when not editing
+	editor *cellEditor
+	// review lists the pending UPDATEs, nil when not shown
+	review *editReview
+	// saving is set while the edits are written
+	saving bool
+	// references offers the rows related to the focused row, nil when not
+	// shown
+	references *referencePicker
+	// export asks how to export the result, nil when not shown
+	export *exportForm
+	// stats shows the statistics of a column, nil when not shown
+	stats *statsPanel
+	// drag is the column being resized with the mouse, nil when none is
+	drag *columnDrag
 }
 
 func TableViewScreen() TableViewModel {
@@ -22,6 +56,7 @@ func TableViewScreen() TableViewModel {
 		table.WithRows(defaultRows()),
 		table.WithFocused(true),
 		table.WithHeight(20),
+		table.WithNullText(query.Display.NullText),
 	)
 
 	s := spinner.New()
@@ -55,6 +90,9 @@ func (m *TableViewModel) SetSize(width, height int) {
 	// The surrounding border is already removed by the parent (borderedInner).
 	tableHeight := max(1, height-1)
 	m.table.SetHeight(tableHeight)
+	if m.record != nil {
+		m.record.SetSize(width, tableHeight)
+	}
 }
 
 func (m *TableViewModel) GetSize() (int, int) {
@@ -64,3 +102,16 @@ func (m *TableViewModel) GetSize() (int, int) {
 func (m *TableViewModel) Table() *table.Model {
 	return &m.table
 }
+
+// Close releases the server-side cursor of a streamed result, if any, and
+// cancels a running row count and column statistics
+func (m *TableViewModel) Close() {
+	m.cancelCount()
+	m.closeStats()
+	m.data.Close()
+}
+
+// ShowsQuery reports whether q is the query whose result is shown
+func (m *TableViewModel) ShowsQuery(q query.ExecutableQuery) bool {
+	return m.data.Query() == q
+}
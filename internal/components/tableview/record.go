@@ -0,0 +1,102 @@
+package tableview
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/internal/query"
+	"github.com/SavingFrame/dbettier/pkgs/table"
+)
+
+// recordFieldWidth caps the width of the column and type columns of the
+// record view
+const recordFieldWidth = 40
+
+// RecordView shows one row of the grid as a list of column, type and value
+// pairs, like psql's expanded display
+type RecordView struct {
+	table table.Model
+	// row is the index of the shown row in the grid
+	row   int
+	total int
+	width int
+}
+
+// NewRecordView creates a record view of the given size
+func NewRecordView(width, height int) *RecordView {
+	r := &RecordView{
+		table: table.New(
+			table.WithFocused(true),
+			table.WithNullText(query.Display.NullText),
+		),
+	}
+	r.SetSize(width, height)
+	return r
+}
+
+// SetSize sets the size of the record view, including its title line
+func (r *RecordView) SetSize(width, height int) {
+	r.width = width
+	r.table.SetWidth(width)
+	r.table.SetHeight(max(1, height-1))
+}
+
+// Show fills the view with the row at index rowIdx of a grid with total rows,
+// keeping the focused field
+func (r *RecordView) Show(columns []table.Column, row table.Row, rowIdx, total int) {
+	r.row, r.total = rowIdx, total
+	field, _ := r.table.FocusedPosition()
+
+	nameWidth, typeWidth := table.TextWidth("column"), table.TextWidth("type")
+	rows := make([]table.Row, len(columns))
+	for i, col := range columns {
+		value := ""
+		if i < len(row) {
+			value = row[i]
+		}
+		rows[i] = table.Row{col.Title, col.Type, value}
+		nameWidth = max(nameWidth, table.TextWidth(col.Title))
+		typeWidth = max(typeWidth, table.TextWidth(col.Type))
+	}
+	nameWidth = min(nameWidth+5, recordFieldWidth)
+	typeWidth = min(typeWidth+5, recordFieldWidth)
+
+	r.table.SetRows(rows)
+	r.table.SetColumns([]table.Column{
+		{Title: "column", Width: nameWidth},
+		{Title: "type", Width: typeWidth},
+		{Title: "value", Width: max(minColWidth, r.width-nameWidth-typeWidth)},
+	})
+	r.table.SetCursor(field, 2)
+}
+
+// Row returns the index of the shown row in the grid
+func (r *RecordView) Row() int {
+	return r.row
+}
+
+// IsTyping reports whether keys go to the record view's search
+func (r *RecordView) IsTyping() bool {
+	return r.table.SearchMode() || r.table.InspectorSearching()
+}
+
+// Update handles keys for the record view's fields. Sorting and the column
+// layout are left out, as the fields are not the grid's columns.
+func (r *RecordView) Update(msg tea.KeyMsg) tea.Cmd {
+	if !r.IsTyping() && !r.table.Inspecting() {
+		switch msg.String() {
+		case "s", "S", "C", "<", ">", "=":
+			return nil
+		}
+	}
+	var cmd tea.Cmd
+	r.table, cmd = r.table.Update(msg)
+	return cmd
+}
+
+// View renders a title line followed by the fields
+func (r *RecordView) View() string {
+	title := sbLabelStyle().Render(fmt.Sprintf(" Record %d of %d ", r.row+1, r.total)) +
+		sbDimStyle().Render("· h/l previous/next row · x back to grid")
+	return title + "\n" + r.table.View()
+}
@@ -0,0 +1,92 @@
+package tableview
+
+import (
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/SavingFrame/dbettier/internal/components/notifications"
+	"github.com/SavingFrame/dbettier/internal/messages"
+)
+
+// referencePicker lets the user pick one of several foreign key jumps
+type referencePicker struct {
+	title  string
+	jumps  []messages.TableJump
+	cursor int
+}
+
+// followReference asks the workspace for the rows the focused cell
+// references, or with incoming for the rows referencing the focused row
+func (m *TableViewModel) followReference(incoming bool) tea.Cmd {
+	if !m.data.HasQuery() || m.data.Query().GetSQLResult() == nil {
+		return nil
+	}
+	row, col := m.table.FocusedPosition()
+	rows := m.data.Rows()
+	if row >= len(rows) {
+		return notifications.ShowInfo("A new row has no references until it is saved")
+	}
+	columns := m.data.Query().GetSQLResult().Columns
+	if col >= len(columns) || !columns[col].FromTable() {
+		return notifications.ShowWarning("This column does not come from a table")
+	}
+	msg := messages.FollowReferenceMsg{
+		Query:      m.data.Query(),
+		DatabaseID: m.data.DatabaseID(),
+		Columns:    columns,
+		Row:        rows[row],
+		Column:     col,
+		Incoming:   incoming,
+	}
+	return func() tea.Msg { return msg }
+}
+
+// updateReferences handles keys in the reference picker; enter opens the
+// selected jump
+func (m *TableViewModel) updateReferences(msg tea.KeyMsg) tea.Cmd {
+	p := m.references
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Escape):
+		m.references = nil
+	case key.Matches(msg, DefaultKeyMap.Enter):
+		m.references = nil
+		jump := messages.JumpToTableMsg{Jump: p.jumps[p.cursor], DatabaseID: m.data.DatabaseID()}
+		return func() tea.Msg { return jump }
+	case msg.String() == "j" || msg.String() == "down":
+		p.cursor = min(p.cursor+1, len(p.jumps)-1)
+	case msg.String() == "k" || msg.String() == "up":
+		p.cursor = max(0, p.cursor-1)
+	}
+	return nil
+}
+
+// renderReferences draws the reference picker centered over content
+func (m TableViewModel) renderReferences(content string) string {
+	p := m.references
+	width := lipgloss.Width(p.title)
+	for _, jump := range p.jumps {
+		width = max(width, lipgloss.Width(jump.Label)+2)
+	}
+	width = min(width, max(10, m.viewport.Width()-6))
+
+	lines := []string{reviewTitleStyle().Render(p.title), ""}
+	for i, jump := range p.jumps {
+		if i == p.cursor {
+			lines = append(lines, referenceSelectedStyle().Width(width).Render("› "+jump.Label))
+		} else {
+			lines = append(lines, reviewDimStyle().Width(width).Render("  "+jump.Label))
+		}
+	}
+	lines = append(lines, "", reviewDimStyle().Render("enter open · j/k move · esc close"))
+	popup := reviewStyle().Render(strings.Join(lines, "\n"))
+
+	base := lipgloss.NewStyle().Width(m.viewport.Width()).Height(m.viewport.Height()).Render(content)
+	x := max(0, (lipgloss.Width(base)-lipgloss.Width(popup))/2)
+	y := max(0, (lipgloss.Height(base)-lipgloss.Height(popup))/2)
+	return lipgloss.NewCompositor(
+		lipgloss.NewLayer(base),
+		lipgloss.NewLayer(popup).X(x).Y(y),
+	).Render()
+}
@@ -0,0 +1,212 @@
+package tableview
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/SavingFrame/dbettier/internal/components/notifications"
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/SavingFrame/dbettier/internal/messages"
+	"github.com/SavingFrame/dbettier/internal/query"
+	"github.com/SavingFrame/dbettier/pkgs/table"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// statsPanel shows the statistics of the focused column, first of the
+// loaded rows and on request of the whole table
+type statsPanel struct {
+	column database.ResultColumn
+	stats  query.ColumnStats
+	// filtered is set when table statistics are of the rows matching the
+	// tab's filter
+	filtered bool
+	// ctx and cancel belong to the statistics being read from the
+	// database, nil when none are
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// openStats shows the statistics of the focused column over the loaded rows
+func (m *TableViewModel) openStats() {
+	if !m.data.HasQuery() || m.data.Query().GetSQLResult() == nil {
+		return
+	}
+	columns := m.data.Query().GetSQLResult().Columns
+	_, col := m.table.FocusedPosition()
+	if col >= len(columns) {
+		return
+	}
+	m.stats = &statsPanel{
+		column: columns[col],
+		stats:  query.PageStats(m.data.Rows(), col, columns[col]),
+	}
+}
+
+// closeStats closes the statistics, cancelling those being read
+func (m *TableViewModel) closeStats() {
+	if m.stats != nil && m.stats.cancel != nil {
+		m.stats.cancel()
+	}
+	m.stats = nil
+}
+
+// updateStats handles keys in the statistics: t reads them over the whole
+// table, e from pg_stats and p goes back to the loaded rows
+func (m *TableViewModel) updateStats(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Escape, DefaultKeyMap.Stats) || msg.String() == "q":
+		m.closeStats()
+	case msg.String() == "p" && m.stats.cancel == nil:
+		m.openStats()
+	case msg.String() == "t" && m.stats.cancel == nil:
+		return m.loadStats(false)
+	case msg.String() == "e" && m.stats.cancel == nil:
+		return m.loadStats(true)
+	}
+	return nil
+}
+
+// loadStats asks the workspace for the statistics of the column over the
+// whole table, filtered as the tab is, or with estimate from pg_stats
+func (m *TableViewModel) loadStats(estimate bool) tea.Cmd {
+	s := m.stats
+	if !s.column.FromTable() {
+		return notifications.ShowWarning("This column does not come from a table")
+	}
+	where := ""
+	if tq, ok := m.data.Query().(*query.TableQuery); ok && !estimate {
+		where = tq.WhereClause
+	}
+	s.filtered = where != ""
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	msg := messages.ColumnStatsMsg{
+		Query:       m.data.Query(),
+		DatabaseID:  m.data.DatabaseID(),
+		Column:      s.column,
+		WhereClause: where,
+		Estimate:    estimate,
+		Ctx:         s.ctx,
+	}
+	return func() tea.Msg { return msg }
+}
+
+// handleColumnStats shows the statistics read for the panel, unless it was
+// closed or reopened meanwhile
+func (m *TableViewModel) handleColumnStats(msg messages.ColumnStatsLoadedMsg) {
+	s := m.stats
+	if s == nil || msg.Ctx != s.ctx {
+		return
+	}
+	s.cancel()
+	s.ctx, s.cancel = nil, nil
+	if msg.Err == nil {
+		s.stats = msg.Stats
+	}
+}
+
+// renderStats draws the statistics centered over content
+func (m TableViewModel) renderStats(content string) string {
+	s := m.stats
+	st := s.stats
+	width := min(60, max(30, m.viewport.Width()-8))
+	approx := ""
+	if st.Source == query.StatsEstimate {
+		approx = "~"
+	}
+	count := func(n int64) string {
+		return approx + strconv.FormatInt(n, 10)
+	}
+	field := func(name, value string) string {
+		return reviewDimStyle().Width(12).Render(name) + statsValue(value, width-12)
+	}
+
+	title := fmt.Sprintf("Statistics of %s (%s)", s.column.Name, s.column.TypeName)
+	source := map[query.StatsSource]string{
+		query.StatsPage:     "Loaded rows",
+		query.StatsTable:    "Whole table",
+		query.StatsEstimate: "Planner estimate from pg_stats",
+	}[st.Source]
+	if st.Source == query.StatsTable && s.filtered {
+		source = "Rows matching the filter"
+	}
+	if s.cancel != nil {
+		source = m.spinner.View() + " Computing…"
+	}
+	lines := []string{
+		reviewTitleStyle().Render(statsValue(title, width)),
+		reviewDimStyle().Render(source),
+		"",
+		field("Rows", count(st.Rows)),
+		field("NULL", fmt.Sprintf("%s (%s%%)", count(st.Nulls), formatStat(st.NullPercent()))),
+		field("Distinct", count(st.Distinct)),
+	}
+	if st.Min != "" {
+		if st.Source == query.StatsEstimate {
+			lines = append(lines, field("Histogram", st.Min+" … "+st.Max))
+		} else {
+			lines = append(lines, field("Min", st.Min), field("Max", st.Max))
+		}
+	}
+	if st.HasMean {
+		lines = append(lines, field("Mean", formatStat(st.Mean)), field("Median", formatStat(st.Median)))
+	}
+	if st.HasLengths {
+		l := st.Lengths
+		lines = append(lines,
+			field("Length", fmt.Sprintf("%d–%d, mean %s", l[0], l[4], formatStat(st.MeanLength))),
+			field("Quartiles", fmt.Sprintf("%d / %d / %d", l[1], l[2], l[3])),
+		)
+	}
+	if st.AvgWidth > 0 {
+		lines = append(lines, field("Avg width", fmt.Sprintf("%d bytes", st.AvgWidth)))
+	}
+
+	if len(st.Top) > 0 {
+		lines = append(lines, "", reviewTitleStyle().Render("Most frequent"))
+		counts := make([]string, len(st.Top))
+		countWidth := 0
+		for i, top := range st.Top {
+			counts[i] = count(top.Count)
+			countWidth = max(countWidth, len(counts[i]))
+		}
+		const barWidth = 10
+		valueWidth := width - countWidth - barWidth - 2
+		for i, top := range st.Top {
+			bar := ""
+			if st.Rows > 0 {
+				bar = strings.Repeat("█", max(1, int(math.Round(float64(top.Count)*barWidth/float64(st.Rows)))))
+			}
+			lines = append(lines, statsValue(top.Value, valueWidth)+" "+
+				reviewDimStyle().Render(fmt.Sprintf("%*s", countWidth, counts[i]))+" "+
+				referenceSelectedStyle().Render(bar))
+		}
+	}
+	lines = append(lines, "", reviewDimStyle().Render("t whole table · e pg_stats · p loaded rows · esc close"))
+	popup := reviewStyle().Render(strings.Join(lines, "\n"))
+
+	base := lipgloss.NewStyle().Width(m.viewport.Width()).Height(m.viewport.Height()).Render(content)
+	x := max(0, (lipgloss.Width(base)-lipgloss.Width(popup))/2)
+	y := max(0, (lipgloss.Height(base)-lipgloss.Height(popup))/2)
+	return lipgloss.NewCompositor(
+		lipgloss.NewLayer(base),
+		lipgloss.NewLayer(popup).X(x).Y(y),
+	).Render()
+}
+
+// statsValue fits a value on one line of the given width, padded to it
+func statsValue(s string, width int) string {
+	s = strings.NewReplacer("\r\n", "↵", "\n", "↵", "\r", "↵", "\t", " ").Replace(s)
+	s = ansi.Truncate(s, width, "…")
+	return s + strings.Repeat(" ", max(0, width-table.TextWidth(s)))
+}
+
+// formatStat formats a mean, median or percentage to at most two decimals
+func formatStat(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
@@ -2,6 +2,7 @@ package tableview
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"charm.land/bubbles/v2/textinput"
@@ -40,6 +41,7 @@ const (
 	StatusBarFocusNone StatusBarFocus = iota
 	StatusBarFocusFilter
 	StatusBarFocusOrdering
+	StatusBarFocusPage
 )
 
 // StatusBar handles the status bar UI for the table view
@@ -55,10 +57,16 @@ type StatusBar struct {
 	totalCols    int
 	isTableQuery bool
 	sortOrders   query.OrderByClauses
+	total        int // -1 if unknown
+	estimate     bool
+	counting     bool
+	sortFilter   query.SortFilterMode
+	pendingEdits int
 
 	// Input fields
 	filterInput   textinput.Model
 	orderingInput textinput.Model
+	pageInput     textinput.Model
 	focus         StatusBarFocus
 
 	// Dimensions
@@ -81,11 +89,19 @@ func NewStatusBar() StatusBar {
 	orderingInput.SetWidth(25)
 	orderingInput.SetStyles(styles)
 
+	pageInput := textinput.New()
+	pageInput.Placeholder = "page"
+	pageInput.CharLimit = 10
+	pageInput.SetWidth(8)
+	pageInput.SetStyles(styles)
+
 	return StatusBar{
 		pagination:    Pagination{},
 		filterInput:   filterInput,
 		orderingInput: orderingInput,
+		pageInput:     pageInput,
 		focus:         StatusBarFocusNone,
+		total:         -1,
 	}
 }
 
@@ -111,12 +127,20 @@ func (s *StatusBar) SetFocus(focus StatusBarFocus) {
 	case StatusBarFocusFilter:
 		s.filterInput.Focus()
 		s.orderingInput.Blur()
+		s.pageInput.Blur()
 	case StatusBarFocusOrdering:
 		s.orderingInput.Focus()
 		s.filterInput.Blur()
+		s.pageInput.Blur()
+	case StatusBarFocusPage:
+		s.pageInput.Reset()
+		s.pageInput.Focus()
+		s.filterInput.Blur()
+		s.orderingInput.Blur()
 	default:
 		s.filterInput.Blur()
 		s.orderingInput.Blur()
+		s.pageInput.Blur()
 	}
 }
 
@@ -135,6 +159,11 @@ func (s *StatusBar) OrderingInput() *textinput.Model {
 	return &s.orderingInput
 }
 
+// PageInput returns a pointer to the jump to page input
+func (s *StatusBar) PageInput() *textinput.Model {
+	return &s.pageInput
+}
+
 // FilterValue returns the current filter value
 func (s *StatusBar) FilterValue() string {
 	return s.filterInput.Value()
@@ -145,6 +174,29 @@ func (s *StatusBar) OrderingValue() string {
 	return s.orderingInput.Value()
 }
 
+// PageValue returns the current jump to page value
+func (s *StatusBar) PageValue() string {
+	return s.pageInput.Value()
+}
+
+// SetTotals sets the total row count shown in the position info. total is
+// -1 when unknown; estimate marks it as the planner's estimate.
+func (s *StatusBar) SetTotals(total int, estimate, counting bool) {
+	s.total = total
+	s.estimate = estimate
+	s.counting = counting
+}
+
+// SetSortFilterMode sets where the result is sorted and filtered
+func (s *StatusBar) SetSortFilterMode(mode query.SortFilterMode) {
+	s.sortFilter = mode
+}
+
+// SetPendingEdits sets the number of staged cell edits not yet saved
+func (s *StatusBar) SetPendingEdits(n int) {
+	s.pendingEdits = n
+}
+
 // SyncState updates the status bar display state from tableview
 func (s *StatusBar) SyncState(
 	focusedRow, totalRows, pageOffset int,
@@ -211,9 +263,19 @@ func (s *StatusBar) renderControls() string {
 	orderInput := zone.Mark("orderingInput", sbInputStyle().Render(s.orderingInput.View()))
 	parts = append(parts, orderLabel+orderInput)
 
+	// Jump to page input, only while in use
+	if s.focus == StatusBarFocusPage {
+		pageLabel := sbInputLabelStyle().Render(" Page ")
+		parts = append(parts, pageLabel+sbInputStyle().Render(s.pageInput.View()))
+	}
+
 	// Buttons
 	refreshBtn := zone.Mark("refresh", sbButtonPrimaryStyle().Render("↻ Refresh"))
-	countBtn := zone.Mark("count", sbButtonStyle().Render("# Count"))
+	countLabel := "# Count"
+	if s.counting {
+		countLabel = "■ Cancel count"
+	}
+	countBtn := zone.Mark("count", sbButtonStyle().Render(countLabel))
 
 	parts = append(parts, refreshBtn)
 	parts = append(parts, countBtn)
@@ -232,6 +294,17 @@ func (s *StatusBar) renderPositionInfo() string {
 	}
 	parts = append(parts, sbIconStyle().Render(icon))
 
+	// Ad-hoc results are sorted and filtered either in memory or by
+	// re-running the query; table tabs always use the server
+	if !s.isTableQuery && s.totalRows > 0 {
+		parts = append(parts, sbLabelStyle().Render("Sort/filter ")+sbValueStyle().Render(s.sortFilter.String()))
+	}
+
+	if s.pendingEdits > 0 {
+		parts = append(parts, sbPendingEditsStyle().Render(fmt.Sprintf("● %d changes", s.pendingEdits))+
+			sbDimStyle().Render(" (W to review)"))
+	}
+
 	// Sort orders (before position)
 	if len(s.sortOrders) > 0 {
 		var orderParts []string
@@ -246,17 +319,31 @@ func (s *StatusBar) renderPositionInfo() string {
 		parts = append(parts, sortInfo)
 	}
 
-	// Row position
+	// Row position: "Row 512 · 501–1,000 of ~1.2M"
 	if s.totalRows > 0 {
 		currentPos := s.focusedRow + 1 + s.pageOffset
-		totalRowsStr := fmt.Sprintf("%d", s.totalRows+s.pageOffset)
-		if s.canFetchMore {
-			totalRowsStr += "+"
+		lastRow := s.totalRows + s.pageOffset
+		var totalStr string
+		switch {
+		case s.total >= 0 && s.estimate:
+			totalStr = "~" + formatCompactCount(s.total)
+		case s.total >= 0:
+			totalStr = formatCount(s.total)
+		default:
+			totalStr = formatCount(lastRow)
+			if s.canFetchMore {
+				totalStr += "+"
+			}
 		}
 		rowInfo := sbLabelStyle().Render("Row ") +
-			sbValueStyle().Render(fmt.Sprintf("%d", currentPos)) +
-			sbDimStyle().Render("/") +
-			sbValueStyle().Render(totalRowsStr)
+			sbValueStyle().Render(formatCount(currentPos)) +
+			sbDimStyle().Render(" · ") +
+			sbValueStyle().Render(formatCount(s.pageOffset+1)+"–"+formatCount(lastRow)) +
+			sbDimStyle().Render(" of ") +
+			sbValueStyle().Render(totalStr)
+		if s.counting {
+			rowInfo += sbDimStyle().Render(" (counting…)")
+		}
 		parts = append(parts, rowInfo)
 	}
 
@@ -272,3 +359,32 @@ func (s *StatusBar) renderPositionInfo() string {
 	sep := sbSepStyle().Render(" │ ")
 	return strings.Join(parts, sep)
 }
+
+// formatCount renders n with thousands separators, e.g. 1,234,567
+func formatCount(n int) string {
+	digits := strconv.Itoa(n)
+	if n < 0 {
+		return digits
+	}
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
+
+// formatCompactCount renders n with a K/M/B suffix, e.g. 1.2M
+func formatCompactCount(n int) string {
+	switch {
+	case n >= 1_000_000_000:
+		return strconv.FormatFloat(float64(n)/1e9, 'f', 1, 64) + "B"
+	case n >= 1_000_000:
+		return strconv.FormatFloat(float64(n)/1e6, 'f', 1, 64) + "M"
+	case n >= 1_000:
+		return strconv.FormatFloat(float64(n)/1e3, 'f', 1, 64) + "K"
+	}
+	return strconv.Itoa(n)
+}
@@ -1,6 +1,7 @@
 package tableview
 
 import (
+	"charm.land/bubbles/v2/textinput"
 	"charm.land/lipgloss/v2"
 	"github.com/SavingFrame/dbettier/internal/theme"
 )
@@ -127,6 +128,68 @@ func emptyStateBulletStyle() lipgloss.Style {
 		Bold(true)
 }
 
+// Cell editing style functions
+
+// editorInputStyles returns the text input styles for editing a cell
+func editorInputStyles() textinput.Styles {
+	colors := theme.Current().Colors
+	text := lipgloss.NewStyle().Background(colors.Overlay).Foreground(colors.Text)
+	state := textinput.StyleState{Text: text, Placeholder: text.Foreground(colors.Muted), Prompt: text}
+	return textinput.Styles{Focused: state, Blurred: state}
+}
+
+func editorNullStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Colors.Muted).
+		Background(theme.Current().Colors.Overlay).
+		Italic(true)
+}
+
+func editorPickerStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Colors.Info).
+		Background(theme.Current().Colors.Overlay)
+}
+
+func sbPendingEditsStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Colors.Warning).
+		Background(theme.Current().Colors.Base).
+		Bold(true)
+}
+
+func reviewStyle() lipgloss.Style {
+	colors := theme.Current().Colors
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colors.BorderFocused).
+		Background(colors.Surface).
+		Padding(0, 1)
+}
+
+func reviewTitleStyle() lipgloss.Style {
+	colors := theme.Current().Colors
+	return lipgloss.NewStyle().
+		Foreground(colors.Primary).
+		Background(colors.Surface).
+		Bold(true)
+}
+
+func reviewDimStyle() lipgloss.Style {
+	colors := theme.Current().Colors
+	return lipgloss.NewStyle().
+		Foreground(colors.Subtle).
+		Background(colors.Surface)
+}
+
+func referenceSelectedStyle() lipgloss.Style {
+	colors := theme.Current().Colors
+	return lipgloss.NewStyle().
+		Foreground(colors.Base).
+		Background(colors.Primary).
+		Bold(true)
+}
+
 func spinnerStyle() lipgloss.Style {
 	return lipgloss.NewStyle().
 		Foreground(theme.Current().Colors.Primary)
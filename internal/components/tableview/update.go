@@ -1,10 +1,15 @@
 package tableview
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
 
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/internal/components/notifications"
 	"github.com/SavingFrame/dbettier/internal/messages"
 	"github.com/SavingFrame/dbettier/internal/query"
 	"github.com/SavingFrame/dbettier/pkgs/table"
@@ -33,37 +38,159 @@ func (m TableViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if earlyExit {
 			return m, tea.Batch(cmds...)
 		}
+	case StatusBarFocusPage:
+		cmd, earlyExit := m.handlePageInput(msg)
+		cmds = append(cmds, cmd)
+		if earlyExit {
+			m.syncStatusBar()
+			return m, tea.Batch(cmds...)
+		}
+	}
+
+	// the cell editor, the review of pending changes, the reference picker,
+	// the export form and the column statistics take all keys
+	if m.editor != nil || m.review != nil || m.references != nil || m.export != nil || m.stats != nil {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			if m.stats != nil {
+				cmds = append(cmds, m.updateStats(keyMsg))
+			} else if m.export != nil {
+				cmds = append(cmds, m.updateExport(keyMsg))
+			} else if m.references != nil {
+				cmds = append(cmds, m.updateReferences(keyMsg))
+			} else if m.review != nil {
+				cmds = append(cmds, m.updateReview(keyMsg))
+			} else {
+				cmds = append(cmds, m.updateEditor(keyMsg))
+			}
+			m.syncStatusBar()
+			return m, tea.Batch(cmds...)
+		}
+		if m.editor != nil {
+			cmds = append(cmds, m.editor.blink(msg))
+			m.table.SetCellEditor(m.editor.view())
+		}
+		if m.export != nil {
+			cmds = append(cmds, m.export.blink(msg))
+		}
+	}
+
+	// the chart view takes the keys choosing what it plots
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.chart != nil {
+		if cmd, handled := m.updateChart(keyMsg); handled {
+			cmds = append(cmds, cmd)
+			m.syncStatusBar()
+			return m, tea.Batch(cmds...)
+		}
 	}
 
-	// always update upstream table model
+	// the record view takes the keys for its fields
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.record != nil {
+		if cmd, handled := m.updateRecordView(keyMsg); handled {
+			cmds = append(cmds, cmd)
+			m.syncStatusBar()
+			return m, tea.Batch(cmds...)
+		}
+	}
+
+	// always update upstream table model; keys meant for the cell inspector,
+	// the yank menu or the column chooser stop there
+	inspecting := m.table.Inspecting() || m.table.YankMenuOpen() || m.table.ChooserOpen()
 	m.table, cmd = m.table.Update(msg)
 	cmds = append(cmds, cmd)
+	if _, isKey := msg.(tea.KeyMsg); isKey && inspecting {
+		m.syncStatusBar()
+		return m, tea.Batch(cmds...)
+	}
 
 	switch msg := msg.(type) {
 	case messages.TableLoadingMsg:
 		m.isLoading = true
 		return m, tea.Batch(cmds...)
+	case query.FetchingRowsMsg:
+		m.isLoading = true
+		m.fetchedRows = msg.Fetched
+		return m, tea.Batch(cmds...)
+	case query.FetchFailedMsg:
+		m.isLoading = false
+		m.fetchedRows = 0
 	case query.SQLResultMsg:
 		log.Printf("Received SQLResultMsg for TableViewModel: %+v", msg)
 		m.isLoading = false
+		m.fetchedRows = 0
+		newQuery := msg.Query != m.data.Query()
+		if newQuery {
+			m.cancelCount()
+			m.data.Close()
+			// A table opened by a jump starts out filtered
+			filter := ""
+			if tq, ok := msg.Query.(*query.TableQuery); ok {
+				filter = tq.WhereClause
+			}
+			m.statusBar.FilterInput().SetValue(filter)
+			m.table.CloseInspector()
+			m.resetEdits()
+			m.references = nil
+			m.export = nil
+			m.closeStats()
+			m.chart = nil
+			m.table.ClearSelection()
+		}
 		result := m.data.SetFromSQLResult(msg)
-		columns, rows := m.data.BuildTableData(result)
+		columns, source := m.data.BuildTableData(result, m.table.ShowTypes())
 		m.table.SetRows(nil)
 		m.table.SetColumns(columns)
+		if newQuery {
+			m.table.SetLayout(savedLayout(m.layoutKey(result)))
+		}
 		log.Println("Setting table rows")
-		m.table.SetRows(rows)
+		m.table.SetRowSource(m.overlayEdits(source))
+		m.setCopyText(result)
+		m.refreshRecordView()
+		m.refreshChart()
+		cmds = append(cmds, m.loadLabels())
 		log.Println("TableViewModel update complete after SQLResultMsg")
-		log.Printf("Table has %d columns and %d rows", len(m.table.Columns()), len(m.table.Rows()))
+		log.Printf("Table has %d columns and %d rows", len(m.table.Columns()), m.table.RowCount())
 	case query.UpdateTableMsg:
 		m.isLoading = false
-		m.data.SetQuery(msg.Query)
-		columns, rows := m.data.BuildTableData(msg.Query.GetSQLResult())
+		m.fetchedRows = 0
+		columns, source := m.data.BuildTableData(msg.Query.GetSQLResult(), m.table.ShowTypes())
 		m.table.SetRows(nil)
 		m.table.SetColumns(columns)
-		m.table.SetRows(rows)
+		m.table.SetRowSource(m.overlayEdits(source))
+		m.setCopyText(msg.Query.GetSQLResult())
+		m.refreshRecordView()
+		m.refreshChart()
+		cmds = append(cmds, m.loadLabels())
+	case messages.RowCountMsg:
+		// A count cancelled and restarted reports back twice
+		if msg.Ctx == m.countCtx {
+			m.cancelCount()
+		}
+	case messages.EditsAppliedMsg:
+		cmds = append(cmds, m.handleEditsApplied(msg))
+	case messages.CSVEditedMsg:
+		cmds = append(cmds, m.handleCSVEdited(msg))
+	case messages.LabelsLoadedMsg:
+		cmds = append(cmds, m.handleLabelsLoaded(msg))
+	case messages.ReferencesMsg:
+		m.references = &referencePicker{title: msg.Title, jumps: msg.Jumps}
+	case messages.ColumnStatsLoadedMsg:
+		m.handleColumnStats(msg)
 	case table.SortChangeMsg:
 		cmds = append(cmds, m.handleSortChange(msg))
+	case table.LayoutChangedMsg:
+		if m.data.HasQuery() {
+			cmds = append(cmds, saveLayout(m.layoutKey(m.data.Query().GetSQLResult()), msg.Layout))
+		}
+	case tea.MouseClickMsg:
+		m.startDrag(msg)
+	case tea.MouseMotionMsg:
+		m.updateDrag(msg)
 	case tea.MouseReleaseMsg:
+		if m.drag != nil {
+			cmds = append(cmds, m.endDrag())
+			return m, tea.Batch(cmds...)
+		}
 		if msg.Button != tea.MouseLeft {
 			return m, nil
 		}
@@ -74,6 +201,8 @@ func (m TableViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.statusBar.SetFocus(StatusBarFocusFilter)
 		} else if zone.Get("orderingInput").InBounds(msg) {
 			m.statusBar.SetFocus(StatusBarFocusOrdering)
+		} else if zone.Get("count").InBounds(msg) {
+			cmds = append(cmds, m.toggleCount())
 		}
 	case tea.KeyMsg:
 		switch {
@@ -91,6 +220,48 @@ func (m TableViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, DefaultKeyMap.PreviousPage):
 			cmd = m.handlePrevPage()
 			cmds = append(cmds, cmd)
+		case key.Matches(msg, DefaultKeyMap.CountRows) && !m.table.SearchMode():
+			cmds = append(cmds, m.toggleCount())
+		case key.Matches(msg, DefaultKeyMap.ToggleTypes) && !m.table.SearchMode():
+			m.toggleColumnTypes()
+		case key.Matches(msg, DefaultKeyMap.ToggleRecord) && !m.table.SearchMode():
+			m.toggleRecordView()
+		case key.Matches(msg, DefaultKeyMap.Chart) && !m.table.SearchMode():
+			cmds = append(cmds, m.toggleChart())
+		case key.Matches(msg, DefaultKeyMap.EditCell) && !m.table.SearchMode():
+			cmds = append(cmds, m.startEdit())
+		case key.Matches(msg, DefaultKeyMap.RevertCell) && !m.table.SearchMode():
+			m.revertCell()
+		case key.Matches(msg, DefaultKeyMap.DiscardEdits) && !m.table.SearchMode():
+			cmds = append(cmds, m.discardEdits())
+		case key.Matches(msg, DefaultKeyMap.ReviewEdits) && !m.table.SearchMode():
+			m.openReview()
+		case key.Matches(msg, DefaultKeyMap.AddRow) && !m.table.SearchMode():
+			cmds = append(cmds, m.addRow())
+		case key.Matches(msg, DefaultKeyMap.DuplicateRow) && !m.table.SearchMode():
+			cmds = append(cmds, m.duplicateRow())
+		case key.Matches(msg, DefaultKeyMap.DeleteRow) && !m.table.SearchMode():
+			cmds = append(cmds, m.toggleDelete())
+		case key.Matches(msg, DefaultKeyMap.BulkEdit) && !m.table.SearchMode():
+			cmds = append(cmds, m.bulkEdit())
+		case key.Matches(msg, DefaultKeyMap.FollowRef) && !m.table.SearchMode():
+			cmds = append(cmds, m.followReference(false))
+		case key.Matches(msg, DefaultKeyMap.ReferencedBy) && !m.table.SearchMode():
+			cmds = append(cmds, m.followReference(true))
+		case key.Matches(msg, DefaultKeyMap.ToggleLabels) && !m.table.SearchMode():
+			cmds = append(cmds, m.toggleLabels())
+		case key.Matches(msg, DefaultKeyMap.Export) && !m.table.SearchMode():
+			cmds = append(cmds, m.openExport())
+		case key.Matches(msg, DefaultKeyMap.Stats) && !m.table.SearchMode():
+			m.openStats()
+		case key.Matches(msg, DefaultKeyMap.JumpBack) && !m.table.SearchMode():
+			cmds = append(cmds, func() tea.Msg { return messages.JumpHistoryMsg{} })
+		case key.Matches(msg, DefaultKeyMap.JumpForward) && !m.table.SearchMode():
+			cmds = append(cmds, func() tea.Msg { return messages.JumpHistoryMsg{Forward: true} })
+		case key.Matches(msg, DefaultKeyMap.JumpToPage) && !m.table.SearchMode():
+			if m.data.IsTableQuery() {
+				m.statusBar.SetFocus(StatusBarFocusPage)
+			}
 		default:
 			m.statusBar.Pagination().Clear()
 		}
@@ -102,9 +273,24 @@ func (m TableViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// IsTyping reports whether keys go to a text input: the table or inspector
+// search, the cell editor and its review, the reference picker, the export
+// form, the column statistics, the yank menu, the column chooser, or one of
+// the status bar inputs
+func (m TableViewModel) IsTyping() bool {
+	if m.editor != nil || m.review != nil || m.references != nil || m.export != nil || m.stats != nil {
+		return true
+	}
+	if m.record != nil && m.record.IsTyping() {
+		return true
+	}
+	return m.table.SearchMode() || m.table.InspectorSearching() || m.table.YankMenuOpen() || m.table.ChooserOpen() ||
+		m.statusBar.Focus() != StatusBarFocusNone
+}
+
 func (m *TableViewModel) syncStatusBar() {
 	focusedRow, focusedCol := m.table.FocusedPosition()
-	totalRows := len(m.table.Rows())
+	totalRows := m.table.RowCount()
 	totalCols := len(m.table.Columns())
 
 	m.statusBar.SyncState(
@@ -117,6 +303,121 @@ func (m *TableViewModel) syncStatusBar() {
 		m.data.IsTableQuery(),
 		m.data.GetSortOrders(),
 	)
+	m.statusBar.SetSortFilterMode(m.data.SortFilterMode())
+	total, estimate := m.data.Total()
+	m.statusBar.SetTotals(total, estimate, m.countCancel != nil)
+	m.statusBar.SetPendingEdits(m.pendingEdits())
+}
+
+// setCopyText gives the table the text to copy from a result's cells and
+// the table to name in copied INSERT statements
+func (m *TableViewModel) setCopyText(result *query.SQLResult) {
+	m.table.SetCopySource(m.data.CopySource(result))
+	name := ""
+	if result != nil {
+		if schema, table, ok := resultTable(result.Columns); ok {
+			name = query.QualifiedName(schema, table)
+		}
+	}
+	m.table.SetYankTable(name)
+}
+
+// toggleColumnTypes shows or hides column types in the header, widening
+// the columns to fit them
+func (m *TableViewModel) toggleColumnTypes() {
+	m.table.SetShowTypes(!m.table.ShowTypes())
+	if !m.data.HasQuery() || m.data.Query().GetSQLResult() == nil {
+		return
+	}
+	columns, _ := m.data.BuildTableData(m.data.Query().GetSQLResult(), m.table.ShowTypes())
+	m.table.SetColumns(columns)
+}
+
+// toggleRecordView switches between the grid and the record view of the
+// focused row
+func (m *TableViewModel) toggleRecordView() {
+	if m.record != nil {
+		m.record = nil
+		return
+	}
+	if m.table.RowCount() == 0 {
+		return
+	}
+	m.record = NewRecordView(m.viewport.Width(), max(1, m.viewport.Height()-1))
+	row, _ := m.table.FocusedPosition()
+	m.showRecord(row)
+}
+
+// updateRecordView handles a key in the record view. It reports false for
+// keys left to the table view, such as quitting.
+func (m *TableViewModel) updateRecordView(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if !m.record.IsTyping() && !m.record.table.Inspecting() {
+		switch {
+		case key.Matches(msg, DefaultKeyMap.ToggleRecord):
+			m.record = nil
+			return nil, true
+		case key.Matches(msg, DefaultKeyMap.PrevRecord):
+			m.showRecord(m.record.Row() - 1)
+			return nil, true
+		case key.Matches(msg, DefaultKeyMap.NextRecord):
+			m.showRecord(m.record.Row() + 1)
+			return nil, true
+		case key.Matches(msg, DefaultKeyMap.Quit):
+			return nil, false
+		}
+	}
+	return m.record.Update(msg), true
+}
+
+// showRecord focuses row in the grid and shows it in the record view
+func (m *TableViewModel) showRecord(row int) {
+	total := m.table.RowCount()
+	row = max(0, min(row, total-1))
+	_, col := m.table.FocusedPosition()
+	m.table.SetCursor(row, col)
+	m.record.Show(m.table.Columns(), m.table.Row(row), row, total)
+}
+
+// refreshRecordView shows the focused row again after the grid's rows
+// changed, and leaves the record view when there are none
+func (m *TableViewModel) refreshRecordView() {
+	if m.record == nil {
+		return
+	}
+	if m.table.RowCount() == 0 {
+		m.record = nil
+		return
+	}
+	row, _ := m.table.FocusedPosition()
+	m.showRecord(row)
+}
+
+// toggleCount starts an exact count of the table query's rows, or cancels
+// the one already running
+func (m *TableViewModel) toggleCount() tea.Cmd {
+	if m.countCancel != nil {
+		m.cancelCount()
+		return nil
+	}
+	tq, ok := m.data.Query().(*query.TableQuery)
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.countCtx, m.countCancel = ctx, cancel
+	databaseID := m.data.DatabaseID()
+	return func() tea.Msg {
+		return messages.CountRowsMsg{Query: tq, DatabaseID: databaseID, Ctx: ctx}
+	}
+}
+
+// cancelCount cancels a running row count. A cancelled count still reports
+// back with a RowCountMsg, which the workspace logs.
+func (m *TableViewModel) cancelCount() {
+	if m.countCancel != nil {
+		m.countCancel()
+		m.countCtx, m.countCancel = nil, nil
+	}
 }
 
 func (m *TableViewModel) handleSortChange(msg table.SortChangeMsg) tea.Cmd {
@@ -126,12 +427,7 @@ func (m *TableViewModel) handleSortChange(msg table.SortChangeMsg) tea.Cmd {
 	}
 
 	orderByClauses := m.data.HandleSortChange(m.table.Columns(), msg.SortOrders)
-	switch tq := m.data.Query().(type) {
-	case *query.TableQuery:
-		cmd := tq.HandleSortChange(orderByClauses)
-		return cmd
-	}
-	return nil
+	return m.data.Query().HandleSortChange(orderByClauses)
 }
 
 func (m *TableViewModel) handleNextPage() tea.Cmd {
@@ -179,9 +475,11 @@ func (m *TableViewModel) handleFilterInput(msg tea.Msg) (tea.Cmd, bool) {
 	if msg, ok := msg.(tea.KeyMsg); ok {
 		if key.Matches(msg, DefaultKeyMap.Enter) {
 			v := m.statusBar.FilterValue()
-			if tq, ok := m.data.Query().(*query.TableQuery); ok {
-				tableUpdateCmd := tq.SetWhereClause(v)
-				cmds = append(cmds, tableUpdateCmd)
+			if tq, ok := m.data.Query().(*query.TableQuery); ok && v != tq.WhereClause {
+				m.cancelCount()
+			}
+			if m.data.HasQuery() {
+				cmds = append(cmds, m.data.Query().SetWhereClause(v))
 				return tea.Batch(cmds...), false
 			}
 		} else if key.Matches(msg, DefaultKeyMap.Escape) {
@@ -220,11 +518,50 @@ func (m *TableViewModel) handleOrderingInput(msg tea.Msg) (tea.Cmd, bool) {
 	}
 }
 
+// handlePageInput processes input when the jump to page input is focused
+func (m *TableViewModel) handlePageInput(msg tea.Msg) (tea.Cmd, bool) {
+	var cmd tea.Cmd
+	m.statusBar.pageInput, cmd = m.statusBar.PageInput().Update(msg)
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return cmd, false
+	}
+
+	switch {
+	case key.Matches(keyMsg, DefaultKeyMap.Enter):
+		m.statusBar.SetFocus(StatusBarFocusNone)
+		return tea.Batch(cmd, m.jumpToPage(m.statusBar.PageValue())), true
+	case key.Matches(keyMsg, DefaultKeyMap.Escape):
+		m.statusBar.SetFocus(StatusBarFocusNone)
+		return cmd, true
+	default:
+		return cmd, true
+	}
+}
+
+// jumpToPage moves the table query to the page typed in by the user,
+// clamped to the last page when the total is known
+func (m *TableViewModel) jumpToPage(value string) tea.Cmd {
+	tq, ok := m.data.Query().(*query.TableQuery)
+	if !ok {
+		return nil
+	}
+	page, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || page < 1 {
+		return notifications.ShowError(fmt.Sprintf("Invalid page number: %q", value))
+	}
+	if pages := tq.PageCount(); pages > 0 {
+		page = min(page, pages)
+	}
+	m.table.ScrollToTop()
+	return tq.JumpToPage(page)
+}
+
 // applyOrdering parses the ordering input and applies it to the table query.
 // Returns the command to execute and whether the ordering was successfully applied.
 func (m *TableViewModel) applyOrdering() (tea.Cmd, bool) {
-	tq, ok := m.data.Query().(*query.TableQuery)
-	if !ok {
+	if !m.data.HasQuery() {
 		return nil, false
 	}
 
@@ -235,7 +572,7 @@ func (m *TableViewModel) applyOrdering() (tea.Cmd, bool) {
 	}
 
 	m.table.SetSortVisually(m.orderClausesToOrderCols(orderByClauses))
-	return tq.HandleSortChange(orderByClauses), true
+	return m.data.Query().HandleSortChange(orderByClauses), true
 }
 
 // orderClausesToOrderCols converts OrderByClauses to table.OrderCol slice
@@ -7,6 +7,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/SavingFrame/dbettier/internal/theme"
+	zone "github.com/lrstanley/bubblezone/v2"
 )
 
 // View implements tea.Model interface
@@ -21,6 +22,9 @@ func (m TableViewModel) View() tea.View {
 // RenderContent returns the string representation of the view for composition
 func (m TableViewModel) RenderContent() string {
 	if m.isLoading {
+		if m.fetchedRows > 0 {
+			return fmt.Sprintf("\n\n   %s Fetching more rows... %d fetched so far\n\n", m.spinner.View(), m.fetchedRows)
+		}
 		return fmt.Sprintf("\n\n   %s Fetching data...\n\n", m.spinner.View())
 	}
 	if !m.viewport.IsReady() {
@@ -33,13 +37,33 @@ func (m TableViewModel) RenderContent() string {
 	// Keep status bar pinned to the bottom by forcing the table body
 	// to occupy all available vertical space above it.
 	tableBodyHeight := max(1, m.viewport.Height()-1)
+	body := zone.Mark("grid", m.table.View())
+	if m.record != nil {
+		body = m.record.View()
+	}
+	if m.chart != nil {
+		body = m.renderChart(m.viewport.Width(), tableBodyHeight)
+	}
 	tableBody := lipgloss.NewStyle().
 		Width(max(0, m.viewport.Width())).
 		Height(tableBodyHeight).
 		Background(theme.Current().Colors.Base).
-		Render(m.table.View())
+		Render(body)
 
-	return tableBody + "\n" + m.statusBar.View()
+	content := tableBody + "\n" + m.statusBar.View()
+	if m.review != nil {
+		return m.renderReview(content)
+	}
+	if m.references != nil {
+		return m.renderReferences(content)
+	}
+	if m.export != nil {
+		return m.renderExport(content)
+	}
+	if m.stats != nil {
+		return m.renderStats(content)
+	}
+	return content
 }
 
 func (m TableViewModel) renderEmptyState() string {
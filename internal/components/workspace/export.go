@@ -0,0 +1,178 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/internal/components/logpanel"
+	"github.com/SavingFrame/dbettier/internal/components/notifications"
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/SavingFrame/dbettier/internal/export"
+	"github.com/SavingFrame/dbettier/internal/messages"
+)
+
+// exportBatchSize is the number of rows read and written per step of an
+// export that re-runs its query
+const exportBatchSize = 10_000
+
+// exportJob is an export in progress. Each step writes one batch and
+// reports back, so progress shows in the log panel as the file grows. The
+// rows go to a temporary file next to path, which replaces path only once
+// the export succeeds, so a failed export leaves an existing file as it was.
+type exportJob struct {
+	path   string
+	file   *os.File
+	writer export.Writer
+	cursor *database.Cursor
+	rows   int
+}
+
+// exportCmd starts writing a result to a file
+func exportCmd(r *database.DBRegistry, msg messages.ExportMsg) tea.Cmd {
+	return func() tea.Msg {
+		job := &exportJob{path: msg.Path}
+		file, err := createExportFile(msg.Path)
+		if err != nil {
+			return messages.ExportProgressMsg{Path: msg.Path, Err: err}
+		}
+		job.file = file
+		if job.writer, err = export.NewWriter(file, msg.Columns, msg.Options); err != nil {
+			return job.finish(err)
+		}
+		if msg.SQL == "" {
+			job.rows = len(msg.Rows)
+			return job.finish(job.writer.WriteRows(msg.Rows))
+		}
+
+		db, errMsg := connectedDatabase(r, msg.DatabaseID)
+		if db == nil {
+			return tea.BatchMsg{
+				func() tea.Msg { return errMsg },
+				func() tea.Msg { return job.finish(errors.New("database unavailable")) },
+			}
+		}
+		ctx := context.Background()
+		logs := []tea.Cmd{
+			logpanel.AddLogCmd(msg.SQL, messages.LogSQL),
+			logpanel.AddLogCmd(fmt.Sprintf("Exporting %s as %s", msg.Path, msg.Options.Format), messages.LogInfo),
+		}
+		// The cursor runs on a spare connection, which would not see the
+		// session's transaction, settings or temporary tables
+		if plain, err := db.SessionIsDefault(ctx); err != nil || !plain {
+			return tea.BatchMsg(append(logs, func() tea.Msg {
+				return job.finish(job.writeQuery(db, msg.SQL, msg.Args))
+			}))
+		}
+		if job.cursor, err = db.DeclareCursor(ctx, msg.SQL, msg.Args...); err != nil {
+			return job.finish(err)
+		}
+		return tea.BatchMsg(append(logs, job.step))
+	}
+}
+
+// createExportFile creates the temporary file an export to path is written
+// to, with the mode of the file it replaces or else one readable by all
+func createExportFile(path string) (*os.File, error) {
+	mode := fs.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	if err := file.Chmod(mode); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return nil, err
+	}
+	return file, nil
+}
+
+// step writes the next batch of rows
+func (j *exportJob) step() tea.Msg {
+	rows, err := j.cursor.Fetch(context.Background(), exportBatchSize)
+	if err == nil {
+		err = j.writer.WriteRows(rows)
+	}
+	j.rows += len(rows)
+	if err != nil || j.cursor.Exhausted() {
+		return j.finish(err)
+	}
+	return messages.ExportProgressMsg{Path: j.path, Rows: j.rows, Next: j.step}
+}
+
+// writeQuery runs sql on the main connection and writes the rows as they
+// arrive, in a single step without progress reports
+func (j *exportJob) writeQuery(db *database.Database, sql string, args []any) error {
+	rows, err := db.Connection.Query(context.Background(), sql, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	batch := make([][]any, 0, exportBatchSize)
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return err
+		}
+		batch = append(batch, values)
+		if len(batch) == exportBatchSize {
+			if err := j.writer.WriteRows(batch); err != nil {
+				return err
+			}
+			j.rows += len(batch)
+			batch = batch[:0]
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	j.rows += len(batch)
+	return j.writer.WriteRows(batch)
+}
+
+// finish completes the file and puts it in place, or removes it if the
+// export failed
+func (j *exportJob) finish(err error) tea.Msg {
+	if j.cursor != nil {
+		_ = j.cursor.Close(context.Background())
+	}
+	if err == nil {
+		err = j.writer.Close()
+	}
+	if closeErr := j.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(j.file.Name(), j.path)
+	}
+	if err != nil {
+		_ = os.Remove(j.file.Name())
+	}
+	return messages.ExportProgressMsg{Path: j.path, Rows: j.rows, Done: err == nil, Err: err}
+}
+
+// handleExportProgress logs an export's progress and continues it
+func handleExportProgress(msg messages.ExportProgressMsg) tea.Cmd {
+	switch {
+	case msg.Err != nil:
+		return tea.Batch(
+			logpanel.AddLogCmd("Export to "+msg.Path+" failed: "+msg.Err.Error(), messages.LogError),
+			notifications.ShowError("Export failed: "+msg.Err.Error()),
+		)
+	case msg.Done:
+		text := fmt.Sprintf("Exported %d rows to %s", msg.Rows, msg.Path)
+		return tea.Batch(logpanel.AddLogCmd(text, messages.LogSuccess), notifications.ShowSuccess(text))
+	default:
+		return tea.Batch(
+			logpanel.AddLogCmd(fmt.Sprintf("Exported %d rows to %s so far", msg.Rows, msg.Path), messages.LogInfo),
+			msg.Next,
+		)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/internal/components/notifymonitor"
 	sqlcommandbarv2 "github.com/SavingFrame/dbettier/internal/components/sql_commandbar_v2"
 	"github.com/SavingFrame/dbettier/internal/components/tableview"
 	"github.com/SavingFrame/dbettier/internal/database"
@@ -18,6 +19,7 @@ type TabType int
 const (
 	TabTypeQuery TabType = iota
 	TabTypeTable
+	TabTypeNotify
 )
 
 type TabSize struct {
@@ -33,6 +35,12 @@ type Tab struct {
 	TableView     tableview.TableViewModel
 	SQLCommandBar sqlcommandbarv2.SQLCommandBarModel
 	DatabaseID    string
+
+	// Monitor replaces TableView and SQLCommandBar for TabTypeNotify tabs
+	Monitor notifymonitor.Model
+
+	// UnreadNotices counts server notices raised while the tab was in the background
+	UnreadNotices int
 }
 
 // Icon returns the nerd font icon for the tab type
@@ -42,6 +50,8 @@ func (t Tab) Icon() string {
 		return "󰓫"
 	case TabTypeQuery:
 		return "󰆍"
+	case TabTypeNotify:
+		return "󰂚"
 	default:
 		return "󰆍"
 	}
@@ -49,12 +59,19 @@ func (t Tab) Icon() string {
 
 // Workspace manages the workspace with multiple tabs
 type Workspace struct {
-	tabs         []Tab
-	activeIndex  int
-	width        int
-	height       int
-	queryCounter int
-	registry     *database.DBRegistry
+	tabs          []Tab
+	activeIndex   int
+	width         int
+	height        int
+	queryCounter  int
+	notifyCounter int
+	tableCounter  int
+	registry      *database.DBRegistry
+
+	// jumpsBack and jumpsForward hold the IDs of the tabs left by jumps
+	// along foreign keys, most recent last
+	jumpsBack    []string
+	jumpsForward []string
 
 	// Scroll state for tab overflow
 	scrollOffset int
@@ -96,8 +113,9 @@ func (w *Workspace) AddQueryTab(databaseID string) {
 
 // AddTableTab creates a new tab for a table
 func (w *Workspace) AddTableTab(tableName string, databaseID string) int {
+	w.tableCounter++
 	tab := Tab{
-		ID:            fmt.Sprintf("table-%s-%s", databaseID, tableName),
+		ID:            fmt.Sprintf("table-%s-%s-%d", databaseID, tableName, w.tableCounter),
 		Name:          tableName,
 		Type:          TabTypeTable,
 		TableView:     tableview.TableViewScreen(),
@@ -111,6 +129,26 @@ func (w *Workspace) AddTableTab(tableName string, databaseID string) int {
 	return w.activeIndex
 }
 
+// AddNotifyTab creates a new LISTEN/NOTIFY monitor tab and returns the command
+// that connects its listener
+func (w *Workspace) AddNotifyTab(databaseID string) tea.Cmd {
+	w.notifyCounter++
+	id := fmt.Sprintf("notify-%d", w.notifyCounter)
+	name := fmt.Sprintf("Notify %d", w.notifyCounter)
+	tab := Tab{
+		ID:         id,
+		Name:       name,
+		Type:       TabTypeNotify,
+		DatabaseID: databaseID,
+		Monitor:    notifymonitor.New(id, name, databaseID, w.registry),
+	}
+	tab.Monitor.SetSize(w.TableViewSize.width, w.TableViewSize.height, w.SQLCommandBarSize.width, w.SQLCommandBarSize.height)
+	w.tabs = append(w.tabs, tab)
+	w.activeIndex = len(w.tabs) - 1
+	w.ensureActiveTabVisible()
+	return tab.Monitor.Init()
+}
+
 // Tabs returns all tabs
 func (w *Workspace) Tabs() []Tab {
 	return w.tabs
@@ -135,17 +173,36 @@ func (w *Workspace) ActiveTab() *Tab {
 }
 
 // SetActiveIndex sets the active tab by index
+// tabByID returns the tab with the given ID, or nil if it was closed
+func (w *Workspace) tabByID(id string) *Tab {
+	for i := range w.tabs {
+		if w.tabs[i].ID == id {
+			return &w.tabs[i]
+		}
+	}
+	return nil
+}
+
 func (w *Workspace) SetActiveIndex(index int) {
 	if index >= 0 && index < len(w.tabs) {
 		w.activeIndex = index
+		w.markActiveTabRead()
 		w.ensureActiveTabVisible()
 	}
 }
 
+// markActiveTabRead clears the notice badge of the active tab
+func (w *Workspace) markActiveTabRead() {
+	if tab := w.ActiveTab(); tab != nil {
+		tab.UnreadNotices = 0
+	}
+}
+
 // NextTab switches to the next tab
 func (w *Workspace) NextTab() {
 	if len(w.tabs) > 0 {
 		w.activeIndex = (w.activeIndex + 1) % len(w.tabs)
+		w.markActiveTabRead()
 		w.ensureActiveTabVisible()
 	}
 }
@@ -154,6 +211,7 @@ func (w *Workspace) NextTab() {
 func (w *Workspace) PrevTab() {
 	if len(w.tabs) > 0 {
 		w.activeIndex = (w.activeIndex - 1 + len(w.tabs)) % len(w.tabs)
+		w.markActiveTabRead()
 		w.ensureActiveTabVisible()
 	}
 }
@@ -164,6 +222,12 @@ func (w *Workspace) CloseTab(index int) {
 		return
 	}
 
+	if w.tabs[index].Type == TabTypeNotify {
+		w.tabs[index].Monitor.Close()
+	} else {
+		w.tabs[index].TableView.Close()
+	}
+
 	// Remove the tab
 	w.tabs = append(w.tabs[:index], w.tabs[index+1:]...)
 
@@ -179,6 +243,7 @@ func (w *Workspace) CloseTab(index int) {
 	} else if w.activeIndex > index {
 		w.activeIndex--
 	}
+	w.markActiveTabRead()
 	w.ensureActiveTabVisible()
 }
 
@@ -198,6 +263,10 @@ func (w *Workspace) SetTabSizes(tableWidth, tableHeight, sqlWidth, sqlHeight int
 	w.SQLCommandBarSize = TabSize{width: sqlWidth, height: sqlHeight}
 	w.TableViewSize = TabSize{width: tableWidth, height: tableHeight}
 	for i := range w.tabs {
+		if w.tabs[i].Type == TabTypeNotify {
+			w.tabs[i].Monitor.SetSize(tableWidth, tableHeight, sqlWidth, sqlHeight)
+			continue
+		}
 		w.tabs[i].TableView.SetSize(tableWidth, tableHeight)
 		w.tabs[i].SQLCommandBar.SetSize(sqlWidth, sqlHeight)
 	}
@@ -206,6 +275,9 @@ func (w *Workspace) SetTabSizes(tableWidth, tableHeight, sqlWidth, sqlHeight int
 // Init initializes the active tab's components
 func (w Workspace) Init() tea.Cmd {
 	if tab := w.ActiveTab(); tab != nil {
+		if tab.Type == TabTypeNotify {
+			return nil
+		}
 		return tea.Batch(
 			tab.TableView.Init(),
 			tab.SQLCommandBar.Init(),
@@ -228,6 +300,11 @@ func (w Workspace) InitialSQLCommand() tea.Cmd {
 // UpdateActiveTableView updates the active tab's tableview
 func (w *Workspace) UpdateActiveTableView(msg tea.Msg) tea.Cmd {
 	if tab := w.ActiveTab(); tab != nil {
+		if tab.Type == TabTypeNotify {
+			var cmd tea.Cmd
+			tab.Monitor, cmd = tab.Monitor.UpdateStream(msg)
+			return cmd
+		}
 		log.Printf("Routing message to active tab's TableView: %+v", msg)
 		model, cmd := tab.TableView.Update(msg)
 		tab.TableView = model.(tableview.TableViewModel)
@@ -239,6 +316,11 @@ func (w *Workspace) UpdateActiveTableView(msg tea.Msg) tea.Cmd {
 // UpdateActiveSQLCommandBar updates the active tab's sqlcommandbar
 func (w *Workspace) UpdateActiveSQLCommandBar(msg tea.Msg) tea.Cmd {
 	if tab := w.ActiveTab(); tab != nil {
+		if tab.Type == TabTypeNotify {
+			var cmd tea.Cmd
+			tab.Monitor, cmd = tab.Monitor.UpdateControls(msg)
+			return cmd
+		}
 		model, cmd := tab.SQLCommandBar.Update(msg)
 		tab.SQLCommandBar = model.(sqlcommandbarv2.SQLCommandBarModel)
 		return cmd
@@ -249,6 +331,9 @@ func (w *Workspace) UpdateActiveSQLCommandBar(msg tea.Msg) tea.Cmd {
 // Focus focuses the active tab's sqlcommandbar
 func (w *Workspace) Focus() tea.Cmd {
 	if tab := w.ActiveTab(); tab != nil {
+		if tab.Type == TabTypeNotify {
+			return tab.Monitor.Focus()
+		}
 		return tab.SQLCommandBar.Focus()
 	}
 	return nil
@@ -257,6 +342,10 @@ func (w *Workspace) Focus() tea.Cmd {
 // Blur blurs the active tab's sqlcommandbar
 func (w *Workspace) Blur() {
 	if tab := w.ActiveTab(); tab != nil {
+		if tab.Type == TabTypeNotify {
+			tab.Monitor.Blur()
+			return
+		}
 		tab.SQLCommandBar.Blur()
 	}
 }
@@ -272,6 +361,9 @@ func (w *Workspace) GetActiveTableViewSize() (int, int) {
 // RenderActiveTableView returns the rendered content of the active tableview
 func (w *Workspace) RenderActiveTableView() string {
 	if tab := w.ActiveTab(); tab != nil {
+		if tab.Type == TabTypeNotify {
+			return tab.Monitor.RenderStream()
+		}
 		return tab.TableView.RenderContent()
 	}
 	return w.renderNoTabTableState()
@@ -280,6 +372,9 @@ func (w *Workspace) RenderActiveTableView() string {
 // RenderActiveSQLCommandBar returns the rendered content of the active sqlcommandbar
 func (w *Workspace) RenderActiveSQLCommandBar() string {
 	if tab := w.ActiveTab(); tab != nil {
+		if tab.Type == TabTypeNotify {
+			return tab.Monitor.RenderControls()
+		}
 		return tab.SQLCommandBar.RenderContent()
 	}
 	return w.renderNoTabSQLState()
@@ -0,0 +1,266 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/internal/components/logpanel"
+	"github.com/SavingFrame/dbettier/internal/components/notifications"
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/SavingFrame/dbettier/internal/messages"
+	"github.com/SavingFrame/dbettier/internal/query"
+)
+
+// followReferenceCmd looks up the foreign keys of the focused column's
+// table and jumps to the related rows, or offers the jumps when there are
+// several
+func followReferenceCmd(r *database.DBRegistry, msg messages.FollowReferenceMsg) tea.Cmd {
+	return func() tea.Msg {
+		db, errMsg := connectedDatabase(r, msg.DatabaseID)
+		if db == nil {
+			return errMsg
+		}
+		col := msg.Columns[msg.Column]
+		table := db.FindTable(col.TableSchema, col.TableName)
+		outgoing, incoming, err := table.LoadForeignKeys()
+		if err != nil {
+			return tea.BatchMsg{
+				logpanel.AddLogCmd("Failed to load foreign keys: "+err.Error(), messages.LogError),
+				notifications.ShowError("Failed to load foreign keys: " + err.Error()),
+			}
+		}
+
+		var jumps []messages.TableJump
+		var title string
+		if msg.Incoming {
+			if len(incoming) == 0 {
+				return notifications.ShowInfo(fmt.Sprintf("No tables reference %s", table.Name))()
+			}
+			for _, fk := range incoming {
+				values, ok := rowValues(msg, fk.RefSchema, fk.RefTable, fk.RefColumns)
+				if !ok {
+					continue
+				}
+				jumps = append(jumps, messages.TableJump{
+					Label:       fmt.Sprintf("%s.%s (%s)", fk.Schema, fk.Table, strings.Join(fk.Columns, ", ")),
+					Table:       db.FindTable(fk.Schema, fk.Table),
+					WhereClause: query.MatchClause(fk.Columns, values),
+				})
+			}
+			if len(jumps) == 0 {
+				return notifications.ShowInfo("The referenced columns are missing from the result")()
+			}
+			title = fmt.Sprintf("Rows referencing this %s", table.Name)
+		} else {
+			var found bool
+			for _, fk := range outgoing {
+				if !slices.Contains(fk.Columns, col.BaseColumn) {
+					continue
+				}
+				found = true
+				values, ok := rowValues(msg, fk.Schema, fk.Table, fk.Columns)
+				if !ok {
+					continue
+				}
+				jumps = append(jumps, messages.TableJump{
+					Label:       fmt.Sprintf("%s.%s (%s)", fk.RefSchema, fk.RefTable, strings.Join(fk.RefColumns, ", ")),
+					Table:       db.FindTable(fk.RefSchema, fk.RefTable),
+					WhereClause: query.MatchClause(fk.RefColumns, values),
+				})
+			}
+			switch {
+			case !found:
+				return notifications.ShowInfo(fmt.Sprintf("%s is not part of a foreign key", col.BaseColumn))()
+			case len(jumps) == 0:
+				return notifications.ShowInfo("The foreign key is NULL or missing from the result")()
+			}
+			title = fmt.Sprintf("Rows referenced by %s", col.BaseColumn)
+		}
+
+		if len(jumps) == 1 {
+			return messages.JumpToTableMsg{Jump: jumps[0], DatabaseID: msg.DatabaseID}
+		}
+		return messages.ReferencesMsg{Query: msg.Query, Title: title, Jumps: jumps}
+	}
+}
+
+// rowValues returns the values of the named columns of a table in the row.
+// ok is false if one is missing from the result or NULL, as a NULL in a
+// foreign key references nothing.
+func rowValues(msg messages.FollowReferenceMsg, schema, table string, names []string) (values []any, ok bool) {
+	for _, name := range names {
+		i := resultColumn(msg.Columns, schema, table, name)
+		if i < 0 || i >= len(msg.Row) || msg.Row[i] == nil {
+			return nil, false
+		}
+		values = append(values, msg.Row[i])
+	}
+	return values, true
+}
+
+// resultColumn returns the index of the result column read from a table's
+// column, or -1
+func resultColumn(columns []database.ResultColumn, schema, table, name string) int {
+	return slices.IndexFunc(columns, func(c database.ResultColumn) bool {
+		return c.TableSchema == schema && c.TableName == table && c.BaseColumn == name
+	})
+}
+
+// loadLabelsCmd reads, in one statement per foreign key, the labels of the
+// rows referenced by a page of a table tab
+func loadLabelsCmd(r *database.DBRegistry, msg messages.LoadLabelsMsg) tea.Cmd {
+	return func() tea.Msg {
+		db, errMsg := connectedDatabase(r, msg.DatabaseID)
+		if db == nil {
+			return tea.BatchMsg{
+				func() tea.Msg { return errMsg },
+				func() tea.Msg {
+					return messages.LabelsLoadedMsg{Query: msg.Query, Err: errors.New("database unavailable")}
+				},
+			}
+		}
+		result := messages.LabelsLoadedMsg{Query: msg.Query}
+		var cmds tea.BatchMsg
+		failed := func(err error) tea.Msg {
+			result.Err = err
+			return append(cmds,
+				logpanel.AddLogCmd("Failed to look up labels: "+err.Error(), messages.LogError),
+				notifications.ShowError("Failed to look up labels: "+err.Error()),
+				func() tea.Msg { return result },
+			)
+		}
+		lookups := msg.Lookups
+		if lookups == nil {
+			var err error
+			if lookups, err = findLabelLookups(db, msg.Columns); err != nil {
+				return failed(err)
+			}
+			result.Lookups = lookups
+		}
+
+		result.Labels = make([]map[string]string, len(lookups))
+		for i := range lookups {
+			l := &lookups[i]
+			keys := l.MissingKeys(msg.Rows)
+			result.Labels[i] = make(map[string]string, len(keys))
+			if len(keys) == 0 {
+				continue
+			}
+			sql, args := l.Query(keys)
+			rows, err := db.LookupLabels(context.Background(), sql, args...)
+			cmds = append(cmds, logpanel.AddLogCmd(sql, messages.LogSQL))
+			if err != nil {
+				return failed(err)
+			}
+			for _, row := range rows {
+				label := row[len(row)-1]
+				key := query.LabelKey(row[:len(row)-1])
+				if label == nil {
+					result.Labels[i][key] = query.Display.NullText
+				} else {
+					result.Labels[i][key] = label.(string)
+				}
+			}
+			// Keys without a row, e.g. dangling ones of a NOT VALID
+			// constraint, are not looked up again
+			for _, k := range keys {
+				if _, ok := result.Labels[i][query.LabelKey(k)]; !ok {
+					result.Labels[i][query.LabelKey(k)] = "?"
+				}
+			}
+		}
+		return append(cmds, func() tea.Msg { return result })
+	}
+}
+
+// findLabelLookups finds the foreign keys of the tables a result was read
+// from whose columns are all in the result and whose referenced table has
+// a label column
+func findLabelLookups(db *database.Database, columns []database.ResultColumn) ([]query.LabelLookup, error) {
+	lookups := []query.LabelLookup{}
+	tables := map[[2]string]bool{}
+	labelled := map[int]bool{}
+	for _, col := range columns {
+		source := [2]string{col.TableSchema, col.TableName}
+		if !col.FromTable() || tables[source] {
+			continue
+		}
+		tables[source] = true
+		outgoing, _, err := db.FindTable(col.TableSchema, col.TableName).LoadForeignKeys()
+		if err != nil {
+			return nil, err
+		}
+		for _, fk := range outgoing {
+			l := query.LabelLookup{RefSchema: fk.RefSchema, RefTable: fk.RefTable, RefColumns: fk.RefColumns}
+			for _, name := range fk.Columns {
+				if i := resultColumn(columns, fk.Schema, fk.Table, name); i >= 0 {
+					l.Columns = append(l.Columns, i)
+				}
+			}
+			if len(l.Columns) < len(fk.Columns) {
+				continue
+			}
+			l.Column = l.Columns[len(l.Columns)-1]
+			if labelled[l.Column] {
+				continue
+			}
+			label, err := db.FindTable(fk.RefSchema, fk.RefTable).LabelColumn()
+			if err != nil {
+				return nil, err
+			}
+			// A label that is the key itself tells nothing new
+			if label == "" || slices.Contains(fk.RefColumns, label) {
+				continue
+			}
+			l.LabelColumn = label
+			labelled[l.Column] = true
+			lookups = append(lookups, l)
+		}
+	}
+	return lookups, nil
+}
+
+// jumpTo opens a jump in a new tab. The tab left behind is where travel goes
+// back to.
+func (w *Workspace) jumpTo(msg messages.JumpToTableMsg) tea.Cmd {
+	if tab := w.ActiveTab(); tab != nil {
+		w.jumpsBack = append(w.jumpsBack, tab.ID)
+		w.jumpsForward = nil
+	}
+	w.AddTableTab(msg.Jump.Table.Name, msg.DatabaseID)
+	return tea.Batch(
+		func() tea.Msg { return messages.TableLoadingMsg{} },
+		logpanel.AddLogCmd("Jumping to "+msg.Jump.Label+" where "+msg.Jump.WhereClause, messages.LogInfo),
+		openTableHandler(w.registry, msg.Jump.Table, msg.DatabaseID, msg.Jump.WhereClause, w.ActiveTab()),
+	)
+}
+
+// travel activates the tab before, or after, the active one along the jump
+// history, skipping tabs that were closed since
+func (w *Workspace) travel(forward bool) tea.Cmd {
+	from, to := &w.jumpsBack, &w.jumpsForward
+	if forward {
+		from, to = to, from
+	}
+	for len(*from) > 0 {
+		id := (*from)[len(*from)-1]
+		*from = (*from)[:len(*from)-1]
+		i := slices.IndexFunc(w.tabs, func(t Tab) bool { return t.ID == id })
+		if i < 0 {
+			continue
+		}
+		if tab := w.ActiveTab(); tab != nil {
+			*to = append(*to, tab.ID)
+		}
+		w.SetActiveIndex(i)
+		return nil
+	}
+	if forward {
+		return notifications.ShowInfo("No later jump to go forward to")
+	}
+	return notifications.ShowInfo("No earlier jump to go back to")
+}
@@ -98,6 +98,16 @@ func closeButtonStyle(active bool) lipgloss.Style {
 	return style
 }
 
+// noticeBadgeStyle returns the style for the unread notices counter on a tab
+func noticeBadgeStyle() lipgloss.Style {
+	colors := theme.Current().Colors
+	return lipgloss.NewStyle().
+		MarginLeft(1).
+		Foreground(colors.Base).
+		Background(colors.Teal).
+		MarginBackground(colors.Base)
+}
+
 // iconStyle returns the style for tab icons
 func iconStyle(tabType TabType, active bool) lipgloss.Style {
 	colors := theme.Current().Colors
@@ -114,6 +124,8 @@ func iconStyle(tabType TabType, active bool) lipgloss.Style {
 		style = style.Foreground(colors.Blue)
 	case TabTypeQuery:
 		style = style.Foreground(colors.Purple)
+	case TabTypeNotify:
+		style = style.Foreground(colors.Teal)
 	}
 	return style
 }
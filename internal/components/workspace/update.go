@@ -2,8 +2,10 @@ package workspace
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"strings"
 	"time"
 
@@ -11,10 +13,13 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"github.com/SavingFrame/dbettier/internal/components/logpanel"
 	"github.com/SavingFrame/dbettier/internal/components/notifications"
+	"github.com/SavingFrame/dbettier/internal/components/notifymonitor"
 	sharedcomponents "github.com/SavingFrame/dbettier/internal/components/shared_components"
+	"github.com/SavingFrame/dbettier/internal/components/tableview"
 	"github.com/SavingFrame/dbettier/internal/database"
 	"github.com/SavingFrame/dbettier/internal/messages"
 	"github.com/SavingFrame/dbettier/internal/query"
+	"github.com/jackc/pgx/v5/pgconn"
 	zone "github.com/lrstanley/bubblezone/v2"
 )
 
@@ -62,29 +67,136 @@ func (w Workspace) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		t := w.ActiveTab()
 		t.DatabaseID = msg.DatabaseID
 		q := query.NewBasicSQLQuery(msg.Query)
+		if q.Params.HasParams() {
+			return w, describeParamsCmd(w.registry, q, msg.DatabaseID, t.ID)
+		}
+		return w, tea.Batch(
+			func() tea.Msg { return messages.TableLoadingMsg{} },
+			executeSQLQuery(w.registry, q, msg.DatabaseID, t),
+		)
+
+	case messages.ExecuteQueryParamsMsg:
+		t := w.tabByID(msg.TabID)
+		if t == nil {
+			return w, logpanel.AddLogCmd("Tab for parameterized query was closed", messages.LogWarning)
+		}
+		t.DatabaseID = msg.DatabaseID
 		return w, tea.Batch(
 			func() tea.Msg { return messages.TableLoadingMsg{} },
-			executeSQLQuery(w.registry, q, msg.DatabaseID),
+			executeSQLQuery(w.registry, msg.Query, msg.DatabaseID, t),
+		)
+
+	// A page may arrive after its tab was left, so it goes to the tabs
+	// showing its query rather than the active one
+	case query.UpdateTableMsg:
+		return w, tea.Batch(w.updateTabsShowing(msg.Query, msg)...)
+
+	case query.FetchingRowsMsg:
+		return w, tea.Batch(w.updateTabsShowing(msg.Query, msg)...)
+
+	case query.FetchFailedMsg:
+		cmds := []tea.Cmd{
+			logpanel.AddLogCmd("Failed to fetch more rows: "+msg.Err.Error(), messages.LogError),
+			notifications.ShowError("Failed to fetch more rows: " + msg.Err.Error()),
+		}
+		return w, tea.Batch(append(cmds, w.updateTabsShowing(msg.Query, msg)...)...)
+
+	case query.SortFilterFailedMsg:
+		return w, tea.Batch(
+			logpanel.AddLogCmd("Failed to filter rows: "+msg.Err.Error(), messages.LogError),
+			notifications.ShowError("Failed to filter rows: "+msg.Err.Error()),
 		)
 
 	case query.ReapplyTableQueryMsg:
 		return w, tea.Batch(
 			func() tea.Msg { return messages.TableLoadingMsg{} },
-			executeSQLQuery(w.registry, msg.Query, w.ActiveTab().DatabaseID),
+			executeSQLQuery(w.registry, msg.Query, w.ActiveTab().DatabaseID, w.ActiveTab()),
 		)
 
+	case messages.CountRowsMsg:
+		return w, countRowsCmd(w.registry, msg)
+
+	case messages.RowCountMsg:
+		return w, w.handleRowCount(msg)
+
+	case messages.ApplyEditsMsg:
+		return w, applyEditsCmd(w.registry, msg)
+
+	case messages.EditsAppliedMsg:
+		return w, w.handleEditsApplied(msg)
+
+	case messages.CSVEditedMsg:
+		return w, tea.Batch(w.updateTabsShowing(msg.Query, msg)...)
+
 	case messages.OpenTableAndExecuteMsg:
 		w.AddTableTab(msg.Table.Name, msg.DatabaseID)
 		return w, tea.Batch(
 			func() tea.Msg { return messages.TableLoadingMsg{} },
-			openTableHandler(w.registry, msg.Table, msg.DatabaseID),
+			openTableHandler(w.registry, msg.Table, msg.DatabaseID, "", w.ActiveTab()),
 		)
 
+	case messages.FollowReferenceMsg:
+		return w, followReferenceCmd(w.registry, msg)
+
+	case messages.ReferencesMsg:
+		return w, tea.Batch(w.updateTabsShowing(msg.Query, msg)...)
+
+	case messages.JumpToTableMsg:
+		return w, w.jumpTo(msg)
+
+	case messages.JumpHistoryMsg:
+		return w, w.travel(msg.Forward)
+
+	case messages.LoadLabelsMsg:
+		return w, loadLabelsCmd(w.registry, msg)
+
+	case messages.ColumnStatsMsg:
+		return w, columnStatsCmd(w.registry, msg)
+
+	case messages.ColumnStatsLoadedMsg:
+		return w, tea.Batch(w.updateTabsShowing(msg.Query, msg)...)
+
+	case messages.ExportMsg:
+		return w, exportCmd(w.registry, msg)
+
+	case messages.ExportProgressMsg:
+		return w, handleExportProgress(msg)
+
+	case messages.LabelsLoadedMsg:
+		return w, tea.Batch(w.updateTabsShowing(msg.Query, msg)...)
+
+	case messages.OpenNotifyTabMsg:
+		return w, w.AddNotifyTab(msg.DatabaseID)
+
+	case notifymonitor.TabMsg:
+		for i := range w.tabs {
+			if w.tabs[i].Type == TabTypeNotify && w.tabs[i].ID == msg.MonitorTabID() {
+				var cmd tea.Cmd
+				w.tabs[i].Monitor, cmd = w.tabs[i].Monitor.Update(msg)
+				return w, cmd
+			}
+		}
+		if connected, ok := msg.(notifymonitor.ListenerConnectedMsg); ok {
+			connected.Discard()
+		}
+		return w, nil
+
+	case messages.QueryNoticesMsg:
+		for i := range w.tabs {
+			if w.tabs[i].ID == msg.TabID {
+				if i != w.activeIndex {
+					w.tabs[i].UnreadNotices += msg.Count
+				}
+				break
+			}
+		}
+		return w, nil
+
 	case messages.OpenQueryTabMsg:
 		log.Printf("Opening query tab for database ID %s with query: %s\n", msg.DatabaseID, msg.Query.Compile())
 		w.AddQueryTab(msg.DatabaseID)
 		t := w.ActiveTab()
-		t.SQLCommandBar.SetContent(msg.Query.Compile())
+		t.SQLCommandBar.SetContent(query.DisplayText(msg.Query))
 	}
 
 	return w, tea.Batch(cmds...)
@@ -119,6 +231,14 @@ func (w *Workspace) HandleMouseClick(msg tea.MouseReleaseMsg) (bool, tea.Cmd) {
 // HandleKeys processes keyboard input for tab navigation
 // Returns true if the key was handled
 func (w *Workspace) HandleKeys(msg tea.KeyMsg) bool {
+	// Let the monitor's inputs receive H/L as text
+	if tab := w.ActiveTab(); tab != nil && tab.Type == TabTypeNotify && tab.Monitor.IsTyping() {
+		return false
+	}
+	// Same for the table view's search and inputs
+	if tab := w.ActiveTab(); tab != nil && tab.Type != TabTypeNotify && tab.TableView.IsTyping() {
+		return false
+	}
 	switch {
 	case key.Matches(msg, DefaultKeyMap.NextTab):
 		w.NextTab()
@@ -177,57 +297,63 @@ func FindTabByZone(zoneID string) (int, bool) {
 
 // TODO: Refactor this all this below:
 
-func executeSQLQuery(r *database.DBRegistry, q query.ExecutableQuery, databaseID string) tea.Cmd {
+func executeSQLQuery(r *database.DBRegistry, q query.ExecutableQuery, databaseID string, tab *Tab) tea.Cmd {
+	var tabID, tabName string
+	if tab != nil {
+		tabID, tabName = tab.ID, tab.Name
+	}
+	return runSQLQuery(r, q, databaseID, tabID, tabName)
+}
+
+// runSQLQuery runs q for the tab with the given ID and name. It takes them
+// rather than the tab, which the model may change or move meanwhile.
+func runSQLQuery(r *database.DBRegistry, q query.ExecutableQuery, databaseID, tabID, tabName string) tea.Cmd {
 	return func() tea.Msg {
-		db := r.GetByID(databaseID)
+		db, errMsg := connectedDatabase(r, databaseID)
 		if db == nil {
-			return tea.BatchMsg{
-				logpanel.AddLogCmd("Database with ID "+databaseID+" not found", messages.LogError),
-				notifications.ShowError("Database with ID " + databaseID + " not found"),
-			}
+			return errMsg
 		}
 		conn := db.Connection
-		if conn == nil {
-			// TODO: TMP
-			err := db.Connect()
-			if err != nil {
-				return tea.BatchMsg{
-					logpanel.AddLogCmd("Failed to connect to database: "+err.Error(), messages.LogError),
-					notifications.ShowError("Failed to connect to database: " + err.Error()),
-				}
-			}
-			conn = db.Connection
+
+		// Notices left over from work outside of a tab (tree loading etc.)
+		// are logged without a source so they are not blamed on this query.
+		staleNotices := noticeLogCmds(db.DrainNotices(), "", "")
+		withNotices := func(cmds ...tea.Cmd) tea.BatchMsg {
+			cmds = append(staleNotices, cmds...)
+			return append(cmds, noticeLogCmds(db.DrainNotices(), tabID, tabName)...)
 		}
 
 		compiledQuery := q.Compile()
+		loggedQuery := compiledQuery + formatArgs(q)
 		log.Printf("Executing SQL query: %s\n", compiledQuery)
+		if bq, ok := q.(*query.BasicSQLQuery); ok && query.IsStreamable(compiledQuery) {
+			if msg, ok := streamSQLQuery(db, bq, databaseID, loggedQuery, withNotices); ok {
+				return msg
+			}
+		}
 		startTime := time.Now()
-		rows, err := conn.Query(context.Background(), compiledQuery)
+		rows, err := conn.Query(context.Background(), compiledQuery, q.Args()...)
 		executionTime := time.Since(startTime)
 		if err != nil {
 			log.Printf("Failed to execute query %s", err.Error())
-			return tea.BatchMsg{
-				logpanel.AddLogCmd(compiledQuery, messages.LogSQL),
+			return withNotices(
+				logpanel.AddLogCmd(loggedQuery, messages.LogSQL),
 				logpanel.AddLogCmd("Failed to execute query: "+err.Error(), messages.LogError),
-				notifications.ShowError("Failed to execute query: " + err.Error()),
-			}
+				notifications.ShowError("Failed to execute query: "+err.Error()),
+			)
 		}
 		defer rows.Close()
-		fieldDescriptions := rows.FieldDescriptions()
-		columnNames := make([]string, len(fieldDescriptions))
-		for i, fd := range fieldDescriptions {
-			columnNames[i] = string(fd.Name)
-		}
+		fieldDescriptions := slices.Clone(rows.FieldDescriptions())
 		var results [][]any
 		fetchStart := time.Now()
 		for rows.Next() {
 			values, err := rows.Values()
 			if err != nil {
-				return tea.BatchMsg{
-					logpanel.AddLogCmd(compiledQuery, messages.LogSQL),
+				return withNotices(
+					logpanel.AddLogCmd(loggedQuery, messages.LogSQL),
 					logpanel.AddLogCmd("Failed to read row: "+err.Error(), messages.LogError),
-					notifications.ShowError("Failed to read row: " + err.Error()),
-				}
+					notifications.ShowError("Failed to read row: "+err.Error()),
+				)
 			}
 			results = append(results, values)
 		}
@@ -235,37 +361,407 @@ func executeSQLQuery(r *database.DBRegistry, q query.ExecutableQuery, databaseID
 		totalTime := executionTime + fetchingTime
 		log.Printf("SQL command executed, retrieved %d rows\n", len(results))
 
+		rows.Close()
 		if rows.Err() != nil {
 			log.Printf("Row iteration error: %s", rows.Err().Error())
-			return tea.BatchMsg{
-				logpanel.AddLogCmd(compiledQuery, messages.LogSQL),
+			return withNotices(
+				logpanel.AddLogCmd(loggedQuery, messages.LogSQL),
 				logpanel.AddLogCmd("Row iteration error: "+rows.Err().Error(), messages.LogError),
-				notifications.ShowError("Row iteration error: " + rows.Err().Error()),
-			}
+				notifications.ShowError("Row iteration error: "+rows.Err().Error()),
+			)
 		}
-		return tea.BatchMsg{
-			logpanel.AddLogCmd(compiledQuery, messages.LogSQL),
+		columns := db.DescribeFields(context.Background(), fieldDescriptions)
+		return withNotices(
+			logpanel.AddLogCmd(loggedQuery, messages.LogSQL),
 			logpanel.AddLogCmd(fmt.Sprintf("Executed query in %s(execution: %s, fetching: %s), retrieved %d rows", totalTime, executionTime, fetchingTime, len(results)), messages.LogSuccess),
 			func() tea.Msg {
 				return query.SQLResultMsg{
-					Columns:    columnNames,
+					Columns:    columns,
 					Rows:       results,
 					Query:      q,
 					DatabaseID: databaseID,
 				}
 			},
+		)
+	}
+}
+
+// streamSQLQuery runs a read query through a server-side cursor and returns
+// its first page; the query fetches further pages on demand. ok is false if
+// the session has state the cursor's connection would not see or the cursor
+// could not be declared, in which case the caller runs the statement
+// directly so the user sees the session's results and the server's own
+// error, if any.
+func streamSQLQuery(db *database.Database, q *query.BasicSQLQuery, databaseID, loggedQuery string, withNotices func(...tea.Cmd) tea.BatchMsg) (msg tea.Msg, ok bool) {
+	ctx := context.Background()
+	// The cursor runs on a spare connection, which would not see the
+	// session's transaction, settings or temporary tables
+	if plain, err := db.SessionIsDefault(ctx); err != nil || !plain {
+		return nil, false
+	}
+	startTime := time.Now()
+	cursor, err := db.DeclareCursor(ctx, q.Compile(), q.Args()...)
+	executionTime := time.Since(startTime)
+	if err != nil {
+		log.Printf("Cannot stream query, running it directly: %s", err.Error())
+		return nil, false
+	}
+
+	fetchStart := time.Now()
+	results, err := cursor.Fetch(ctx, query.PageSize)
+	fetchingTime := time.Since(fetchStart)
+	if err != nil {
+		log.Printf("Failed to execute query %s", err.Error())
+		return withNotices(
+			logpanel.AddLogCmd(loggedQuery, messages.LogSQL),
+			logpanel.AddLogCmd("Failed to execute query: "+err.Error(), messages.LogError),
+			notifications.ShowError("Failed to execute query: "+err.Error()),
+		), true
+	}
+
+	columns := db.DescribeFields(ctx, cursor.Fields())
+
+	more := ""
+	if !cursor.Exhausted() {
+		q.SetFetcher(cursor)
+		more = ", more rows are fetched as you page"
+	}
+	totalTime := executionTime + fetchingTime
+	return withNotices(
+		logpanel.AddLogCmd(loggedQuery, messages.LogSQL),
+		logpanel.AddLogCmd(fmt.Sprintf("Executed query in %s(execution: %s, fetching: %s), retrieved %d rows%s", totalTime, executionTime, fetchingTime, len(results), more), messages.LogSuccess),
+		func() tea.Msg {
+			return query.SQLResultMsg{
+				Columns:    columns,
+				Rows:       results,
+				Query:      q,
+				DatabaseID: databaseID,
+			}
+		},
+	), true
+}
+
+// connectedDatabase looks up a database and connects to it if needed. On
+// failure the returned message reports the error.
+func connectedDatabase(r *database.DBRegistry, databaseID string) (*database.Database, tea.Msg) {
+	db := r.GetByID(databaseID)
+	if db == nil {
+		return nil, tea.BatchMsg{
+			logpanel.AddLogCmd("Database with ID "+databaseID+" not found", messages.LogError),
+			notifications.ShowError("Database with ID " + databaseID + " not found"),
 		}
 	}
+	if db.Connection == nil {
+		// TODO: TMP
+		err := db.Connect()
+		if err != nil {
+			return nil, tea.BatchMsg{
+				logpanel.AddLogCmd("Failed to connect to database: "+err.Error(), messages.LogError),
+				notifications.ShowError("Failed to connect to database: " + err.Error()),
+			}
+		}
+	}
+	return db, nil
 }
 
-func openTableHandler(r *database.DBRegistry, table *database.Table, databaseID string) tea.Cmd {
+// countRowsCmd counts the rows matching a table query's filter on a spare
+// connection, so the tab stays usable while it runs.
+func countRowsCmd(r *database.DBRegistry, msg messages.CountRowsMsg) tea.Cmd {
+	countQuery := msg.Query.CountQuery()
+	whereClause := msg.Query.WhereClause
+	return func() tea.Msg {
+		db, errMsg := connectedDatabase(r, msg.DatabaseID)
+		if db == nil {
+			return tea.BatchMsg{
+				func() tea.Msg { return errMsg },
+				func() tea.Msg {
+					return messages.RowCountMsg{Query: msg.Query, Ctx: msg.Ctx, WhereClause: whereClause, Err: errors.New("database unavailable")}
+				},
+			}
+		}
+		startTime := time.Now()
+		count, err := db.CountRows(msg.Ctx, countQuery)
+		log.Printf("Counted rows in %s: %d, err: %v", time.Since(startTime), count, err)
+		return tea.BatchMsg{
+			logpanel.AddLogCmd(countQuery, messages.LogSQL),
+			func() tea.Msg {
+				return messages.RowCountMsg{Query: msg.Query, Ctx: msg.Ctx, WhereClause: whereClause, Count: count, Err: err}
+			},
+		}
+	}
+}
+
+// columnStatsCmd computes the statistics of a table column on a spare
+// connection, so the tab stays usable while it scans the table
+func columnStatsCmd(r *database.DBRegistry, msg messages.ColumnStatsMsg) tea.Cmd {
+	return func() tea.Msg {
+		result := messages.ColumnStatsLoadedMsg{Query: msg.Query, Ctx: msg.Ctx}
+		db, errMsg := connectedDatabase(r, msg.DatabaseID)
+		if db == nil {
+			result.Err = errors.New("database unavailable")
+			return tea.BatchMsg{
+				func() tea.Msg { return errMsg },
+				func() tea.Msg { return result },
+			}
+		}
+		statements := query.TableStatsStatements(msg.Column, msg.WhereClause)
+		if msg.Estimate {
+			statements = []database.Statement{query.EstimateStatsStatement(msg.Column)}
+		}
+		var cmds tea.BatchMsg
+		for _, stmt := range statements {
+			cmds = append(cmds, logpanel.AddLogCmd(stmt.SQL, messages.LogSQL))
+		}
+		results, err := db.ReadColumnStats(msg.Ctx, statements)
+		switch {
+		case msg.Ctx.Err() != nil:
+			cmds = append(cmds, logpanel.AddLogCmd("Column statistics cancelled", messages.LogInfo))
+			result.Err = msg.Ctx.Err()
+		case err == nil && msg.Estimate:
+			result.Stats, err = query.ParseEstimateStats(results[0])
+		case err == nil:
+			result.Stats, err = query.ParseTableStats(results, msg.Column)
+		}
+		if err != nil && msg.Ctx.Err() == nil {
+			result.Err = err
+			cmds = append(cmds,
+				logpanel.AddLogCmd("Failed to compute column statistics: "+err.Error(), messages.LogError),
+				notifications.ShowError("Failed to compute column statistics: "+err.Error()),
+			)
+		}
+		return append(cmds, func() tea.Msg { return result })
+	}
+}
+
+// handleRowCount records a finished count on its query and tells the tab
+// showing it, which need not be the active one.
+func (w *Workspace) handleRowCount(msg messages.RowCountMsg) tea.Cmd {
+	var cmds []tea.Cmd
+	switch {
+	case msg.Ctx.Err() != nil:
+		cmds = append(cmds, logpanel.AddLogCmd("Row count cancelled", messages.LogInfo))
+	case msg.Err != nil:
+		cmds = append(cmds,
+			logpanel.AddLogCmd("Failed to count rows: "+msg.Err.Error(), messages.LogError),
+			notifications.ShowError("Failed to count rows: "+msg.Err.Error()),
+		)
+	case msg.WhereClause != msg.Query.WhereClause:
+		// The filter changed while counting; the count is stale.
+		cmds = append(cmds, logpanel.AddLogCmd("Filter changed while counting, discarding row count", messages.LogInfo))
+	default:
+		msg.Query.SetExactTotal(msg.Count)
+		cmds = append(cmds, logpanel.AddLogCmd(fmt.Sprintf("Counted %d rows", msg.Count), messages.LogSuccess))
+	}
+
+	for i := range w.tabs {
+		tab := &w.tabs[i]
+		if tab.Type == TabTypeNotify || !tab.TableView.ShowsQuery(msg.Query) {
+			continue
+		}
+		model, cmd := tab.TableView.Update(msg)
+		tab.TableView = model.(tableview.TableViewModel)
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
+// applyEditsCmd runs the statements of a table tab's staged changes in one
+// transaction.
+func applyEditsCmd(r *database.DBRegistry, msg messages.ApplyEditsMsg) tea.Cmd {
+	return func() tea.Msg {
+		db, errMsg := connectedDatabase(r, msg.DatabaseID)
+		if db == nil {
+			return tea.BatchMsg{
+				func() tea.Msg { return errMsg },
+				func() tea.Msg {
+					return messages.EditsAppliedMsg{Query: msg.Query, Err: errors.New("database unavailable")}
+				},
+			}
+		}
+		inserted, err := db.ApplyRowChanges(context.Background(), msg.Statements)
+		cmds := make(tea.BatchMsg, 0, len(msg.Statements)+1)
+		for _, s := range msg.Statements {
+			cmds = append(cmds, logpanel.AddLogCmd(s.SQL, messages.LogSQL))
+		}
+		return append(cmds, func() tea.Msg {
+			return messages.EditsAppliedMsg{Query: msg.Query, Count: len(msg.Statements), Inserted: inserted, Err: err}
+		})
+	}
+}
+
+// handleEditsApplied reports saved edits and tells the tab showing the
+// query, which keeps its edits if they failed.
+func (w *Workspace) handleEditsApplied(msg messages.EditsAppliedMsg) tea.Cmd {
+	var cmds []tea.Cmd
+	if msg.Err != nil {
+		cmds = append(cmds,
+			logpanel.AddLogCmd("Failed to save changes: "+msg.Err.Error(), messages.LogError),
+			notifications.ShowError("Failed to save changes: "+msg.Err.Error()),
+		)
+	} else {
+		text := fmt.Sprintf("Saved %d changes", msg.Count)
+		cmds = append(cmds, logpanel.AddLogCmd(text, messages.LogSuccess), notifications.ShowSuccess(text))
+	}
+	return tea.Batch(append(cmds, w.updateTabsShowing(msg.Query, msg)...)...)
+}
+
+// updateTabsShowing passes msg to the table views showing q, which need not
+// be the active tab's
+func (w *Workspace) updateTabsShowing(q query.ExecutableQuery, msg tea.Msg) []tea.Cmd {
+	var cmds []tea.Cmd
+	for i := range w.tabs {
+		tab := &w.tabs[i]
+		if tab.Type == TabTypeNotify || !tab.TableView.ShowsQuery(q) {
+			continue
+		}
+		model, cmd := tab.TableView.Update(msg)
+		tab.TableView = model.(tableview.TableViewModel)
+		cmds = append(cmds, cmd)
+	}
+	return cmds
+}
+
+// describeParamsCmd prepares the statement without executing it to learn
+// the parameter types, then asks the user for values.
+func describeParamsCmd(r *database.DBRegistry, q *query.BasicSQLQuery, databaseID, tabID string) tea.Cmd {
+	return func() tea.Msg {
+		db, errMsg := connectedDatabase(r, databaseID)
+		if db == nil {
+			return errMsg
+		}
+		conn := db.Connection
+
+		sd, err := conn.PgConn().Prepare(context.Background(), "", q.Compile(), nil)
+		if err != nil {
+			return tea.BatchMsg{
+				logpanel.AddLogCmd(q.Compile(), messages.LogSQL),
+				logpanel.AddLogCmd("Failed to prepare query: "+err.Error(), messages.LogError),
+				notifications.ShowError("Failed to prepare query: " + err.Error()),
+			}
+		}
+
+		types := make([]string, len(q.Params.Labels))
+		for i, oid := range sd.ParamOIDs {
+			if i >= len(types) {
+				break
+			}
+			if t, ok := conn.TypeMap().TypeForOID(oid); ok {
+				types[i] = t.Name
+			} else {
+				types[i] = fmt.Sprintf("oid %d", oid)
+			}
+		}
+		return messages.QueryParamsRequestMsg{
+			Query:      q,
+			Types:      types,
+			DatabaseID: databaseID,
+			TabID:      tabID,
+		}
+	}
+}
+
+// formatArgs renders bind parameter values as SQL comments for the log panel
+func formatArgs(q query.ExecutableQuery) string {
+	args := q.Args()
+	if len(args) == 0 {
+		return ""
+	}
+	var labels []string
+	if bq, ok := q.(*query.BasicSQLQuery); ok {
+		labels = bq.Params.Labels
+	}
+	var b strings.Builder
+	for i, arg := range args {
+		label := fmt.Sprintf("$%d", i+1)
+		if i < len(labels) && labels[i] != label {
+			label += " " + labels[i]
+		}
+		value := "NULL"
+		if arg != nil {
+			value = "'" + strings.ReplaceAll(fmt.Sprint(arg), "'", "''") + "'"
+		}
+		fmt.Fprintf(&b, "\n-- %s = %s", label, value)
+	}
+	return b.String()
+}
+
+// noticeLogCmds turns server notices into log entries attributed to the tab
+// that ran the statement, and reports the count so the tab can show a badge.
+func noticeLogCmds(notices []*pgconn.Notice, tabID, tabName string) []tea.Cmd {
+	if len(notices) == 0 {
+		return nil
+	}
+	cmds := make([]tea.Cmd, 0, len(notices)+1)
+	for _, n := range notices {
+		text := fmt.Sprintf("%s: %s", n.Severity, n.Message)
+		if n.Detail != "" {
+			text += "\n  DETAIL: " + n.Detail
+		}
+		if n.Hint != "" {
+			text += "\n  HINT: " + n.Hint
+		}
+		if n.Where != "" {
+			text += "\n  CONTEXT: " + n.Where
+		}
+		cmds = append(cmds, logpanel.AddSourceLogCmd(text, messages.LogNotice, tabName))
+	}
+	if tabID != "" {
+		count := len(notices)
+		cmds = append(cmds, func() tea.Msg {
+			return messages.QueryNoticesMsg{TabID: tabID, Count: count}
+		})
+	}
+	return cmds
+}
+
+// openTableHandler reads the first page of a table into tab, filtered by
+// whereClause if it is not empty
+func openTableHandler(r *database.DBRegistry, table *database.Table, databaseID, whereClause string, tab *Tab) tea.Cmd {
 	log.Printf("Opening table %s\n", table.Name)
+	var tabID, tabName string
+	if tab != nil {
+		tabID, tabName = tab.ID, tab.Name
+	}
 	return tea.Batch(
 		logpanel.AddLogCmd(fmt.Sprintf("Opening table: %s", table.Name), messages.LogInfo),
 		func() tea.Msg {
-			baseQuery := fmt.Sprintf("SELECT * FROM \"%s\"", table.Name)
+			// Qualified, as jumps by foreign key can lead out of the search path
+			baseQuery := fmt.Sprintf("SELECT * FROM \"%s\".\"%s\"", table.Schema.Name, table.Name)
 			q := query.NewTableQuery(baseQuery, 500)
-			return executeSQLQuery(r, q, databaseID)()
+			q.WhereClause = whereClause
+
+			var pagingLog tea.Cmd
+			keyColumns, err := table.LoadKeyColumns()
+			switch {
+			case err != nil:
+				log.Printf("Failed to load key columns for %s: %v", table.Name, err)
+				pagingLog = logpanel.AddLogCmd("Could not look up a key for "+table.Name+", paginating with OFFSET: "+err.Error(), messages.LogWarning)
+			case len(keyColumns) == 0:
+				pagingLog = logpanel.AddLogCmd("No primary key or unique NOT NULL index on "+table.Name+", paginating with OFFSET", messages.LogInfo)
+			default:
+				q.SetKeyColumns(keyColumns)
+			}
+			if columns, err := table.LoadColumnsForTable(); err != nil {
+				log.Printf("Failed to load column defaults for %s: %v", table.Name, err)
+			} else {
+				q.ColumnDefaults = map[string]string{}
+				for _, col := range columns {
+					if col.ColumnDefault.Valid {
+						q.ColumnDefaults[col.Name] = col.ColumnDefault.String
+					}
+				}
+			}
+			if estimate, err := table.EstimateRowCount(); err != nil {
+				log.Printf("Failed to estimate row count for %s: %v", table.Name, err)
+			} else {
+				q.EstimatedTotal = estimate
+			}
+
+			msg := runSQLQuery(r, q, databaseID, tabID, tabName)()
+			if pagingLog == nil {
+				return msg
+			}
+			return tea.BatchMsg{pagingLog, func() tea.Msg { return msg }}
 		},
 	)
 }
@@ -34,13 +34,7 @@ func (w *Workspace) RenderTabBar() string {
 		tab := w.tabs[idx]
 		isActive := idx == w.activeIndex
 
-		// Build tab content: icon + name + close button
-		icon := iconStyle(tab.Type, isActive).Render(tab.Icon())
-		name := tabNameStyle(isActive).Render(tab.Name)
-		space := tabSpaceStyle(isActive).Render(" ")
-		closeBtn := closeButtonStyle(isActive).Render("×")
-
-		content := icon + name + space + closeBtn
+		content := tabContent(tab, isActive)
 
 		var tabView string
 		if isActive {
@@ -76,6 +70,21 @@ func (w *Workspace) RenderTabBar() string {
 	return row
 }
 
+// tabContent builds the inner content of a tab: icon + name + notice badge + close button
+func tabContent(tab Tab, isActive bool) string {
+	icon := iconStyle(tab.Type, isActive).Render(tab.Icon())
+	name := tabNameStyle(isActive).Render(tab.Name)
+	space := tabSpaceStyle(isActive).Render(" ")
+	closeBtn := closeButtonStyle(isActive).Render("×")
+
+	badge := ""
+	if !isActive && tab.UnreadNotices > 0 {
+		badge = noticeBadgeStyle().Render(fmt.Sprintf("%d", tab.UnreadNotices))
+	}
+
+	return icon + name + badge + space + closeBtn
+}
+
 // calculateVisibleTabs returns the indices of tabs that should be visible
 func (w *Workspace) calculateVisibleTabs() []int {
 	if w.width <= 0 {
@@ -91,7 +100,12 @@ func (w *Workspace) calculateVisibleTabs() []int {
 	// This is approximate; actual width depends on tab name length
 	estimateTabWidth := func(tab Tab) int {
 		// icon (2) + space (1) + name + space (1) + close (1) + padding (2) + borders (2)
-		return len(tab.Name) + 9
+		width := len(tab.Name) + 9
+		if tab.UnreadNotices > 0 {
+			// badge digits + margin
+			width += len(fmt.Sprintf("%d", tab.UnreadNotices)) + 1
+		}
+		return width
 	}
 
 	var visibleIndices []int
@@ -155,11 +169,7 @@ func (w *Workspace) GetTabIndexAtPosition(x int) int {
 		isActive := idx == w.activeIndex
 
 		// Calculate tab width
-		icon := iconStyle(tab.Type, isActive).Render(tab.Icon())
-		name := tabNameStyle(isActive).Render(tab.Name)
-		space := tabSpaceStyle(isActive).Render(" ")
-		closeBtn := closeButtonStyle(isActive).Render("×")
-		content := icon + name + space + closeBtn
+		content := tabContent(tab, isActive)
 
 		var tabWidth int
 		if isActive {
@@ -187,11 +197,7 @@ func (w *Workspace) IsCloseButtonClick(tabIndex, relativeX int) bool {
 	isActive := tabIndex == w.activeIndex
 
 	// Calculate tab content width
-	icon := iconStyle(tab.Type, isActive).Render(tab.Icon())
-	name := tabNameStyle(isActive).Render(tab.Name)
-	space := tabSpaceStyle(isActive).Render(" ")
-	closeBtn := closeButtonStyle(isActive).Render("×")
-	content := icon + name + space + closeBtn
+	content := tabContent(tab, isActive)
 
 	var style lipgloss.Style
 	if isActive {
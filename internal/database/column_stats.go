@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ReadColumnStats runs the statements computing a column's statistics on a
+// spare connection, so that a scan of a large table does not block the tab.
+// It returns the rows of each statement, in order. Cancelling ctx cancels
+// the running statement on the server.
+func (db *Database) ReadColumnStats(ctx context.Context, statements []Statement) ([][][]any, error) {
+	conn, err := db.acquireSpareConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer db.releaseSpareConn(context.Background(), conn)
+
+	results := make([][][]any, len(statements))
+	for i, stmt := range statements {
+		rows, err := conn.Query(ctx, stmt.SQL, stmt.Args...)
+		if err != nil {
+			return nil, fmt.Errorf("statement %d of %d: %w", i+1, len(statements), err)
+		}
+		results[i], err = pgx.CollectRows(rows, func(row pgx.CollectableRow) ([]any, error) {
+			return row.Values()
+		})
+		if err != nil {
+			return nil, fmt.Errorf("statement %d of %d: %w", i+1, len(statements), err)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadColumnStats(t *testing.T) {
+	db, cleanup := SetupTestDatabase(t)
+	defer cleanup()
+
+	CreateSchema(t, db, "stats_schema")
+	defer DropSchemas(t, db, "stats_schema")
+
+	ExecQueries(t, db,
+		`CREATE TABLE stats_schema.items AS SELECT g AS id, CASE WHEN g % 4 = 0 THEN NULL ELSE repeat('x', g % 3 + 1) END AS name FROM generate_series(1, 100) g`,
+	)
+
+	results, err := db.ReadColumnStats(context.Background(), []Statement{
+		{SQL: `SELECT count(*), count(name), percentile_disc(ARRAY[0, 0.5, 1]) WITHIN GROUP (ORDER BY length(name)) FROM stats_schema.items`},
+		{SQL: `SELECT name, count(*) FROM stats_schema.items WHERE id > $1 AND name IS NOT NULL GROUP BY 1 ORDER BY 2 DESC, 1 LIMIT 1`, Args: []any{90}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, [][]any{{int64(100), int64(75), []any{int32(1), int32(2), int32(3)}}}, results[0])
+	assert.Equal(t, [][]any{{"xx", int64(3)}}, results[1])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = db.ReadColumnStats(ctx, []Statement{{SQL: `SELECT count(*) FROM stats_schema.items`}})
+	assert.Error(t, err)
+}
@@ -11,8 +11,12 @@ func (db *Database) Connect() error {
 	if db.Connected {
 		return nil
 	}
-	uri := fmt.Sprintf("postgres://%s:%s@%s:%d/%s", db.Username, db.Password, db.Host, db.Port, db.Database)
-	conn, err := pgx.Connect(context.Background(), uri)
+	config, err := db.connConfig()
+	if err != nil {
+		return err
+	}
+	config.OnNotice = db.handleNotice
+	conn, err := pgx.ConnectConfig(context.Background(), config)
 	if err != nil {
 		return err
 	}
@@ -21,7 +25,14 @@ func (db *Database) Connect() error {
 	return nil
 }
 
+// connConfig builds the pgx connection config for the database
+func (db *Database) connConfig() (*pgx.ConnConfig, error) {
+	uri := fmt.Sprintf("postgres://%s:%s@%s:%d/%s", db.Username, db.Password, db.Host, db.Port, db.Database)
+	return pgx.ParseConfig(uri)
+}
+
 func (db *Database) Disconnect() error {
+	db.closeIdleSpareConn(context.Background())
 	if db.Connected {
 		db.Connected = false
 		return db.Connection.Close(context.Background())
@@ -0,0 +1,162 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrCursorClosed is returned by Cursor.Fetch after the cursor was closed.
+var ErrCursorClosed = errors.New("cursor closed")
+
+// cursorName is the name of the server-side cursor. Every cursor has a
+// connection of its own, so the name never clashes.
+const cursorName = "dbettier_cursor"
+
+// Cursor streams the result of a query through a server-side cursor, so only
+// the rows that are fetched are held in memory.
+//
+// A cursor lives inside a transaction, which would block the main connection
+// for as long as the result is being browsed. Each cursor therefore runs on a
+// dedicated connection. Session state of the main connection (an open
+// transaction, temporary tables, SET) is not visible to it, so callers check
+// SessionIsDefault before declaring one.
+type Cursor struct {
+	db     *Database
+	conn   *pgx.Conn
+	fields []pgconn.FieldDescription
+
+	mu        sync.Mutex
+	fetched   int
+	exhausted bool
+	closed    bool
+}
+
+// sessionIsDefaultSQL reports whether the main connection's session has
+// the settings, role and schemas a newly opened connection has
+const sessionIsDefaultSQL = `SELECT NOT EXISTS (SELECT 1 FROM pg_settings WHERE source = 'session')
+	AND current_user = session_user
+	AND pg_my_temp_schema() = 0`
+
+// SessionIsDefault reports whether a query runs on a spare connection as it
+// would on the main one: the main connection has no open transaction, no
+// settings changed with SET, no role taken with SET ROLE and no temporary
+// tables.
+func (db *Database) SessionIsDefault(ctx context.Context) (bool, error) {
+	if db.Connection.PgConn().TxStatus() != 'I' {
+		return false, nil
+	}
+	var plain bool
+	err := db.Connection.QueryRow(ctx, sessionIsDefaultSQL).Scan(&plain)
+	return plain, err
+}
+
+// DeclareCursor opens a cursor for a single SELECT, VALUES or TABLE
+// statement. No rows are read until Fetch is called.
+func (db *Database) DeclareCursor(ctx context.Context, sql string, args ...any) (*Cursor, error) {
+	conn, err := db.acquireSpareConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Exec(ctx, "BEGIN"); err != nil {
+		db.releaseSpareConn(ctx, conn)
+		return nil, err
+	}
+	declare := fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s", cursorName, sql)
+	if _, err := conn.Exec(ctx, declare, args...); err != nil {
+		_, _ = conn.Exec(ctx, "ROLLBACK")
+		db.releaseSpareConn(ctx, conn)
+		return nil, err
+	}
+	return &Cursor{db: db, conn: conn}, nil
+}
+
+// Fetch reads up to n further rows. Once fewer than n rows come back the
+// cursor is exhausted and closed.
+func (c *Cursor) Fetch(ctx context.Context, n int) ([][]any, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil, ErrCursorClosed
+	}
+
+	rows, err := c.conn.Query(ctx, fmt.Sprintf("FETCH FORWARD %d FROM %s", n, cursorName))
+	if err != nil {
+		c.closeLocked(ctx)
+		return nil, err
+	}
+	defer rows.Close()
+	if c.fields == nil {
+		// pgconn reuses the slice for the connection's next statement
+		c.fields = slices.Clone(rows.FieldDescriptions())
+	}
+
+	var result [][]any
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			rows.Close()
+			c.closeLocked(ctx)
+			return nil, err
+		}
+		result = append(result, values)
+	}
+	if err := rows.Err(); err != nil {
+		c.closeLocked(ctx)
+		return nil, err
+	}
+
+	c.fetched += len(result)
+	if len(result) < n {
+		c.exhausted = true
+		rows.Close()
+		c.closeLocked(ctx)
+	}
+	return result, nil
+}
+
+// Fields returns the result columns. They are known after the first Fetch.
+func (c *Cursor) Fields() []pgconn.FieldDescription {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.fields
+}
+
+// Fetched returns the number of rows read so far.
+func (c *Cursor) Fetched() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.fetched
+}
+
+// Exhausted reports whether every row of the result has been read.
+func (c *Cursor) Exhausted() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.exhausted
+}
+
+// Close ends the cursor's transaction and frees its connection. Closing an
+// already closed cursor is a no-op.
+func (c *Cursor) Close(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeLocked(ctx)
+}
+
+func (c *Cursor) closeLocked(ctx context.Context) error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	// COMMIT rather than ROLLBACK: functions called by the query may have
+	// side effects the user expects to persist, as they would in autocommit.
+	_, err := c.conn.Exec(ctx, "COMMIT")
+	c.db.releaseSpareConn(ctx, c.conn)
+	return err
+}
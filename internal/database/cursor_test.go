@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorFetch(t *testing.T) {
+	db, cleanup := SetupTestDatabase(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cursor, err := db.DeclareCursor(ctx, "SELECT n FROM generate_series(1, $1::int) AS n", 1200)
+	require.NoError(t, err)
+
+	var total int
+	for _, want := range []int{500, 500, 200} {
+		rows, err := cursor.Fetch(ctx, 500)
+		require.NoError(t, err)
+		assert.Len(t, rows, want)
+		total += len(rows)
+	}
+	assert.Equal(t, 1200, total)
+	assert.Equal(t, 1200, cursor.Fetched())
+	assert.True(t, cursor.Exhausted(), "Cursor should be exhausted after a short fetch")
+	require.Len(t, cursor.Fields(), 1)
+	assert.Equal(t, "n", cursor.Fields()[0].Name)
+
+	_, err = cursor.Fetch(ctx, 500)
+	assert.ErrorIs(t, err, ErrCursorClosed)
+
+	// The connection is reused for the next cursor
+	cursor, err = db.DeclareCursor(ctx, "SELECT 1")
+	require.NoError(t, err)
+	require.NoError(t, cursor.Close(ctx))
+	require.NoError(t, cursor.Close(ctx), "Closing twice should be a no-op")
+}
+
+func TestDeclareCursorRejectsWrites(t *testing.T) {
+	db, cleanup := SetupTestDatabase(t)
+	defer cleanup()
+
+	CreateSchema(t, db, "cursor_schema")
+	defer DropSchemas(t, db, "cursor_schema")
+	ExecQueries(t, db, `CREATE TABLE cursor_schema.items (id int)`)
+
+	_, err := db.DeclareCursor(context.Background(),
+		"WITH d AS (DELETE FROM cursor_schema.items RETURNING id) SELECT * FROM d")
+	assert.Error(t, err, "DECLARE should reject data-modifying WITH")
+}
+
+func TestSessionIsDefault(t *testing.T) {
+	db, cleanup := SetupTestDatabase(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	plain, err := db.SessionIsDefault(ctx)
+	require.NoError(t, err)
+	assert.True(t, plain)
+
+	ExecQueries(t, db, "BEGIN")
+	plain, err = db.SessionIsDefault(ctx)
+	require.NoError(t, err)
+	assert.False(t, plain, "An open transaction is not visible to a spare connection")
+	ExecQueries(t, db, "ROLLBACK")
+
+	ExecQueries(t, db, "SET search_path TO pg_catalog")
+	plain, err = db.SessionIsDefault(ctx)
+	require.NoError(t, err)
+	assert.False(t, plain, "SET is not visible to a spare connection")
+	ExecQueries(t, db, "RESET search_path")
+
+	ExecQueries(t, db, "CREATE TEMP TABLE session_items (id int)")
+	plain, err = db.SessionIsDefault(ctx)
+	require.NoError(t, err)
+	assert.False(t, plain, "Temporary tables are not visible to a spare connection")
+}
@@ -5,8 +5,10 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"sync"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type Database struct {
@@ -19,6 +21,16 @@ type Database struct {
 	Connection *pgx.Conn `json:"-"`
 	Schemas    []*Schema `json:"-"`
 	ID         string    `json:"id"`
+
+	// Notices received from the server (RAISE NOTICE, warnings) that
+	// have not been collected yet. See DrainNotices.
+	notices   []*pgconn.Notice
+	noticesMu sync.Mutex
+
+	// Connection kept open between uses of a spare connection.
+	// See acquireSpareConn.
+	idleSpareConn *pgx.Conn
+	spareMu       sync.Mutex
 }
 
 func NewDatabase(host, username, password string, port int, database string) *Database {
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ForeignKey is a foreign key constraint: Columns of Schema.Table reference
+// RefColumns of RefSchema.RefTable, position by position
+type ForeignKey struct {
+	Name       string
+	Schema     string
+	Table      string
+	Columns    []string
+	RefSchema  string
+	RefTable   string
+	RefColumns []string
+}
+
+// LoadForeignKeys returns the foreign keys of the table (outgoing) and the
+// foreign keys of tables referencing it (incoming). A self-referencing key
+// is in both.
+func (t *Table) LoadForeignKeys() (outgoing, incoming []ForeignKey, err error) {
+	db := t.Schema.Database
+	if !db.Connected {
+		if err := db.Connect(); err != nil {
+			return nil, nil, err
+		}
+	}
+	q := `SELECT con.conname::text, n.nspname::text, c.relname::text,
+       ARRAY(SELECT a.attname::text
+               FROM unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+               JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+              ORDER BY k.ord),
+       rn.nspname::text, rc.relname::text,
+       ARRAY(SELECT a.attname::text
+               FROM unnest(con.confkey) WITH ORDINALITY AS k(attnum, ord)
+               JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum
+              ORDER BY k.ord)
+  FROM pg_constraint con
+  JOIN pg_class c ON c.oid = con.conrelid
+  JOIN pg_namespace n ON n.oid = c.relnamespace
+  JOIN pg_class rc ON rc.oid = con.confrelid
+  JOIN pg_namespace rn ON rn.oid = rc.relnamespace
+ WHERE con.contype = 'f'
+   AND ((n.nspname = $1 AND c.relname = $2) OR (rn.nspname = $1 AND rc.relname = $2))
+ ORDER BY n.nspname, c.relname, con.conname`
+	rows, err := db.Connection.Query(context.Background(), q, t.Schema.Name, t.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	keys, err := pgx.CollectRows(rows, pgx.RowToStructByPos[ForeignKey])
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, fk := range keys {
+		if fk.Schema == t.Schema.Name && fk.Table == t.Name {
+			outgoing = append(outgoing, fk)
+		}
+		if fk.RefSchema == t.Schema.Name && fk.RefTable == t.Name {
+			incoming = append(incoming, fk)
+		}
+	}
+	return outgoing, incoming, nil
+}
+
+// FindTable returns the named table from the loaded schemas. Schemas and
+// tables that were not loaded yet are made up, so a table can be opened
+// before the tree shows it.
+func (db *Database) FindTable(schemaName, name string) *Table {
+	var schema *Schema
+	for _, s := range db.Schemas {
+		if s.Name == schemaName {
+			schema = s
+			break
+		}
+	}
+	if schema == nil {
+		schema = NewSchema(schemaName, db)
+	}
+	if table := schema.FindTable(name); table != nil {
+		return table
+	}
+	return NewTable(name, schema, baseTableType)
+}
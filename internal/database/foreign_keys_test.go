@@ -0,0 +1,52 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadForeignKeys(t *testing.T) {
+	db, cleanup := SetupTestDatabase(t)
+	defer cleanup()
+
+	CreateSchema(t, db, "fk_schema")
+	defer DropSchemas(t, db, "fk_schema")
+
+	ExecQueries(t, db,
+		`CREATE TABLE fk_schema.tenants (id INT PRIMARY KEY)`,
+		`CREATE TABLE fk_schema.users (
+			tenant INT NOT NULL REFERENCES fk_schema.tenants,
+			id INT NOT NULL,
+			manager INT,
+			PRIMARY KEY (tenant, id),
+			CONSTRAINT users_manager_fkey FOREIGN KEY (tenant, manager) REFERENCES fk_schema.users (tenant, id)
+		)`,
+		`CREATE TABLE fk_schema.orders (
+			id INT PRIMARY KEY,
+			tenant INT,
+			buyer INT,
+			CONSTRAINT orders_buyer_fkey FOREIGN KEY (buyer, tenant) REFERENCES fk_schema.users (id, tenant)
+		)`,
+	)
+
+	table := db.FindTable("fk_schema", "users")
+	outgoing, incoming, err := table.LoadForeignKeys()
+	require.NoError(t, err)
+
+	require.Len(t, outgoing, 2)
+	assert.Equal(t, ForeignKey{
+		Name: "users_manager_fkey", Schema: "fk_schema", Table: "users", Columns: []string{"tenant", "manager"},
+		RefSchema: "fk_schema", RefTable: "users", RefColumns: []string{"tenant", "id"},
+	}, outgoing[0])
+	assert.Equal(t, "tenants", outgoing[1].RefTable)
+	assert.Equal(t, []string{"tenant"}, outgoing[1].Columns)
+	assert.Equal(t, []string{"id"}, outgoing[1].RefColumns)
+
+	require.Len(t, incoming, 2)
+	assert.Equal(t, "orders", incoming[0].Table)
+	assert.Equal(t, []string{"buyer", "tenant"}, incoming[0].Columns)
+	assert.Equal(t, []string{"id", "tenant"}, incoming[0].RefColumns)
+	assert.Equal(t, "users_manager_fkey", incoming[1].Name)
+}
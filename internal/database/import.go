@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrImportClosed is returned by Import.Copy after the import was committed
+// or rolled back.
+var ErrImportClosed = errors.New("import closed")
+
+// Import loads rows into a table with COPY, in batches, inside a single
+// transaction on a dedicated connection. Nothing is visible to other
+// sessions until Commit, and Rollback discards every batch copied so far.
+type Import struct {
+	db      *Database
+	conn    *pgx.Conn
+	table   pgx.Identifier
+	columns []string
+
+	mu     sync.Mutex
+	copied int64
+	closed bool
+}
+
+// BeginImport starts an import into schema.table for the given columns.
+// When createSQL is not empty it is run first, in the same transaction, so
+// a table created for the import disappears again if the import fails.
+func (db *Database) BeginImport(ctx context.Context, schema, table string, columns []string, createSQL string) (*Import, error) {
+	conn, err := db.acquireSpareConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Exec(ctx, "BEGIN"); err != nil {
+		db.releaseSpareConn(ctx, conn)
+		return nil, err
+	}
+	if createSQL != "" {
+		if _, err := conn.Exec(ctx, createSQL); err != nil {
+			_, _ = conn.Exec(ctx, "ROLLBACK")
+			db.releaseSpareConn(ctx, conn)
+			return nil, err
+		}
+	}
+	return &Import{
+		db:      db,
+		conn:    conn,
+		table:   pgx.Identifier{schema, table},
+		columns: columns,
+	}, nil
+}
+
+// Copy writes a batch of rows, each holding one value per column. A failed
+// batch rolls the whole import back.
+func (i *Import) Copy(ctx context.Context, rows [][]any) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.closed {
+		return ErrImportClosed
+	}
+	n, err := i.conn.CopyFrom(ctx, i.table, i.columns, pgx.CopyFromRows(rows))
+	if err != nil {
+		i.endLocked(ctx, "ROLLBACK")
+		return err
+	}
+	i.copied += n
+	return nil
+}
+
+// Copied returns the number of rows written so far.
+func (i *Import) Copied() int64 {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.copied
+}
+
+// Commit makes the imported rows visible and frees the connection.
+func (i *Import) Commit(ctx context.Context) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.closed {
+		return ErrImportClosed
+	}
+	return i.endLocked(ctx, "COMMIT")
+}
+
+// Rollback discards the imported rows and frees the connection. Rolling
+// back an already closed import is a no-op.
+func (i *Import) Rollback(ctx context.Context) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.closed {
+		return nil
+	}
+	return i.endLocked(ctx, "ROLLBACK")
+}
+
+func (i *Import) endLocked(ctx context.Context, statement string) error {
+	i.closed = true
+	_, err := i.conn.Exec(ctx, statement)
+	i.db.releaseSpareConn(ctx, i.conn)
+	return err
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImport(t *testing.T) {
+	db, cleanup := SetupTestDatabase(t)
+	defer cleanup()
+
+	CreateSchema(t, db, "import_schema")
+	defer DropSchemas(t, db, "import_schema")
+	ExecQueries(t, db, `CREATE TABLE import_schema.items (id bigint PRIMARY KEY, name text)`)
+
+	ctx := context.Background()
+	imp, err := db.BeginImport(ctx, "import_schema", "items", []string{"id", "name"}, "")
+	require.NoError(t, err)
+	require.NoError(t, imp.Copy(ctx, [][]any{{int64(1), "a"}, {int64(2), nil}}))
+	require.NoError(t, imp.Copy(ctx, [][]any{{int64(3), "c"}}))
+	assert.Equal(t, int64(3), imp.Copied())
+	require.NoError(t, imp.Commit(ctx))
+
+	var count int
+	require.NoError(t, db.Connection.QueryRow(ctx, "SELECT count(*) FROM import_schema.items").Scan(&count))
+	assert.Equal(t, 3, count)
+
+	// A failing batch rolls back every batch before it
+	imp, err = db.BeginImport(ctx, "import_schema", "items", []string{"id"}, "")
+	require.NoError(t, err)
+	require.NoError(t, imp.Copy(ctx, [][]any{{int64(4)}}))
+	assert.Error(t, imp.Copy(ctx, [][]any{{int64(1)}}), "Duplicate key should fail")
+	assert.ErrorIs(t, imp.Copy(ctx, [][]any{{int64(5)}}), ErrImportClosed)
+	require.NoError(t, imp.Rollback(ctx), "Rolling back a closed import should be a no-op")
+	require.NoError(t, db.Connection.QueryRow(ctx, "SELECT count(*) FROM import_schema.items").Scan(&count))
+	assert.Equal(t, 3, count)
+
+	// A created table goes away with a rolled back import
+	imp, err = db.BeginImport(ctx, "import_schema", "fresh", []string{"n"},
+		`CREATE TABLE "import_schema"."fresh" ("n" bigint)`)
+	require.NoError(t, err)
+	require.NoError(t, imp.Copy(ctx, [][]any{{int64(1)}}))
+	require.NoError(t, imp.Rollback(ctx))
+	var exists bool
+	require.NoError(t, db.Connection.QueryRow(ctx, "SELECT to_regclass('import_schema.fresh') IS NOT NULL").Scan(&exists))
+	assert.False(t, exists)
+}
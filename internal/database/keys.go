@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// LoadKeyColumns returns the columns that uniquely identify a row of the
+// table: the primary key, or else the smallest unique index whose columns
+// are all NOT NULL. Partial and expression indexes are ignored. It returns
+// nil if the table has no such key.
+func (t *Table) LoadKeyColumns() ([]string, error) {
+	db := t.Schema.Database
+	if !db.Connected {
+		if err := db.Connect(); err != nil {
+			return nil, err
+		}
+	}
+	q := `SELECT array_agg(a.attname::text ORDER BY k.ord)
+  FROM pg_index i
+  JOIN pg_class c ON c.oid = i.indrelid
+  JOIN pg_namespace n ON n.oid = c.relnamespace
+ CROSS JOIN LATERAL unnest(i.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+  JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = k.attnum
+ WHERE n.nspname = $1
+   AND c.relname = $2
+   AND i.indisunique
+   AND i.indisvalid
+   AND i.indpred IS NULL
+   AND i.indexprs IS NULL
+   AND k.ord <= i.indnkeyatts
+ GROUP BY i.indexrelid, i.indisprimary
+HAVING bool_and(a.attnotnull)
+ ORDER BY i.indisprimary DESC, count(*), i.indexrelid
+ LIMIT 1`
+	var columns []string
+	err := db.Connection.QueryRow(context.Background(), q, t.Schema.Name, t.Name).Scan(&columns)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return columns, err
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadKeyColumns(t *testing.T) {
+	db, cleanup := SetupTestDatabase(t)
+	defer cleanup()
+
+	CreateSchema(t, db, "key_schema")
+	defer DropSchemas(t, db, "key_schema")
+
+	ExecQueries(t, db,
+		`CREATE TABLE key_schema.with_pk (
+			tenant INT NOT NULL,
+			id INT NOT NULL,
+			code TEXT NOT NULL UNIQUE,
+			PRIMARY KEY (tenant, id)
+		)`,
+		`CREATE TABLE key_schema.with_unique (
+			code TEXT NOT NULL,
+			email TEXT UNIQUE,
+			CONSTRAINT with_unique_code UNIQUE (code)
+		)`,
+		`CREATE TABLE key_schema.nullable_unique (
+			email TEXT UNIQUE
+		)`,
+		`CREATE TABLE key_schema.no_key (value TEXT)`,
+	)
+
+	schemas, err := db.ParseSchemas()
+	require.NoError(t, err)
+
+	var schema *Schema
+	for _, s := range schemas {
+		if s.Name == "key_schema" {
+			schema = s
+		}
+	}
+	require.NotNil(t, schema)
+	_, err = schema.LoadTables()
+	require.NoError(t, err)
+
+	tests := map[string][]string{
+		"with_pk":         {"tenant", "id"},
+		"with_unique":     {"code"},
+		"nullable_unique": nil,
+		"no_key":          nil,
+	}
+	for tableName, want := range tests {
+		table := schema.FindTable(tableName)
+		require.NotNil(t, table, tableName)
+		got, err := table.LoadKeyColumns()
+		require.NoError(t, err, tableName)
+		assert.Equal(t, want, got, tableName)
+	}
+}
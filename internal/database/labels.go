@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// LabelColumns maps "schema.table" or "table" to the column that labels
+// the table's rows in foreign key lookups, overriding the guess by name
+var LabelColumns = map[string]string{}
+
+// labelColumnNames are the column names tried, in order, to label rows
+var labelColumnNames = []string{"name", "title", "label", "email", "username", "display_name", "full_name", "code", "slug"}
+
+// LabelColumn returns the column whose value tells a person which row of
+// the table a foreign key points to, or "" if none looks fit
+func (t *Table) LabelColumn() (string, error) {
+	columns, err := t.LoadColumnsForTable()
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name
+	}
+	return chooseLabelColumn(t.Schema.Name, t.Name, names), nil
+}
+
+// chooseLabelColumn picks the configured label column of a table, or
+// else the first of labelColumnNames it has, or else a column named like
+// company_name or job_title
+func chooseLabelColumn(schema, table string, names []string) string {
+	for _, key := range []string{schema + "." + table, table} {
+		if name, ok := LabelColumns[key]; ok && slices.Contains(names, name) {
+			return name
+		}
+	}
+	for _, name := range labelColumnNames {
+		if slices.Contains(names, name) {
+			return name
+		}
+	}
+	for _, name := range names {
+		if strings.HasSuffix(name, "_name") || strings.HasSuffix(name, "_title") {
+			return name
+		}
+	}
+	return ""
+}
+
+// LookupLabels runs a label lookup on a spare connection, so that it does
+// not wait for or block the statements of the tab
+func (db *Database) LookupLabels(ctx context.Context, sql string, args ...any) ([][]any, error) {
+	conn, err := db.acquireSpareConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer db.releaseSpareConn(context.Background(), conn)
+
+	rows, err := conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) ([]any, error) {
+		return row.Values()
+	})
+}
@@ -0,0 +1,21 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChooseLabelColumn(t *testing.T) {
+	assert.Equal(t, "name", chooseLabelColumn("public", "customers", []string{"id", "email", "name"}))
+	assert.Equal(t, "email", chooseLabelColumn("public", "users", []string{"id", "email"}))
+	assert.Equal(t, "company_name", chooseLabelColumn("public", "accounts", []string{"id", "company_name"}))
+	assert.Equal(t, "", chooseLabelColumn("public", "events", []string{"id", "payload"}))
+
+	LabelColumns = map[string]string{"public.customers": "email", "orders": "reference", "users": "missing"}
+	defer func() { LabelColumns = map[string]string{} }()
+	assert.Equal(t, "email", chooseLabelColumn("public", "customers", []string{"id", "email", "name"}))
+	assert.Equal(t, "reference", chooseLabelColumn("sales", "orders", []string{"id", "reference"}))
+	// A configured column the table lacks falls back to the guess
+	assert.Equal(t, "email", chooseLabelColumn("public", "users", []string{"id", "email"}))
+}
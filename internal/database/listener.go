@@ -0,0 +1,157 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrListenerClosed is returned by Listener methods after Close was called.
+var ErrListenerClosed = errors.New("listener closed")
+
+// Listener is a dedicated connection for LISTEN/NOTIFY. Waiting for a
+// notification blocks the connection, so it can't share the main one used
+// for queries.
+//
+// Wait is expected to be called from a single goroutine in a loop. Other
+// statements (Listen, Unlisten, Notify) interrupt the pending wait, run, and
+// let the wait resume afterwards.
+type Listener struct {
+	conn *pgx.Conn
+
+	mu         sync.Mutex
+	cond       *sync.Cond
+	busy       bool // conn is in use
+	pending    int  // statements waiting for the conn
+	cancelWait context.CancelFunc
+	closed     bool
+}
+
+// NewListener opens a new connection to the database for LISTEN/NOTIFY.
+func (db *Database) NewListener(ctx context.Context) (*Listener, error) {
+	config, err := db.connConfig()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := pgx.ConnectConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	l := &Listener{conn: conn}
+	l.cond = sync.NewCond(&l.mu)
+	return l, nil
+}
+
+// PID returns the backend process ID of the listener connection.
+func (l *Listener) PID() uint32 {
+	return l.conn.PgConn().PID()
+}
+
+// Listen subscribes to a channel.
+func (l *Listener) Listen(ctx context.Context, channel string) error {
+	return l.exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize())
+}
+
+// Unlisten unsubscribes from a channel.
+func (l *Listener) Unlisten(ctx context.Context, channel string) error {
+	return l.exec(ctx, "UNLISTEN "+pgx.Identifier{channel}.Sanitize())
+}
+
+// Notify sends a notification with the given payload to a channel.
+func (l *Listener) Notify(ctx context.Context, channel, payload string) error {
+	return l.exec(ctx, "SELECT pg_notify($1, $2)", channel, payload)
+}
+
+// Wait blocks until a notification arrives on any subscribed channel.
+func (l *Listener) Wait(ctx context.Context) (*pgconn.Notification, error) {
+	for {
+		l.mu.Lock()
+		for !l.closed && (l.busy || l.pending > 0) {
+			l.cond.Wait()
+		}
+		if l.closed {
+			l.mu.Unlock()
+			return nil, ErrListenerClosed
+		}
+		waitCtx, cancel := context.WithCancel(ctx)
+		l.busy = true
+		l.cancelWait = cancel
+		l.mu.Unlock()
+
+		n, err := l.conn.WaitForNotification(waitCtx)
+		interrupted := waitCtx.Err() != nil && ctx.Err() == nil
+		cancel()
+
+		l.mu.Lock()
+		l.busy = false
+		l.cancelWait = nil
+		closed := l.closed
+		l.cond.Broadcast()
+		l.mu.Unlock()
+
+		switch {
+		case n != nil:
+			return n, nil
+		case closed:
+			return nil, ErrListenerClosed
+		case err != nil && interrupted:
+			// Another statement needed the connection; wait again once it is done
+			continue
+		}
+		return nil, err
+	}
+}
+
+// Close stops any pending Wait and closes the connection.
+func (l *Listener) Close(ctx context.Context) error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	if l.cancelWait != nil {
+		l.cancelWait()
+	}
+	for l.busy {
+		l.cond.Wait()
+	}
+	l.cond.Broadcast()
+	l.mu.Unlock()
+	return l.conn.Close(ctx)
+}
+
+// exec runs a statement on the listener connection, interrupting a pending Wait.
+func (l *Listener) exec(ctx context.Context, sql string, args ...any) error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return ErrListenerClosed
+	}
+	l.pending++
+	if l.cancelWait != nil {
+		l.cancelWait()
+	}
+	for l.busy && !l.closed {
+		l.cond.Wait()
+	}
+	if l.closed {
+		l.pending--
+		l.mu.Unlock()
+		return ErrListenerClosed
+	}
+	l.busy = true
+	l.mu.Unlock()
+
+	_, err := l.conn.Exec(ctx, sql, args...)
+
+	l.mu.Lock()
+	l.busy = false
+	l.pending--
+	l.cond.Broadcast()
+	l.mu.Unlock()
+	return err
+}
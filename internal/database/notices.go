@@ -0,0 +1,21 @@
+package database
+
+import "github.com/jackc/pgx/v5/pgconn"
+
+// handleNotice is installed as the pgx OnNotice callback. It is called from
+// the goroutine that is reading from the connection, so it only buffers
+// the notice; the caller that ran the statement collects it afterwards.
+func (db *Database) handleNotice(_ *pgconn.PgConn, notice *pgconn.Notice) {
+	db.noticesMu.Lock()
+	defer db.noticesMu.Unlock()
+	db.notices = append(db.notices, notice)
+}
+
+// DrainNotices returns all buffered notices and clears the buffer.
+func (db *Database) DrainNotices() []*pgconn.Notice {
+	db.noticesMu.Lock()
+	defer db.noticesMu.Unlock()
+	notices := db.notices
+	db.notices = nil
+	return notices
+}
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainNotices(t *testing.T) {
+	db, cleanup := SetupTestDatabase(t)
+	defer cleanup()
+
+	// Drop anything buffered while connecting
+	db.DrainNotices()
+
+	ExecQueries(t, db,
+		`DO $$
+		BEGIN
+			RAISE NOTICE 'hello %', 42;
+			RAISE WARNING 'careful';
+		END
+		$$`,
+	)
+
+	notices := db.DrainNotices()
+	require.Len(t, notices, 2, "Expected both RAISE statements to be captured")
+	assert.Equal(t, "NOTICE", notices[0].Severity)
+	assert.Equal(t, "hello 42", notices[0].Message)
+	assert.Equal(t, "WARNING", notices[1].Severity)
+	assert.Equal(t, "careful", notices[1].Message)
+
+	assert.Empty(t, db.DrainNotices(), "Buffer should be empty after draining")
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ResultColumn describes a column of a query result, as reported by the
+// server's field description and resolved against the catalog.
+type ResultColumn struct {
+	Name         string
+	TypeOID      uint32
+	TypeModifier int32
+	// TypeName is the formatted type, e.g. "character varying(64)"
+	TypeName string
+	// BaseTypeName is the type without its modifier, e.g. "character
+	// varying", which a cast to leaves values of any length or precision
+	BaseTypeName string
+
+	// TableOID and TableAttributeNumber identify the base table column the
+	// value was read from; both are 0 for computed values.
+	TableOID             uint32
+	TableAttributeNumber uint16
+	// TableSchema, TableName and BaseColumn name the base table column, if
+	// any. BaseColumn differs from Name when the column was aliased.
+	TableSchema string
+	TableName   string
+	BaseColumn  string
+	// NotNull is true when the base table column is declared NOT NULL
+	NotNull bool
+	// EnumLabels are the values of an enum type, in sort order
+	EnumLabels []string
+}
+
+// FromTable reports whether the column maps back to a base table column
+func (c ResultColumn) FromTable() bool {
+	return c.TableOID != 0 && c.TableAttributeNumber > 0 && c.BaseColumn != ""
+}
+
+// IsNumeric reports whether the column holds numbers
+func (c ResultColumn) IsNumeric() bool {
+	switch c.TypeOID {
+	case pgtype.Int2OID, pgtype.Int4OID, pgtype.Int8OID,
+		pgtype.Float4OID, pgtype.Float8OID, pgtype.NumericOID,
+		pgtype.OIDOID, pgtype.XIDOID, pgtype.CIDOID:
+		return true
+	}
+	return false
+}
+
+// IsTime reports whether the column holds dates or timestamps
+func (c ResultColumn) IsTime() bool {
+	switch c.TypeOID {
+	case pgtype.DateOID, pgtype.TimestampOID, pgtype.TimestamptzOID:
+		return true
+	}
+	return false
+}
+
+// IsText reports whether the column holds character strings
+func (c ResultColumn) IsText() bool {
+	switch c.TypeOID {
+	case pgtype.TextOID, pgtype.VarcharOID, pgtype.BPCharOID, pgtype.NameOID:
+		return true
+	}
+	return c.TypeName == "citext"
+}
+
+// ColumnNames returns the names of the given columns
+func ColumnNames(columns []ResultColumn) []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// DescribeFields resolves field descriptions to type names and base table
+// columns. A failed catalog lookup is logged and leaves the fields with
+// the names pgx knows, so the result can still be shown.
+func (db *Database) DescribeFields(ctx context.Context, fields []pgconn.FieldDescription) []ResultColumn {
+	columns := make([]ResultColumn, len(fields))
+	typeOIDs := make([]uint32, len(fields))
+	typeMods := make([]int32, len(fields))
+	tableOIDs := make([]uint32, len(fields))
+	attnums := make([]int16, len(fields))
+	for i, fd := range fields {
+		columns[i] = ResultColumn{
+			Name:                 fd.Name,
+			TypeOID:              fd.DataTypeOID,
+			TypeModifier:         fd.TypeModifier,
+			TableOID:             fd.TableOID,
+			TableAttributeNumber: fd.TableAttributeNumber,
+		}
+		if t, ok := db.Connection.TypeMap().TypeForOID(fd.DataTypeOID); ok {
+			columns[i].TypeName, columns[i].BaseTypeName = t.Name, t.Name
+		}
+		typeOIDs[i] = fd.DataTypeOID
+		typeMods[i] = fd.TypeModifier
+		tableOIDs[i] = fd.TableOID
+		attnums[i] = int16(fd.TableAttributeNumber)
+	}
+	if len(fields) == 0 {
+		return columns
+	}
+
+	q := `SELECT f.i::int,
+       format_type(f.typ, NULLIF(f.mod, -1)),
+       format_type(f.typ, NULL),
+       COALESCE(n.nspname::text, ''),
+       COALESCE(c.relname::text, ''),
+       COALESCE(a.attname::text, ''),
+       COALESCE(a.attnotnull, false),
+       (SELECT array_agg(e.enumlabel::text ORDER BY e.enumsortorder) FROM pg_enum e WHERE e.enumtypid = f.typ)
+  FROM unnest($1::oid[], $2::int4[], $3::oid[], $4::int2[]) WITH ORDINALITY AS f(typ, mod, tbl, att, i)
+  LEFT JOIN pg_class c ON c.oid = f.tbl
+  LEFT JOIN pg_namespace n ON n.oid = c.relnamespace
+  LEFT JOIN pg_attribute a ON a.attrelid = f.tbl AND a.attnum = f.att AND f.att > 0 AND NOT a.attisdropped`
+	rows, err := db.Connection.Query(ctx, q, typeOIDs, typeMods, tableOIDs, attnums)
+	if err != nil {
+		log.Printf("Failed to describe result columns: %v", err)
+		return columns
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var i int
+		var typeName, baseTypeName *string
+		var schema, table, column string
+		var notNull bool
+		var enumLabels []string
+		if err := rows.Scan(&i, &typeName, &baseTypeName, &schema, &table, &column, &notNull, &enumLabels); err != nil {
+			log.Printf("Failed to describe result columns: %v", err)
+			return columns
+		}
+		c := &columns[i-1]
+		if typeName != nil && baseTypeName != nil {
+			c.TypeName, c.BaseTypeName = *typeName, *baseTypeName
+		}
+		c.TableSchema, c.TableName, c.BaseColumn, c.NotNull = schema, table, column, notNull
+		c.EnumLabels = enumLabels
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Failed to describe result columns: %v", err)
+	}
+	return columns
+}
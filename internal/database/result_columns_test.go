@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeFields(t *testing.T) {
+	db, cleanup := SetupTestDatabase(t)
+	defer cleanup()
+
+	CreateSchema(t, db, "describe_schema")
+	defer DropSchemas(t, db, "describe_schema")
+
+	ExecQueries(t, db,
+		`CREATE TABLE describe_schema.products (
+			id INT PRIMARY KEY,
+			name VARCHAR(64) NOT NULL,
+			price NUMERIC(10, 2)
+		)`,
+	)
+
+	ctx := context.Background()
+	rows, err := db.Connection.Query(ctx, "SELECT id, name AS title, price, 1 + 1 AS computed FROM describe_schema.products")
+	require.NoError(t, err)
+	fields := slices.Clone(rows.FieldDescriptions())
+	rows.Close()
+
+	columns := db.DescribeFields(ctx, fields)
+	require.Len(t, columns, 4)
+
+	assert.Equal(t, []string{"id", "title", "price", "computed"}, ColumnNames(columns))
+	assert.Equal(t, []string{"integer", "character varying(64)", "numeric(10,2)", "integer"},
+		[]string{columns[0].TypeName, columns[1].TypeName, columns[2].TypeName, columns[3].TypeName})
+	assert.Equal(t, []string{"integer", "character varying", "numeric", "integer"},
+		[]string{columns[0].BaseTypeName, columns[1].BaseTypeName, columns[2].BaseTypeName, columns[3].BaseTypeName})
+
+	title := columns[1]
+	assert.True(t, title.FromTable())
+	assert.Equal(t, "describe_schema", title.TableSchema)
+	assert.Equal(t, "products", title.TableName)
+	assert.Equal(t, "name", title.BaseColumn)
+	assert.True(t, title.NotNull)
+	assert.False(t, title.IsNumeric())
+
+	assert.True(t, columns[2].IsNumeric())
+	assert.False(t, columns[2].NotNull)
+	assert.False(t, columns[3].FromTable())
+	assert.Nil(t, columns[0].EnumLabels)
+}
+
+func TestDescribeFieldsEnum(t *testing.T) {
+	db, cleanup := SetupTestDatabase(t)
+	defer cleanup()
+
+	CreateSchema(t, db, "describe_enum_schema")
+	defer DropSchemas(t, db, "describe_enum_schema")
+
+	ExecQueries(t, db,
+		`CREATE TYPE describe_enum_schema.mood AS ENUM ('sad', 'ok', 'happy')`,
+	)
+
+	ctx := context.Background()
+	rows, err := db.Connection.Query(ctx, "SELECT 'ok'::describe_enum_schema.mood AS mood")
+	require.NoError(t, err)
+	fields := slices.Clone(rows.FieldDescriptions())
+	rows.Close()
+
+	columns := db.DescribeFields(ctx, fields)
+	require.Len(t, columns, 1)
+	assert.Equal(t, "describe_enum_schema.mood", columns[0].TypeName)
+	assert.Equal(t, []string{"sad", "ok", "happy"}, columns[0].EnumLabels)
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrOpenTransaction is returned by ApplyRowChanges when the main
+// connection is inside a transaction the user opened
+var ErrOpenTransaction = errors.New("the session has an open transaction; commit or roll it back first")
+
+// Statement is a SQL statement with its bind parameters
+type Statement struct {
+	SQL  string
+	Args []any
+	// Returning is set when the statement ends in a RETURNING clause whose
+	// row is read back
+	Returning bool
+}
+
+// ApplyRowChanges runs statements that each change exactly one row in a
+// single transaction. If any statement fails or changes a number of rows
+// other than one, e.g. because the row was deleted meanwhile, the
+// transaction is rolled back and nothing is changed. It returns the rows
+// read back by the Returning statements, in order.
+//
+// The changes are refused with ErrOpenTransaction while the user has a
+// transaction open, since committing them would also commit, or fail with,
+// whatever that transaction did.
+func (db *Database) ApplyRowChanges(ctx context.Context, statements []Statement) ([][]any, error) {
+	if db.Connection.PgConn().TxStatus() != 'I' {
+		return nil, ErrOpenTransaction
+	}
+	tx, err := db.Connection.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Rolling back after Commit is a no-op
+	defer func() { _ = tx.Rollback(context.Background()) }()
+
+	var returned [][]any
+	for i, stmt := range statements {
+		var n int64
+		if stmt.Returning {
+			rows, err := tx.Query(ctx, stmt.SQL, stmt.Args...)
+			if err != nil {
+				return nil, fmt.Errorf("statement %d of %d: %w", i+1, len(statements), err)
+			}
+			values, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) ([]any, error) {
+				return row.Values()
+			})
+			if err != nil {
+				return nil, fmt.Errorf("statement %d of %d: %w", i+1, len(statements), err)
+			}
+			returned = append(returned, values...)
+			n = int64(len(values))
+		} else {
+			tag, err := tx.Exec(ctx, stmt.SQL, stmt.Args...)
+			if err != nil {
+				return nil, fmt.Errorf("statement %d of %d: %w", i+1, len(statements), err)
+			}
+			n = tag.RowsAffected()
+		}
+		if n != 1 {
+			return nil, fmt.Errorf("statement %d of %d changed %d rows instead of 1; the row may have been changed or deleted meanwhile", i+1, len(statements), n)
+		}
+	}
+	return returned, tx.Commit(ctx)
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyRowChanges(t *testing.T) {
+	db, cleanup := SetupTestDatabase(t)
+	defer cleanup()
+
+	CreateSchema(t, db, "changes_schema")
+	defer DropSchemas(t, db, "changes_schema")
+
+	ExecQueries(t, db,
+		`CREATE TABLE changes_schema.items (id INT PRIMARY KEY, name TEXT)`,
+		`INSERT INTO changes_schema.items VALUES (1, 'a'), (2, 'b')`,
+	)
+	ctx := context.Background()
+	names := func() []string {
+		rows, err := db.Connection.Query(ctx, "SELECT name FROM changes_schema.items ORDER BY id")
+		require.NoError(t, err)
+		defer rows.Close()
+		var names []string
+		for rows.Next() {
+			var name string
+			require.NoError(t, rows.Scan(&name))
+			names = append(names, name)
+		}
+		return names
+	}
+
+	_, err := db.ApplyRowChanges(ctx, []Statement{
+		{SQL: `UPDATE changes_schema.items SET name = $1 WHERE id = $2`, Args: []any{"x", 1}},
+		{SQL: `UPDATE changes_schema.items SET name = $1 WHERE id = $2`, Args: []any{"y", 2}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"x", "y"}, names())
+
+	// The second statement matches no row, so the first is rolled back
+	_, err = db.ApplyRowChanges(ctx, []Statement{
+		{SQL: `UPDATE changes_schema.items SET name = $1 WHERE id = $2`, Args: []any{"z", 1}},
+		{SQL: `UPDATE changes_schema.items SET name = $1 WHERE id = $2`, Args: []any{"z", 3}},
+	})
+	assert.ErrorContains(t, err, "changed 0 rows")
+	assert.Equal(t, []string{"x", "y"}, names())
+
+	// Inserted rows are read back with their generated values
+	returned, err := db.ApplyRowChanges(ctx, []Statement{
+		{SQL: `DELETE FROM changes_schema.items WHERE id = $1`, Args: []any{2}},
+		{SQL: `INSERT INTO changes_schema.items VALUES ($1, $2) RETURNING *`, Args: []any{3, "w"}, Returning: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{int32(3), "w"}}, returned)
+	assert.Equal(t, []string{"x", "w"}, names())
+
+	// Changes are refused inside a transaction the user opened
+	ExecQueries(t, db, "BEGIN")
+	_, err = db.ApplyRowChanges(ctx, []Statement{
+		{SQL: `UPDATE changes_schema.items SET name = $1 WHERE id = $2`, Args: []any{"v", 1}},
+	})
+	assert.ErrorIs(t, err, ErrOpenTransaction)
+	ExecQueries(t, db, "ROLLBACK")
+	assert.Equal(t, []string{"x", "w"}, names())
+}
@@ -0,0 +1,41 @@
+package database
+
+import (
+	"context"
+)
+
+// CountRows runs a count query on a spare connection so the main connection
+// stays free while it runs. Cancelling ctx cancels the query on the server.
+func (db *Database) CountRows(ctx context.Context, sql string, args ...any) (int64, error) {
+	conn, err := db.acquireSpareConn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer db.releaseSpareConn(context.Background(), conn)
+
+	var count int64
+	err = conn.QueryRow(ctx, sql, args...).Scan(&count)
+	return count, err
+}
+
+// EstimateRowCount returns the planner's estimate of the table's row count
+// from pg_class.reltuples. It returns -1 when there is no estimate, e.g. for
+// views or tables that were never vacuumed or analyzed.
+func (t *Table) EstimateRowCount() (int64, error) {
+	db := t.Schema.Database
+	if !db.Connected {
+		if err := db.Connect(); err != nil {
+			return -1, err
+		}
+	}
+	q := `SELECT CASE WHEN c.relkind IN ('r', 'm', 'p', 'f') THEN c.reltuples::bigint ELSE -1 END
+  FROM pg_class c
+  JOIN pg_namespace n ON n.oid = c.relnamespace
+ WHERE n.nspname = $1
+   AND c.relname = $2`
+	var estimate int64
+	if err := db.Connection.QueryRow(context.Background(), q, t.Schema.Name, t.Name).Scan(&estimate); err != nil {
+		return -1, err
+	}
+	return estimate, nil
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountRows(t *testing.T) {
+	db, cleanup := SetupTestDatabase(t)
+	defer cleanup()
+
+	CreateSchema(t, db, "count_schema")
+	defer DropSchemas(t, db, "count_schema")
+
+	ExecQueries(t, db,
+		`CREATE TABLE count_schema.items AS SELECT g AS id FROM generate_series(1, 250) g`,
+		`ANALYZE count_schema.items`,
+	)
+
+	count, err := db.CountRows(context.Background(), "SELECT count(*) FROM count_schema.items WHERE id > $1", 200)
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), count)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = db.CountRows(ctx, "SELECT count(*) FROM count_schema.items")
+	assert.Error(t, err)
+
+	schemas, err := db.ParseSchemas()
+	require.NoError(t, err)
+	var schema *Schema
+	for _, s := range schemas {
+		if s.Name == "count_schema" {
+			schema = s
+		}
+	}
+	require.NotNil(t, schema)
+	_, err = schema.LoadTables()
+	require.NoError(t, err)
+
+	estimate, err := schema.FindTable("items").EstimateRowCount()
+	require.NoError(t, err)
+	assert.Equal(t, int64(250), estimate)
+}
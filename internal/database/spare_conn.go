@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgconn/ctxwatch"
+)
+
+// acquireSpareConn returns the idle spare connection or opens a new one.
+// Spare connections run work that must not block the main connection for
+// long, such as cursors and exact row counts.
+func (db *Database) acquireSpareConn(ctx context.Context) (*pgx.Conn, error) {
+	db.spareMu.Lock()
+	conn := db.idleSpareConn
+	db.idleSpareConn = nil
+	db.spareMu.Unlock()
+	if conn != nil {
+		return conn, nil
+	}
+
+	config, err := db.connConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.OnNotice = db.handleNotice
+	// Ask the server to cancel the running statement when ctx is cancelled,
+	// instead of only dropping the connection, so a cancelled count(*) stops.
+	config.BuildContextWatcherHandler = func(pgConn *pgconn.PgConn) ctxwatch.Handler {
+		return &pgconn.CancelRequestContextWatcherHandler{
+			Conn:          pgConn,
+			DeadlineDelay: 5 * time.Second,
+		}
+	}
+	return pgx.ConnectConfig(ctx, config)
+}
+
+// releaseSpareConn keeps one healthy connection around for the next use
+// and closes any other.
+func (db *Database) releaseSpareConn(ctx context.Context, conn *pgx.Conn) {
+	db.spareMu.Lock()
+	defer db.spareMu.Unlock()
+	if db.idleSpareConn == nil && !conn.IsClosed() && conn.PgConn().TxStatus() == 'I' {
+		db.idleSpareConn = conn
+		return
+	}
+	_ = conn.Close(ctx)
+}
+
+// closeIdleSpareConn closes the idle spare connection, if any.
+func (db *Database) closeIdleSpareConn(ctx context.Context) {
+	db.spareMu.Lock()
+	defer db.spareMu.Unlock()
+	if db.idleSpareConn != nil {
+		_ = db.idleSpareConn.Close(ctx)
+		db.idleSpareConn = nil
+	}
+}
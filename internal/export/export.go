@@ -0,0 +1,143 @@
+// Package export writes query results to files in common data formats.
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/SavingFrame/dbettier/internal/query"
+)
+
+// Format is a file format results can be exported to
+type Format int
+
+const (
+	CSV Format = iota
+	TSV
+	JSON
+	NDJSON
+	Markdown
+	HTML
+	SQL
+)
+
+// Formats lists every format, in the order they are offered
+var Formats = []Format{CSV, TSV, JSON, NDJSON, Markdown, HTML, SQL}
+
+func (f Format) String() string {
+	switch f {
+	case CSV:
+		return "CSV"
+	case TSV:
+		return "TSV"
+	case JSON:
+		return "JSON"
+	case NDJSON:
+		return "NDJSON"
+	case Markdown:
+		return "Markdown"
+	case HTML:
+		return "HTML"
+	case SQL:
+		return "SQL INSERT"
+	default:
+		return "unknown"
+	}
+}
+
+// Extension returns the usual file name extension of the format
+func (f Format) Extension() string {
+	switch f {
+	case CSV:
+		return ".csv"
+	case TSV:
+		return ".tsv"
+	case JSON:
+		return ".json"
+	case NDJSON:
+		return ".ndjson"
+	case Markdown:
+		return ".md"
+	case HTML:
+		return ".html"
+	case SQL:
+		return ".sql"
+	default:
+		return ""
+	}
+}
+
+// UsesHeader reports whether Options.Header applies to the format. JSON
+// objects are always keyed by column name and Markdown tables need a
+// header row.
+func (f Format) UsesHeader() bool {
+	return f == CSV || f == TSV || f == HTML || f == SQL
+}
+
+// UsesNullText reports whether Options.NullText applies to the format.
+// JSON and SQL have a NULL of their own.
+func (f Format) UsesNullText() bool {
+	return f != JSON && f != NDJSON && f != SQL
+}
+
+// Options control how a result is written
+type Options struct {
+	Format Format
+	// Delimiter separates CSV fields; TSV always uses a tab
+	Delimiter rune
+	// Header writes the column names: a header line for CSV and TSV, a
+	// table head for HTML and the column list of SQL INSERTs
+	Header bool
+	// NullText stands for NULL in the text formats
+	NullText string
+	// Table is the target of SQL INSERTs, written as given
+	Table string
+}
+
+// DefaultOptions returns the options for a CSV file with a header, where
+// NULL is an empty field
+func DefaultOptions() Options {
+	return Options{Format: CSV, Delimiter: ',', Header: true}
+}
+
+// Writer writes rows in a format. Rows can be written in several calls, as
+// they are read; Close finishes the file but does not close the
+// underlying writer.
+type Writer interface {
+	WriteRows(rows [][]any) error
+	Close() error
+}
+
+// NewWriter returns a writer of rows of a result with the given columns
+func NewWriter(w io.Writer, columns []database.ResultColumn, opts Options) (Writer, error) {
+	switch opts.Format {
+	case CSV, TSV:
+		return newDelimitedWriter(w, columns, opts)
+	case JSON, NDJSON:
+		return newJSONWriter(w, columns, opts), nil
+	case Markdown:
+		return newMarkdownWriter(w, columns, opts)
+	case HTML:
+		return newHTMLWriter(w, columns, opts)
+	case SQL:
+		if strings.TrimSpace(opts.Table) == "" {
+			return nil, errors.New("SQL INSERTs need a target table")
+		}
+		return &sqlWriter{w: w, columns: columns, opts: opts}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format %d", opts.Format)
+	}
+}
+
+// text returns a value as the text written to a file: in PostgreSQL's
+// text format whatever the display options, so that the file reads back
+// into the same values, and NullText for NULL
+func text(v any, col database.ResultColumn, opts Options) string {
+	if v == nil {
+		return opts.NullText
+	}
+	return query.TextValue(v, col)
+}
@@ -0,0 +1,108 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/SavingFrame/dbettier/internal/query"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testColumns = []database.ResultColumn{
+		{Name: "id", TypeOID: pgtype.Int4OID},
+		{Name: "name", TypeOID: pgtype.TextOID},
+		{Name: "active", TypeOID: pgtype.BoolOID},
+		{Name: "score", TypeOID: pgtype.Float8OID},
+	}
+	testRows = [][]any{
+		{int32(1234), "a|b, \"c\"", true, 1.5},
+		{int32(2), nil, false, nil},
+	}
+)
+
+// export writes the test rows in two batches, as a streamed export does
+func export(t *testing.T, opts Options) string {
+	var b strings.Builder
+	w, err := NewWriter(&b, testColumns, opts)
+	require.NoError(t, err)
+	require.NoError(t, w.WriteRows(testRows[:1]))
+	require.NoError(t, w.WriteRows(testRows[1:]))
+	require.NoError(t, w.Close())
+	return b.String()
+}
+
+func TestExportFormats(t *testing.T) {
+	opts := DefaultOptions()
+	assert.Equal(t, "id,name,active,score\n1234,\"a|b, \"\"c\"\"\",true,1.5\n2,,false,\n", export(t, opts))
+
+	opts.Delimiter, opts.Header, opts.NullText = ';', false, `\N`
+	assert.Equal(t, "1234;\"a|b, \"\"c\"\"\";true;1.5\n2;\\N;false;\\N\n", export(t, opts))
+
+	opts = Options{Format: TSV, Header: true, NullText: "NULL"}
+	assert.Equal(t, "id\tname\tactive\tscore\n1234\t\"a|b, \"\"c\"\"\"\ttrue\t1.5\n2\tNULL\tfalse\tNULL\n", export(t, opts))
+
+	assert.Equal(t, `[
+  {"id":1234,"name":"a|b, \"c\"","active":true,"score":1.5},
+  {"id":2,"name":null,"active":false,"score":null}
+]
+`, export(t, Options{Format: JSON}))
+
+	assert.Equal(t, `{"id":1234,"name":"a|b, \"c\"","active":true,"score":1.5}
+{"id":2,"name":null,"active":false,"score":null}
+`, export(t, Options{Format: NDJSON}))
+
+	assert.Equal(t, `| id | name | active | score |
+| ---: | --- | --- | ---: |
+| 1234 | a\|b, "c" | true | 1.5 |
+| 2 | NULL | false | NULL |
+`, export(t, Options{Format: Markdown, NullText: "NULL"}))
+
+	assert.Equal(t, `<table>
+  <thead>
+    <tr><th>id</th><th>name</th><th>active</th><th>score</th></tr>
+  </thead>
+  <tbody>
+    <tr><td>1234</td><td>a|b, &#34;c&#34;</td><td>true</td><td>1.5</td></tr>
+    <tr><td>2</td><td></td><td>false</td><td></td></tr>
+  </tbody>
+</table>
+`, export(t, Options{Format: HTML, Header: true}))
+
+	assert.Equal(t, `INSERT INTO public.people ("id", "name", "active", "score") VALUES (1234, 'a|b, "c"', TRUE, 1.5);
+INSERT INTO public.people ("id", "name", "active", "score") VALUES (2, NULL, FALSE, NULL);
+`, export(t, Options{Format: SQL, Header: true, Table: "public.people"}))
+
+	_, err := NewWriter(&strings.Builder{}, testColumns, Options{Format: SQL})
+	assert.Error(t, err)
+}
+
+func TestExportEmptyJSON(t *testing.T) {
+	var b strings.Builder
+	w, err := NewWriter(&b, testColumns, Options{Format: JSON})
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	assert.Equal(t, "[\n]\n", b.String())
+}
+
+func TestExportIgnoresDisplay(t *testing.T) {
+	saved := query.Display
+	defer func() { query.Display = saved }()
+	query.Display.GroupDigits = true
+	query.Display.TimestampLayout = "02.01.2006 15:04"
+	query.Display.Location = time.UTC
+
+	columns := []database.ResultColumn{{Name: "n", TypeOID: pgtype.Int8OID}, {Name: "at", TypeOID: pgtype.TimestamptzOID}}
+	at := time.Date(2024, 1, 1, 17, 30, 0, 0, time.FixedZone("IST", 5*60*60+30*60))
+	var b strings.Builder
+	w, err := NewWriter(&b, columns, DefaultOptions())
+	require.NoError(t, err)
+	require.NoError(t, w.WriteRows([][]any{{int64(48213), at}}))
+	require.NoError(t, w.Close())
+	assert.Equal(t, "n,at\n48213,2024-01-01 17:30:00+05:30\n", b.String())
+}
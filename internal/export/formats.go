@@ -0,0 +1,281 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// delimitedWriter writes CSV and TSV
+type delimitedWriter struct {
+	w       *csv.Writer
+	columns []database.ResultColumn
+	opts    Options
+	record  []string
+}
+
+func newDelimitedWriter(w io.Writer, columns []database.ResultColumn, opts Options) (*delimitedWriter, error) {
+	cw := csv.NewWriter(w)
+	cw.Comma = opts.Delimiter
+	if opts.Format == TSV {
+		cw.Comma = '\t'
+	}
+	if cw.Comma == 0 {
+		cw.Comma = ','
+	}
+	d := &delimitedWriter{w: cw, columns: columns, opts: opts, record: make([]string, len(columns))}
+	if opts.Header {
+		if err := cw.Write(database.ColumnNames(columns)); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+func (d *delimitedWriter) WriteRows(rows [][]any) error {
+	for _, row := range rows {
+		for i, v := range row {
+			d.record[i] = text(v, d.columns[i], d.opts)
+		}
+		if err := d.w.Write(d.record); err != nil {
+			return err
+		}
+	}
+	d.w.Flush()
+	return d.w.Error()
+}
+
+func (d *delimitedWriter) Close() error {
+	d.w.Flush()
+	return d.w.Error()
+}
+
+// jsonWriter writes a JSON array of objects, or for NDJSON one object per
+// line, keyed by column name
+type jsonWriter struct {
+	w       *bufio.Writer
+	columns []database.ResultColumn
+	keys    []string
+	opts    Options
+	rows    int
+}
+
+func newJSONWriter(w io.Writer, columns []database.ResultColumn, opts Options) *jsonWriter {
+	// Columns of the same name would overwrite each other
+	keys := make([]string, len(columns))
+	seen := map[string]int{}
+	for i, col := range columns {
+		seen[col.Name]++
+		name := col.Name
+		if n := seen[col.Name]; n > 1 {
+			name = fmt.Sprintf("%s_%d", col.Name, n)
+		}
+		key, _ := json.Marshal(name)
+		keys[i] = string(key)
+	}
+	return &jsonWriter{w: bufio.NewWriter(w), columns: columns, keys: keys, opts: opts}
+}
+
+func (j *jsonWriter) WriteRows(rows [][]any) error {
+	for _, row := range rows {
+		switch {
+		case j.opts.Format == NDJSON:
+		case j.rows == 0:
+			j.w.WriteString("[\n  ")
+		default:
+			j.w.WriteString(",\n  ")
+		}
+		j.w.WriteByte('{')
+		for i, v := range row {
+			if i > 0 {
+				j.w.WriteByte(',')
+			}
+			j.w.WriteString(j.keys[i])
+			j.w.WriteByte(':')
+			j.w.WriteString(jsonValue(v, j.columns[i]))
+		}
+		j.w.WriteByte('}')
+		if j.opts.Format == NDJSON {
+			j.w.WriteByte('\n')
+		}
+		j.rows++
+	}
+	return j.w.Flush()
+}
+
+func (j *jsonWriter) Close() error {
+	if j.opts.Format == JSON {
+		if j.rows == 0 {
+			j.w.WriteString("[")
+		}
+		j.w.WriteString("\n]\n")
+	}
+	return j.w.Flush()
+}
+
+// jsonValue returns a value as JSON: numbers and booleans as such, json
+// columns as their document, and anything else as its text
+func jsonValue(v any, col database.ResultColumn) string {
+	if v == nil {
+		return "null"
+	}
+	s := text(v, col, Options{})
+	switch {
+	case col.TypeOID == pgtype.BoolOID:
+		return strconv.FormatBool(v == true)
+	case col.IsNumeric() && json.Valid([]byte(s)):
+		// NaN and Infinity are not JSON numbers and become strings
+		return s
+	case (col.TypeOID == pgtype.JSONOID || col.TypeOID == pgtype.JSONBOID) && json.Valid([]byte(s)):
+		return s
+	}
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
+
+// markdownWriter writes a GitHub flavored Markdown table
+type markdownWriter struct {
+	w       *bufio.Writer
+	columns []database.ResultColumn
+	opts    Options
+}
+
+func newMarkdownWriter(w io.Writer, columns []database.ResultColumn, opts Options) (*markdownWriter, error) {
+	m := &markdownWriter{w: bufio.NewWriter(w), columns: columns, opts: opts}
+	names := database.ColumnNames(columns)
+	for i, name := range names {
+		names[i] = markdownCell(name)
+	}
+	m.writeLine(names)
+	rule := make([]string, len(columns))
+	for i, col := range columns {
+		rule[i] = "---"
+		if col.IsNumeric() {
+			rule[i] = "---:"
+		}
+	}
+	m.writeLine(rule)
+	return m, m.w.Flush()
+}
+
+func (m *markdownWriter) writeLine(cells []string) {
+	m.w.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+}
+
+func (m *markdownWriter) WriteRows(rows [][]any) error {
+	cells := make([]string, len(m.columns))
+	for _, row := range rows {
+		for i, v := range row {
+			cells[i] = markdownCell(text(v, m.columns[i], m.opts))
+		}
+		m.writeLine(cells)
+	}
+	return m.w.Flush()
+}
+
+func (m *markdownWriter) Close() error {
+	return m.w.Flush()
+}
+
+// markdownCell escapes what would end a table cell or row
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// htmlWriter writes an HTML table
+type htmlWriter struct {
+	w       *bufio.Writer
+	columns []database.ResultColumn
+	opts    Options
+}
+
+func newHTMLWriter(w io.Writer, columns []database.ResultColumn, opts Options) (*htmlWriter, error) {
+	h := &htmlWriter{w: bufio.NewWriter(w), columns: columns, opts: opts}
+	h.w.WriteString("<table>\n")
+	if opts.Header {
+		h.w.WriteString("  <thead>\n    <tr>")
+		for _, col := range columns {
+			h.w.WriteString("<th>" + html.EscapeString(col.Name) + "</th>")
+		}
+		h.w.WriteString("</tr>\n  </thead>\n")
+	}
+	h.w.WriteString("  <tbody>\n")
+	return h, h.w.Flush()
+}
+
+func (h *htmlWriter) WriteRows(rows [][]any) error {
+	for _, row := range rows {
+		h.w.WriteString("    <tr>")
+		for i, v := range row {
+			h.w.WriteString("<td>" + html.EscapeString(text(v, h.columns[i], h.opts)) + "</td>")
+		}
+		h.w.WriteString("</tr>\n")
+	}
+	return h.w.Flush()
+}
+
+func (h *htmlWriter) Close() error {
+	h.w.WriteString("  </tbody>\n</table>\n")
+	return h.w.Flush()
+}
+
+// sqlWriter writes one INSERT statement per row
+type sqlWriter struct {
+	w       io.Writer
+	columns []database.ResultColumn
+	opts    Options
+}
+
+func (s *sqlWriter) WriteRows(rows [][]any) error {
+	prefix := "INSERT INTO " + s.opts.Table
+	if s.opts.Header {
+		names := database.ColumnNames(s.columns)
+		for i, name := range names {
+			names[i] = `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+		}
+		prefix += " (" + strings.Join(names, ", ") + ")"
+	}
+	prefix += " VALUES ("
+
+	bw := bufio.NewWriter(s.w)
+	values := make([]string, len(s.columns))
+	for _, row := range rows {
+		for i, v := range row {
+			values[i] = sqlLiteral(v, s.columns[i])
+		}
+		bw.WriteString(prefix + strings.Join(values, ", ") + ");\n")
+	}
+	return bw.Flush()
+}
+
+func (s *sqlWriter) Close() error {
+	return nil
+}
+
+// sqlLiteral returns a value as a SQL literal. Numbers and booleans are
+// written bare and everything else quoted, which the server casts to the
+// target column's type.
+func sqlLiteral(v any, col database.ResultColumn) string {
+	if v == nil {
+		return "NULL"
+	}
+	s := text(v, col, Options{})
+	switch {
+	case col.TypeOID == pgtype.BoolOID:
+		return strings.ToUpper(strconv.FormatBool(v == true))
+	case col.IsNumeric() && json.Valid([]byte(s)):
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,131 @@
+package importer
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile writes content to a file in a temporary directory
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// readAll returns every record of a file
+func readAll(t *testing.T, path string, opts Options) ([]string, [][]any) {
+	r, err := Open(path, opts)
+	require.NoError(t, err)
+	defer r.Close()
+	var records [][]any
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return r.Columns(), records
+		}
+		require.NoError(t, err)
+		records = append(records, record)
+	}
+}
+
+func TestReadDelimited(t *testing.T) {
+	path := writeFile(t, "people.csv", "\ufeffid,name\n1,\"Pat, \"\"P\"\"\"\n2,\n")
+	cols, records := readAll(t, path, DefaultOptions())
+	assert.Equal(t, []string{"id", "name"}, cols)
+	assert.Equal(t, [][]any{{"1", `Pat, "P"`}, {"2", nil}}, records)
+
+	opts := Options{Format: TSV, NullText: `\N`}
+	path = writeFile(t, "people.tsv", "1\ta \"b\"\n2\t\\N\n")
+	cols, records = readAll(t, path, opts)
+	assert.Equal(t, []string{"column1", "column2"}, cols)
+	assert.Equal(t, [][]any{{"1", `a "b"`}, {"2", nil}}, records)
+
+	_, err := Open(writeFile(t, "empty.csv", ""), DefaultOptions())
+	assert.Error(t, err)
+}
+
+func TestReadNDJSON(t *testing.T) {
+	path := writeFile(t, "events.ndjson", `{"id": 1, "name": "a", "tags": ["x"]}
+
+{"id": 2, "name": null, "ok": true}
+`)
+	cols, records := readAll(t, path, Options{Format: NDJSON})
+	assert.Equal(t, []string{"id", "name", "tags", "ok"}, cols)
+	assert.Equal(t, [][]any{
+		{"1", "a", `["x"]`, nil},
+		{"2", nil, nil, "true"},
+	}, records)
+
+	_, err := Open(writeFile(t, "bad.ndjson", "[1, 2]\n"), Options{Format: NDJSON})
+	assert.Error(t, err)
+}
+
+func TestFormatForPath(t *testing.T) {
+	assert.Equal(t, TSV, FormatForPath("/tmp/a.TSV"))
+	assert.Equal(t, NDJSON, FormatForPath("a.jsonl"))
+	assert.Equal(t, CSV, FormatForPath("a.txt"))
+}
+
+func TestConvert(t *testing.T) {
+	v, err := Convert(" 42 ", "integer")
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), v)
+
+	v, err = Convert("yes", "boolean")
+	require.NoError(t, err)
+	assert.Equal(t, true, v)
+
+	v, err = Convert("2024-03-01 10:30:00+02", "timestamp with time zone")
+	require.NoError(t, err)
+	assert.True(t, time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC).Equal(v.(time.Time)))
+
+	v, err = Convert("12.50", "numeric")
+	require.NoError(t, err)
+	assert.IsType(t, pgtype.Numeric{}, v)
+
+	v, err = Convert(nil, "integer")
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	_, err = Convert("abc", "bigint")
+	assert.Error(t, err)
+	_, err = Convert("{", "jsonb")
+	assert.Error(t, err)
+
+	assert.True(t, Supported("USER-DEFINED"))
+	assert.False(t, Supported("ARRAY"))
+
+	bad, err := Check([]any{"1", "x", nil, "y"}, "bigint")
+	assert.Equal(t, 2, bad)
+	assert.Error(t, err)
+}
+
+func TestInfer(t *testing.T) {
+	assert.Equal(t, "bigint", Infer([]any{"1", nil, "0"}))
+	assert.Equal(t, "numeric", Infer([]any{"1", "2.5"}))
+	assert.Equal(t, "boolean", Infer([]any{"true", "F"}))
+	assert.Equal(t, "date", Infer([]any{"2024-01-31"}))
+	assert.Equal(t, "timestamp with time zone", Infer([]any{"2024-01-31T10:00:00Z", "2024-01-31 11:00"}))
+	assert.Equal(t, "uuid", Infer([]any{"a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"}))
+	assert.Equal(t, "jsonb", Infer([]any{`{"a": 1}`, "[]"}))
+	assert.Equal(t, "text", Infer([]any{"1", "x"}))
+	assert.Equal(t, "text", Infer([]any{nil}))
+}
+
+func TestMatch(t *testing.T) {
+	assert.Equal(t, []int{2, 1, -1},
+		Match([]string{"id", "first_name", "email"}, []string{"ID", "First Name", "id", "phone"}))
+}
+
+func TestCreateTableSQL(t *testing.T) {
+	assert.Equal(t, "CREATE TABLE \"public\".\"new \"\"t\"\"\" (\n  \"id\" bigint,\n  \"Name\" text\n)",
+		CreateTableSQL("public", `new "t"`, []string{"id", "Name"}, []string{"bigint", "text"}))
+}
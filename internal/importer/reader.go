@@ -0,0 +1,323 @@
+// Package importer reads CSV, TSV and NDJSON files for loading into tables:
+// it reads the records, converts their fields to column types and infers
+// types for a new table.
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Format is a file format rows can be imported from
+type Format int
+
+const (
+	CSV Format = iota
+	TSV
+	NDJSON
+)
+
+// Formats lists every format, in the order they are offered
+var Formats = []Format{CSV, TSV, NDJSON}
+
+func (f Format) String() string {
+	switch f {
+	case CSV:
+		return "CSV"
+	case TSV:
+		return "TSV"
+	case NDJSON:
+		return "NDJSON"
+	default:
+		return "unknown"
+	}
+}
+
+// FormatForPath guesses the format of a file from its extension, falling
+// back to CSV
+func FormatForPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tsv", ".tab":
+		return TSV
+	case ".ndjson", ".jsonl":
+		return NDJSON
+	default:
+		return CSV
+	}
+}
+
+// Options control how a file is read
+type Options struct {
+	Format Format
+	// Delimiter separates CSV fields; TSV always uses a tab
+	Delimiter rune
+	// Header means the first CSV or TSV line holds the column names.
+	// Without one the columns are named column1, column2 and so on.
+	Header bool
+	// NullText is the CSV or TSV field that stands for NULL. NDJSON has a
+	// null of its own.
+	NullText string
+}
+
+// DefaultOptions returns the options for a CSV file with a header, where
+// NULL is an empty field
+func DefaultOptions() Options {
+	return Options{Format: CSV, Delimiter: ',', Header: true}
+}
+
+// ndjsonKeyLines is the number of NDJSON lines scanned for keys. Their
+// keys, in order of first appearance, become the columns.
+const ndjsonKeyLines = 1000
+
+// Reader reads the records of a file. A record holds one value per column:
+// a string, or nil for NULL. NDJSON numbers, booleans, objects and arrays
+// are kept as their JSON text.
+type Reader struct {
+	file    *os.File
+	opts    Options
+	columns []string
+	line    int
+
+	csv     *csv.Reader
+	pending []string
+
+	json  *bufio.Reader
+	index map[string]int
+}
+
+// Open opens a file and reads its columns
+func Open(path string, opts Options) (*Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Reader{file: file, opts: opts}
+	if opts.Format == NDJSON {
+		err = r.openNDJSON()
+	} else {
+		err = r.openDelimited()
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// Columns returns the column names of the file
+func (r *Reader) Columns() []string {
+	return r.columns
+}
+
+// Line returns the line of the record read last, for error messages
+func (r *Reader) Line() int {
+	return r.line
+}
+
+// Read returns the next record, or io.EOF after the last one
+func (r *Reader) Read() ([]any, error) {
+	if r.json != nil {
+		return r.readNDJSON()
+	}
+	return r.readDelimited()
+}
+
+// Close closes the file
+func (r *Reader) Close() error {
+	return r.file.Close()
+}
+
+func (r *Reader) openDelimited() error {
+	r.csv = csv.NewReader(bufio.NewReader(r.file))
+	r.csv.Comma = r.opts.Delimiter
+	if r.opts.Format == TSV {
+		r.csv.Comma = '\t'
+		r.csv.LazyQuotes = true
+	}
+	first, err := r.csv.Read()
+	if errors.Is(err, io.EOF) {
+		return errors.New("file is empty")
+	}
+	if err != nil {
+		return err
+	}
+	// Spreadsheets often start CSV files with a byte order mark
+	first[0] = strings.TrimPrefix(first[0], "\ufeff")
+	if r.opts.Header {
+		r.columns = first
+		return nil
+	}
+	r.pending = first
+	r.columns = make([]string, len(first))
+	for i := range first {
+		r.columns[i] = fmt.Sprintf("column%d", i+1)
+	}
+	return nil
+}
+
+func (r *Reader) readDelimited() ([]any, error) {
+	fields := r.pending
+	r.pending = nil
+	if fields == nil {
+		var err error
+		if fields, err = r.csv.Read(); err != nil {
+			return nil, err
+		}
+	}
+	r.line, _ = r.csv.FieldPos(0)
+	record := make([]any, len(fields))
+	for i, field := range fields {
+		if field != r.opts.NullText {
+			record[i] = field
+		}
+	}
+	return record, nil
+}
+
+func (r *Reader) openNDJSON() error {
+	r.json = bufio.NewReader(r.file)
+	r.index = make(map[string]int)
+	for n := 1; n <= ndjsonKeyLines; n++ {
+		line, err := r.json.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			keys, _, parseErr := parseObject(line)
+			if parseErr != nil {
+				return fmt.Errorf("line %d: %w", n, parseErr)
+			}
+			for _, key := range keys {
+				if _, ok := r.index[key]; !ok {
+					r.index[key] = len(r.columns)
+					r.columns = append(r.columns, key)
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if len(r.columns) == 0 {
+		return errors.New("file has no keys")
+	}
+	if _, err := r.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	r.json.Reset(r.file)
+	return nil
+}
+
+func (r *Reader) readNDJSON() ([]any, error) {
+	for {
+		line, err := r.json.ReadBytes('\n')
+		if len(line) > 0 {
+			r.line++
+		}
+		if len(bytes.TrimSpace(line)) > 0 {
+			keys, values, parseErr := parseObject(line)
+			if parseErr != nil {
+				return nil, fmt.Errorf("line %d: %w", r.line, parseErr)
+			}
+			record := make([]any, len(r.columns))
+			for i, key := range keys {
+				col, ok := r.index[key]
+				if !ok {
+					return nil, fmt.Errorf("line %d: key %q is not in the first %d lines", r.line, key, ndjsonKeyLines)
+				}
+				record[col] = values[i]
+			}
+			return record, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseObject returns the keys of a JSON object in order, with their
+// values as strings: the text of a JSON string, nil for null and the JSON
+// text of anything else
+func parseObject(line []byte) ([]string, []any, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, nil, errors.New("not a JSON object")
+	}
+	var keys []string
+	var values []any
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, tok.(string))
+		values = append(values, jsonField(raw))
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, err
+	}
+	return keys, values, nil
+}
+
+func jsonField(raw json.RawMessage) any {
+	switch raw[0] {
+	case 'n':
+		return nil
+	case '"':
+		var s string
+		_ = json.Unmarshal(raw, &s)
+		return s
+	default:
+		return string(raw)
+	}
+}
+
+// Sample is the start of a file, for previewing it and checking its types
+type Sample struct {
+	Columns []string
+	Rows    [][]any
+}
+
+// ReadSample reads the columns and up to n records of a file
+func ReadSample(path string, opts Options, n int) (Sample, error) {
+	r, err := Open(path, opts)
+	if err != nil {
+		return Sample{}, err
+	}
+	defer r.Close()
+	sample := Sample{Columns: r.Columns()}
+	for len(sample.Rows) < n {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Sample{}, err
+		}
+		sample.Rows = append(sample.Rows, record)
+	}
+	return sample, nil
+}
+
+// Values returns the sampled values of column i
+func (s Sample) Values(i int) []any {
+	values := make([]any, len(s.Rows))
+	for r, row := range s.Rows {
+		if i < len(row) {
+			values[r] = row[i]
+		}
+	}
+	return values
+}
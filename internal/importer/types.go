@@ -0,0 +1,220 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Types lists the column types offered for a new table, as
+// information_schema names them
+var Types = []string{
+	"text",
+	"bigint",
+	"numeric",
+	"double precision",
+	"boolean",
+	"date",
+	"timestamp with time zone",
+	"timestamp without time zone",
+	"uuid",
+	"jsonb",
+}
+
+// Supported reports whether fields can be imported into a column of the
+// given information_schema data type
+func Supported(dataType string) bool {
+	_, err := Convert("", dataType)
+	return !errors.Is(err, errUnsupported)
+}
+
+var errUnsupported = errors.New("unsupported type")
+
+// timestampLayouts are the accepted timestamp forms, most specific first
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04",
+}
+
+// Convert turns a field into the value COPY sends for a column of the
+// given information_schema data type. COPY uses the binary format, in
+// which a string is only accepted by text-like columns, so every other
+// type is parsed here. nil stays NULL.
+func Convert(field any, dataType string) (any, error) {
+	s, ok := field.(string)
+	if !ok {
+		return field, nil
+	}
+	v, err := convert(s, strings.TrimSpace(s), dataType)
+	if err != nil && !errors.Is(err, errUnsupported) {
+		return nil, fmt.Errorf("invalid %s %q", dataType, s)
+	}
+	return v, err
+}
+
+func convert(s, trimmed, dataType string) (any, error) {
+	switch dataType {
+	case "smallint", "integer", "bigint":
+		return strconv.ParseInt(trimmed, 10, 64)
+	case "real", "double precision":
+		return strconv.ParseFloat(trimmed, 64)
+	case "numeric":
+		var n pgtype.Numeric
+		return n, n.Scan(trimmed)
+	case "boolean":
+		return parseBool(trimmed)
+	case "date":
+		return time.Parse(time.DateOnly, trimmed)
+	case "timestamp with time zone":
+		// Without an offset the time is taken as local, as psql would
+		return parseTimestamp(trimmed, time.Local)
+	case "timestamp without time zone":
+		return parseTimestamp(trimmed, time.UTC)
+	case "time without time zone":
+		var t pgtype.Time
+		return t, t.Scan(trimmed)
+	case "uuid":
+		var u pgtype.UUID
+		return u, u.Scan(trimmed)
+	case "json", "jsonb":
+		if !json.Valid([]byte(s)) {
+			return nil, errors.New("invalid JSON")
+		}
+		return s, nil
+	case "text", "character varying", "character", "name", "USER-DEFINED":
+		// Enums and other user-defined types are sent as text
+		return s, nil
+	}
+	return nil, fmt.Errorf("%w %s", errUnsupported, dataType)
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "t", "true", "y", "yes", "on", "1":
+		return true, nil
+	case "f", "false", "n", "no", "off", "0":
+		return false, nil
+	}
+	return false, errors.New("invalid boolean")
+}
+
+func parseTimestamp(s string, loc *time.Location) (time.Time, error) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.ParseInLocation(time.DateOnly, s, loc)
+}
+
+// Check converts the sampled values of a field for a column type. It
+// returns how many failed and the first error.
+func Check(values []any, dataType string) (int, error) {
+	bad := 0
+	var first error
+	for _, v := range values {
+		if _, err := Convert(v, dataType); err != nil {
+			bad++
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	return bad, first
+}
+
+// Infer returns the narrowest type in Types that all sampled values of a
+// field convert to. Fields that are always NULL are text.
+func Infer(values []any) string {
+	candidates := []string{"bigint", "numeric", "boolean", "date", "timestamp with time zone", "uuid", "jsonb"}
+	seen := false
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		seen = true
+		candidates = slices.DeleteFunc(candidates, func(t string) bool {
+			return !looksLike(strings.TrimSpace(s), t)
+		})
+	}
+	if !seen || len(candidates) == 0 {
+		return "text"
+	}
+	return candidates[0]
+}
+
+// looksLike is stricter than Convert, so that for instance 1 and 0 are
+// numbers rather than booleans and JSON strings stay text
+func looksLike(s, dataType string) bool {
+	switch dataType {
+	case "numeric":
+		f, err := strconv.ParseFloat(s, 64)
+		return err == nil && !math.IsInf(f, 0) && !math.IsNaN(f)
+	case "boolean":
+		switch strings.ToLower(s) {
+		case "true", "false", "t", "f":
+			return true
+		}
+		return false
+	case "timestamp with time zone":
+		_, err := parseTimestamp(s, time.UTC)
+		return err == nil && len(s) > len(time.DateOnly)
+	case "jsonb":
+		return (strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[")) && json.Valid([]byte(s))
+	}
+	_, err := Convert(s, dataType)
+	return err == nil
+}
+
+// Match pairs each table column with the field of the same name: exactly,
+// or else ignoring case and the difference between spaces, dashes and
+// underscores. Unmatched columns get -1.
+func Match(tableColumns, fields []string) []int {
+	matches := make([]int, len(tableColumns))
+	for i, col := range tableColumns {
+		matches[i] = -1
+		for j, field := range fields {
+			if field == col {
+				matches[i] = j
+				break
+			}
+			if matches[i] < 0 && normalizeName(field) == normalizeName(col) {
+				matches[i] = j
+			}
+		}
+	}
+	return matches
+}
+
+func normalizeName(name string) string {
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// CreateTableSQL returns the statement creating a table with the given
+// columns and types
+func CreateTableSQL(schema, table string, columns, types []string) string {
+	var b strings.Builder
+	b.WriteString("CREATE TABLE " + pgx.Identifier{schema, table}.Sanitize() + " (\n")
+	for i, col := range columns {
+		b.WriteString("  " + pgx.Identifier{col}.Sanitize() + " " + types[i])
+		if i < len(columns)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(")")
+	return b.String()
+}
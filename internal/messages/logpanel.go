@@ -9,10 +9,12 @@ const (
 	LogWarning
 	LogError
 	LogSQL
+	LogNotice // Server notices and RAISE output
 )
 
 // AddLogMsg is a message to add a log entry to the log panel
 type AddLogMsg struct {
 	Message string
 	Level   LogLevel
+	Source  string // Name of the tab that produced the entry, if any
 }
@@ -1,4 +1,163 @@
 package messages
 
+import (
+	"context"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/SavingFrame/dbettier/internal/export"
+	"github.com/SavingFrame/dbettier/internal/query"
+)
+
 // TableLoadingMsg indicates that the table is loading data
 type TableLoadingMsg struct{}
+
+// CountRowsMsg asks for an exact count(*) of a table query's rows in the
+// background. Cancelling Ctx cancels the count on the server.
+type CountRowsMsg struct {
+	Query      *query.TableQuery
+	DatabaseID string
+	Ctx        context.Context
+}
+
+// RowCountMsg carries the result of a CountRowsMsg
+type RowCountMsg struct {
+	Query *query.TableQuery
+	// Ctx is the context of the CountRowsMsg
+	Ctx context.Context
+	// WhereClause is the filter the rows were counted with
+	WhereClause string
+	Count       int64
+	Err         error
+}
+
+// ApplyEditsMsg asks to write the staged changes of a table query's result
+// in one transaction
+type ApplyEditsMsg struct {
+	Query      *query.TableQuery
+	DatabaseID string
+	Statements []database.Statement
+}
+
+// EditsAppliedMsg carries the result of an ApplyEditsMsg. On error nothing
+// was written.
+type EditsAppliedMsg struct {
+	Query *query.TableQuery
+	Count int
+	// Inserted holds the new rows as read back with RETURNING
+	Inserted [][]any
+	Err      error
+}
+
+// CSVEditedMsg is sent when the editor opened on a CSV file of a table
+// query's rows exits
+type CSVEditedMsg struct {
+	Query *query.TableQuery
+	Path  string
+	Err   error
+}
+
+// FollowReferenceMsg asks for the rows related by foreign key to a row of a
+// result: the rows Column references, or with Incoming the rows of other
+// tables referencing the row's table
+type FollowReferenceMsg struct {
+	Query      query.ExecutableQuery
+	DatabaseID string
+	Columns    []database.ResultColumn
+	Row        []any
+	// Column is the focused column; it picks the table and, when following
+	// a reference, the foreign key
+	Column   int
+	Incoming bool
+}
+
+// TableJump opens a table filtered to the rows related to another row
+type TableJump struct {
+	Label       string
+	Table       *database.Table
+	WhereClause string
+}
+
+// ReferencesMsg offers the jumps found for a FollowReferenceMsg that has
+// more than one
+type ReferencesMsg struct {
+	Query query.ExecutableQuery
+	Title string
+	Jumps []TableJump
+}
+
+// JumpToTableMsg opens a jump in a new tab, remembering the current tab to
+// go back to
+type JumpToTableMsg struct {
+	Jump       TableJump
+	DatabaseID string
+}
+
+// JumpHistoryMsg goes back, or forward, along the tabs opened by jumps
+type JumpHistoryMsg struct {
+	Forward bool
+}
+
+// LoadLabelsMsg asks for the labels of the rows referenced by foreign keys
+// of a table tab's rows. With no Lookups, the foreign keys that have a
+// label column are found first.
+type LoadLabelsMsg struct {
+	Query      *query.TableQuery
+	DatabaseID string
+	Columns    []database.ResultColumn
+	Rows       [][]any
+	Lookups    []query.LabelLookup
+}
+
+// LabelsLoadedMsg carries the result of a LoadLabelsMsg. Lookups is set
+// when they were found for this message; Labels holds the labels read for
+// each lookup, in order.
+type LabelsLoadedMsg struct {
+	Query   *query.TableQuery
+	Lookups []query.LabelLookup
+	Labels  []map[string]string
+	Err     error
+}
+
+// ColumnStatsMsg asks for the statistics of a table column over every row
+// matching WhereClause, or with Estimate for the planner's statistics from
+// pg_stats. Cancelling Ctx cancels the statements on the server.
+type ColumnStatsMsg struct {
+	Query       query.ExecutableQuery
+	DatabaseID  string
+	Column      database.ResultColumn
+	WhereClause string
+	Estimate    bool
+	Ctx         context.Context
+}
+
+// ColumnStatsLoadedMsg carries the result of a ColumnStatsMsg
+type ColumnStatsLoadedMsg struct {
+	Query query.ExecutableQuery
+	// Ctx is the context of the ColumnStatsMsg
+	Ctx   context.Context
+	Stats query.ColumnStats
+	Err   error
+}
+
+// ExportMsg asks to write a result to a file: Rows if SQL is empty, or
+// else every row of SQL, read through a cursor in batches
+type ExportMsg struct {
+	DatabaseID string
+	Columns    []database.ResultColumn
+	Rows       [][]any
+	SQL        string
+	Args       []any
+	Path       string
+	Options    export.Options
+}
+
+// ExportProgressMsg reports the rows written by an export so far. Next
+// writes the next batch; it is nil once the export is Done or failed.
+type ExportProgressMsg struct {
+	Path string
+	Rows int
+	Done bool
+	Err  error
+	Next tea.Cmd
+}
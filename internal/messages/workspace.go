@@ -1,6 +1,7 @@
 package messages
 
 import (
+	tea "charm.land/bubbletea/v2"
 	"github.com/SavingFrame/dbettier/internal/database"
 	"github.com/SavingFrame/dbettier/internal/query"
 )
@@ -22,3 +23,53 @@ type OpenTableAndExecuteMsg struct {
 	Table      *database.Table
 	DatabaseID string
 }
+
+// OpenNotifyTabMsg creates a new LISTEN/NOTIFY monitor tab for database
+type OpenNotifyTabMsg struct {
+	DatabaseID string
+}
+
+// QueryNoticesMsg reports how many server notices a statement raised in a tab.
+type QueryNoticesMsg struct {
+	TabID string
+	Count int
+}
+
+// QueryParamsRequestMsg asks the user for bind parameter values before a
+// statement with placeholders is executed in a tab.
+type QueryParamsRequestMsg struct {
+	Query      *query.BasicSQLQuery
+	Types      []string // Server-inferred type name per parameter
+	DatabaseID string
+	TabID      string
+}
+
+// ExecuteQueryParamsMsg executes a statement once its bind parameters have
+// been filled in.
+type ExecuteQueryParamsMsg struct {
+	Query      *query.BasicSQLQuery
+	DatabaseID string
+	TabID      string
+}
+
+// OpenImportMsg opens the import wizard for loading a file into Table, or
+// into a new table of Schema when Table is nil
+type OpenImportMsg struct {
+	DatabaseID string
+	Schema     *database.Schema
+	Table      *database.Table
+}
+
+// ImportProgressMsg reports the rows copied by an import so far. Next
+// copies the next batch; it is nil once the import is Done or failed.
+// Created is the table made for the import, if any.
+type ImportProgressMsg struct {
+	DatabaseID string
+	Path       string
+	Table      string
+	Created    *database.Table
+	Rows       int64
+	Done       bool
+	Err        error
+	Next       tea.Cmd
+}
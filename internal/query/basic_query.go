@@ -1,34 +1,203 @@
 package query
 
 import (
+	"context"
 	"log"
+	"strings"
+	"sync"
 
 	tea "charm.land/bubbletea/v2"
 )
 
 type BasicSQLQuery struct {
 	Query      string
+	Params     ParsedParams
 	SortOrders OrderByClauses
 	SQLResult  *SQLResult
+	args       []any
+
+	// WhereClause filters the result. In SortFilterLocal mode it is parsed
+	// by ParseLocalFilter, otherwise it is SQL.
+	WhereClause string
+	// mode is decided on the first sort or filter: a result that is wholly
+	// in memory is sorted there, a streamed one is re-run on the server
+	mode SortFilterMode
+	// sourceRows holds the rows as returned by the server in local mode
+	sourceRows [][]any
+
+	// fetcher reads further rows when the result is streamed from a cursor
+	fetcher RowFetcher
+
+	// mu guards the buffered rows, which grow from fetch commands
+	mu sync.Mutex
+	// bufferStart is the position of SQLResult.Rows[0] in the whole result;
+	// it moves forward when old pages are dropped to respect MaxBufferedRows
+	bufferStart int
 	// page
 	localOffset int
-	localRows   [][]any
 }
 
 func NewBasicSQLQuery(query string) *BasicSQLQuery {
 	return &BasicSQLQuery{
 		Query:       query,
+		Params:      ParseParams(query),
 		localOffset: 0,
 	}
 }
 
+// Compile returns the statement sent to the server, with named placeholders
+// rewritten to positional ones. When sorting or filtering on the server,
+// the statement is wrapped as a subquery.
 func (q *BasicSQLQuery) Compile() string {
-	return q.Query
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.mode != SortFilterServer || (q.WhereClause == "" && len(q.SortOrders) == 0) {
+		return q.Params.SQL
+	}
+	// The statement goes on lines of its own so a trailing line comment
+	// can't swallow the closing parenthesis.
+	sql := "SELECT * FROM (\n" + strings.TrimSuffix(strings.TrimSpace(q.Params.SQL), ";") + "\n) AS result"
+	if q.WhereClause != "" {
+		sql += " WHERE " + q.WhereClause
+	}
+	if len(q.SortOrders) > 0 {
+		sql += " ORDER BY " + q.SortOrders.String()
+	}
+	return sql
+}
+
+// Args returns the bind parameter values for the statement
+func (q *BasicSQLQuery) Args() []any {
+	return q.args
+}
+
+// SetArgs sets the bind parameter values, one per entry in Params.Labels
+func (q *BasicSQLQuery) SetArgs(args []any) {
+	q.args = args
+}
+
+// SetFetcher streams the rest of the result from f as the user pages forward
+func (q *BasicSQLQuery) SetFetcher(f RowFetcher) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.fetcher = f
+}
+
+// Close releases the cursor behind a streamed result, if any
+func (q *BasicSQLQuery) Close() {
+	q.mu.Lock()
+	f := q.fetcher
+	q.fetcher = nil
+	q.mu.Unlock()
+	if f != nil {
+		go func() {
+			if err := f.Close(context.Background()); err != nil {
+				log.Printf("Failed to close cursor: %v", err)
+			}
+		}()
+	}
 }
 
 func (q *BasicSQLQuery) HandleSortChange(orderBy OrderByClauses) tea.Cmd {
+	q.mu.Lock()
 	q.SortOrders = orderBy
-	return nil
+	q.mu.Unlock()
+	return q.applySortFilter()
+}
+
+func (q *BasicSQLQuery) SetWhereClause(whereClause string) tea.Cmd {
+	q.mu.Lock()
+	q.WhereClause = strings.TrimSpace(whereClause)
+	q.mu.Unlock()
+	return q.applySortFilter()
+}
+
+// SortFilterMode returns where the result is sorted and filtered, deciding
+// it if that has not happened yet
+func (q *BasicSQLQuery) SortFilterMode() SortFilterMode {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.sortFilterModeLocked()
+}
+
+func (q *BasicSQLQuery) sortFilterModeLocked() SortFilterMode {
+	if q.mode != sortFilterUnset {
+		return q.mode
+	}
+	// Only results that could be declared as a cursor are streamed, so a
+	// partial result can always be wrapped in a subquery.
+	if q.SQLResult != nil && (q.bufferStart > 0 || q.streamingLocked()) {
+		return SortFilterServer
+	}
+	return SortFilterLocal
+}
+
+// applySortFilter re-runs the query on the server or rearranges the rows in
+// memory, depending on the mode
+func (q *BasicSQLQuery) applySortFilter() tea.Cmd {
+	q.mu.Lock()
+	if q.SQLResult == nil {
+		q.mu.Unlock()
+		return nil
+	}
+	if q.mode == sortFilterUnset {
+		q.mode = q.sortFilterModeLocked()
+		if q.mode == SortFilterLocal {
+			q.sourceRows = q.SQLResult.Rows
+		}
+	}
+	mode := q.mode
+	q.mu.Unlock()
+
+	if mode == SortFilterServer {
+		// The old cursor is of no use once the statement changes
+		q.Close()
+		return func() tea.Msg {
+			return ReapplyTableQueryMsg{
+				Query: q,
+			}
+		}
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	rows, err := q.sortFilterLocked(q.sourceRows)
+	if err != nil {
+		return func() tea.Msg {
+			return SortFilterFailedMsg{Query: q, Err: err}
+		}
+	}
+	q.setRowsLocked(rows)
+	return func() tea.Msg {
+		return UpdateTableMsg{
+			Query: q,
+		}
+	}
+}
+
+// sortFilterLocked returns the rows passing WhereClause in SortOrders
+func (q *BasicSQLQuery) sortFilterLocked(source [][]any) ([][]any, error) {
+	predicate, err := ParseLocalFilter(q.WhereClause, q.SQLResult.Columns)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([][]any, 0, len(source))
+	for _, row := range source {
+		if predicate(row) {
+			rows = append(rows, row)
+		}
+	}
+	SortRows(rows, q.SQLResult.ColumnNames(), q.SortOrders)
+	return rows, nil
+}
+
+// setRowsLocked replaces the rows in memory and goes back to the first page
+func (q *BasicSQLQuery) setRowsLocked(rows [][]any) {
+	q.SQLResult.Rows = rows
+	q.SQLResult.Total = len(rows)
+	q.SQLResult.TotalFetched = len(rows)
+	q.bufferStart = 0
+	q.localOffset = 0
 }
 
 func (q *BasicSQLQuery) GetSortOrders() OrderByClauses {
@@ -36,17 +205,28 @@ func (q *BasicSQLQuery) GetSortOrders() OrderByClauses {
 }
 
 func (q *BasicSQLQuery) SetSQLResult(msg *SQLResultMsg) *SQLResult {
+	q.mu.Lock()
+	defer q.mu.Unlock()
 	q.SQLResult = &SQLResult{
 		Rows:          msg.Rows,
 		Columns:       msg.Columns,
 		TotalFetched:  len(msg.Rows),
 		Total:         len(msg.Rows),
-		CanFetchTotal: false,
+		CanFetchTotal: q.streamingLocked(),
 	}
-	if len(msg.Rows) > 500 {
-		q.localRows = q.SQLResult.Rows[:500]
-	} else {
-		q.localRows = q.SQLResult.Rows
+	if q.SQLResult.CanFetchTotal {
+		q.SQLResult.Total = -1
+	}
+	q.bufferStart = 0
+	q.localOffset = 0
+	if q.mode == SortFilterLocal {
+		// Re-run, e.g. refreshed: keep the user's sort and filter
+		q.sourceRows = msg.Rows
+		if rows, err := q.sortFilterLocked(msg.Rows); err == nil {
+			q.setRowsLocked(rows)
+		} else {
+			log.Printf("Failed to reapply filter to refreshed rows: %v", err)
+		}
 	}
 	return q.SQLResult
 }
@@ -55,12 +235,38 @@ func (q *BasicSQLQuery) GetSQLResult() *SQLResult {
 	return q.SQLResult
 }
 
+// streamingLocked reports whether more rows can be fetched from the server
+func (q *BasicSQLQuery) streamingLocked() bool {
+	return q.fetcher != nil && !q.fetcher.Exhausted()
+}
+
+// CompleteRows returns every row of the result, sorted and filtered, if
+// they are all in memory. They are not when the result is streamed and
+// rows are still to be fetched or were dropped to save memory.
+func (q *BasicSQLQuery) CompleteRows() ([][]any, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.SQLResult == nil || q.streamingLocked() || q.bufferStart > 0 {
+		return nil, false
+	}
+	return q.SQLResult.Rows, true
+}
+
+// bufferedNextPageLocked reports whether the next page is already in memory
+func (q *BasicSQLQuery) bufferedNextPageLocked() bool {
+	return q.localOffset+PageSize < q.bufferStart+len(q.SQLResult.Rows)
+}
+
 func (q *BasicSQLQuery) HasNextPage() bool {
-	return q.SQLResult != nil && len(q.localRows) >= 500
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.SQLResult != nil && (q.bufferedNextPageLocked() || q.streamingLocked())
 }
 
 func (q *BasicSQLQuery) HasPreviousPage() bool {
-	return q.localOffset > 0
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.localOffset > q.bufferStart
 }
 
 func (q *BasicSQLQuery) PreviousPage() tea.Cmd {
@@ -68,7 +274,9 @@ func (q *BasicSQLQuery) PreviousPage() tea.Cmd {
 		log.Println("No previous page available")
 		return nil
 	}
-	q.localOffset = max(0, q.localOffset-500)
+	q.mu.Lock()
+	q.localOffset = max(q.bufferStart, q.localOffset-PageSize)
+	q.mu.Unlock()
 	return func() tea.Msg {
 		return UpdateTableMsg{
 			Query: q,
@@ -77,18 +285,96 @@ func (q *BasicSQLQuery) PreviousPage() tea.Cmd {
 }
 
 func (q *BasicSQLQuery) NextPage() tea.Cmd {
-	q.localRows = q.SQLResult.Rows[:500]
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.SQLResult == nil {
+		return nil
+	}
+	if q.bufferedNextPageLocked() {
+		q.localOffset += PageSize
+		return func() tea.Msg {
+			return UpdateTableMsg{
+				Query: q,
+			}
+		}
+	}
+	if !q.streamingLocked() {
+		log.Println("No next page available")
+		return nil
+	}
+
+	fetched := q.SQLResult.TotalFetched
+	return tea.Batch(
+		func() tea.Msg { return FetchingRowsMsg{Query: q, Fetched: fetched} },
+		q.fetchNextPage(q.fetcher, q.SQLResult),
+	)
+}
+
+// fetchNextPage reads the next page of result from f and moves to it. A
+// sort or filter re-run on the server meanwhile closes f and replaces the
+// result, so the page, or the error from reading a closed cursor, is then
+// dropped.
+func (q *BasicSQLQuery) fetchNextPage(f RowFetcher, result *SQLResult) tea.Cmd {
 	return func() tea.Msg {
+		rows, err := f.Fetch(context.Background(), PageSize)
+
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		if q.fetcher != f || q.SQLResult != result {
+			return nil
+		}
+		if err != nil {
+			return FetchFailedMsg{Query: q, Err: err}
+		}
+		result.Rows = append(result.Rows, rows...)
+		result.TotalFetched += len(rows)
+		result.CanFetchTotal = q.streamingLocked()
+		if !result.CanFetchTotal {
+			result.Total = result.TotalFetched
+		}
+		if len(rows) > 0 {
+			q.localOffset += PageSize
+		}
+		q.dropOldPagesLocked()
+		log.Printf("Fetched %d more rows, %d buffered", len(rows), len(result.Rows))
+
 		return UpdateTableMsg{
 			Query: q,
 		}
 	}
 }
 
+// dropOldPagesLocked frees whole pages from the front of the buffer until it
+// fits in MaxBufferedRows, never dropping the current page.
+func (q *BasicSQLQuery) dropOldPagesLocked() {
+	rows := q.SQLResult.Rows
+	excess := len(rows) - MaxBufferedRows
+	if excess <= 0 {
+		return
+	}
+	drop := (excess + PageSize - 1) / PageSize * PageSize
+	drop = min(drop, q.localOffset-q.bufferStart)
+	if drop <= 0 {
+		return
+	}
+	clear(rows[:drop])
+	q.SQLResult.Rows = rows[drop:]
+	q.bufferStart += drop
+}
+
 func (q *BasicSQLQuery) Rows() [][]any {
-	return q.localRows
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.SQLResult == nil {
+		return nil
+	}
+	start := q.localOffset - q.bufferStart
+	end := min(start+PageSize, len(q.SQLResult.Rows))
+	return q.SQLResult.Rows[start:end]
 }
 
 func (q *BasicSQLQuery) PageOffset() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
 	return q.localOffset
 }
@@ -0,0 +1,72 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasicSQLQuerySortFilter(t *testing.T) {
+	q := NewBasicSQLQuery("SELECT * FROM users -- all of them")
+	q.SetSQLResult(&SQLResultMsg{
+		Columns: columnsNamed("id", "name"),
+		Rows:    [][]any{{int32(2), "b"}, {int32(1), "a"}, {int32(3), "c"}},
+	})
+	assert.Equal(t, SortFilterLocal, q.SortFilterMode())
+
+	q.HandleSortChange(OrderByClauses{{ColumnName: "id", Direction: "ASC"}})
+	q.SetWhereClause("id <> 2")
+	assert.Equal(t, [][]any{{int32(1), "a"}, {int32(3), "c"}}, q.Rows())
+	assert.Equal(t, 2, q.GetSQLResult().Total)
+	// Sorting in memory leaves the statement alone
+	assert.Equal(t, "SELECT * FROM users -- all of them", q.Compile())
+
+	q.SetWhereClause("")
+	q.HandleSortChange(nil)
+	assert.Equal(t, [][]any{{int32(2), "b"}, {int32(1), "a"}, {int32(3), "c"}}, q.Rows())
+}
+
+func TestBasicSQLQueryServerSortFilter(t *testing.T) {
+	q := NewBasicSQLQuery("SELECT * FROM users -- all of them")
+	q.SetFetcher(&stubFetcher{})
+	q.SetSQLResult(&SQLResultMsg{Columns: columnsNamed("id"), Rows: [][]any{{int32(1)}}})
+	assert.Equal(t, SortFilterServer, q.SortFilterMode())
+
+	q.SetWhereClause("id > 1")
+	q.HandleSortChange(OrderByClauses{{ColumnName: "id", Direction: "DESC"}})
+	assert.Equal(t, "SELECT * FROM (\nSELECT * FROM users -- all of them\n) AS result WHERE id > 1 ORDER BY \"id\" DESC", q.Compile())
+}
+
+func TestBasicSQLQueryFetchAfterClose(t *testing.T) {
+	q := NewBasicSQLQuery("SELECT * FROM users")
+	f := &pageFetcher{rows: [][]any{{int32(2)}}}
+	q.SetFetcher(f)
+	q.SetSQLResult(&SQLResultMsg{Columns: columnsNamed("id"), Rows: [][]any{{int32(1)}}})
+	fetch := q.fetchNextPage(f, q.GetSQLResult())
+
+	// A sort re-run on the server closes the cursor before the page arrives
+	q.Close()
+	assert.Nil(t, fetch())
+	assert.Equal(t, [][]any{{int32(1)}}, q.Rows())
+}
+
+// pageFetcher is a streamed result with one page of rows left
+type pageFetcher struct {
+	rows [][]any
+}
+
+func (f *pageFetcher) Fetch(context.Context, int) ([][]any, error) {
+	rows := f.rows
+	f.rows = nil
+	return rows, nil
+}
+func (f *pageFetcher) Exhausted() bool             { return f.rows == nil }
+func (f *pageFetcher) Close(context.Context) error { return nil }
+
+// stubFetcher is a streamed result with rows left on the server
+type stubFetcher struct{}
+
+func (stubFetcher) Fetch(context.Context, int) ([][]any, error) { return nil, nil }
+func (stubFetcher) Exhausted() bool                             { return false }
+func (stubFetcher) Close(context.Context) error                 { return nil }
@@ -0,0 +1,138 @@
+package query
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CompareValues orders two non-NULL values of the same column by their Go
+// type: numbers numerically, times chronologically, everything else by its
+// displayed text.
+func CompareValues(a, b any) int {
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	switch x := a.(type) {
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y)
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0
+			case !x:
+				return -1
+			}
+			return 1
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y)
+		}
+	case []byte:
+		if y, ok := b.([]byte); ok {
+			return bytes.Compare(x, y)
+		}
+	}
+	return strings.Compare(FormatValue(a), FormatValue(b))
+}
+
+// NumberValue returns a numeric cell value as a float64, or false if v is
+// not a number
+func NumberValue(v any) (float64, bool) {
+	return toFloat(v)
+}
+
+// toFloat converts numeric cell values to float64
+func toFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case pgtype.Numeric:
+		f, err := v.Float64Value()
+		if err != nil || !f.Valid {
+			return 0, false
+		}
+		return f.Float64, true
+	}
+	return 0, false
+}
+
+// SortRows sorts rows in place by the given orders. NULLs sort last in
+// ascending and first in descending order, as they do in PostgreSQL.
+// Orders naming a column that is not in columns are ignored.
+func SortRows(rows [][]any, columns []string, orders OrderByClauses) {
+	type key struct {
+		index int
+		desc  bool
+	}
+	var keys []key
+	for _, o := range orders {
+		if idx := slices.Index(columns, o.ColumnName); idx >= 0 {
+			keys = append(keys, key{index: idx, desc: o.Direction == "DESC"})
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	slices.SortStableFunc(rows, func(a, b []any) int {
+		for _, k := range keys {
+			x, y := a[k.index], b[k.index]
+			var c int
+			switch {
+			case x == nil && y == nil:
+				c = 0
+			case x == nil:
+				c = 1
+			case y == nil:
+				c = -1
+			default:
+				c = CompareValues(x, y)
+			}
+			if k.desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+}
@@ -0,0 +1,547 @@
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// EditTarget is the table behind a table query's result, with the key that
+// identifies its rows. Edits to the result become statements on it.
+type EditTarget struct {
+	Schema     string
+	Table      string
+	KeyColumns []string
+	// Defaults maps column names to their default expressions
+	Defaults map[string]string
+	columns  []database.ResultColumn
+	// keyIndexes are the positions of KeyColumns in the result
+	keyIndexes []int
+}
+
+// EditTarget returns the table the query's rows can be written back to. It
+// fails unless every column of the result is read from the same table and
+// the table has a key to find rows by.
+func (q *TableQuery) EditTarget() (*EditTarget, error) {
+	if len(q.KeyColumns) == 0 {
+		return nil, errors.New("the table has no primary key or unique NOT NULL index")
+	}
+	if q.SQLResult == nil || len(q.SQLResult.Columns) == 0 {
+		return nil, errors.New("no result to edit")
+	}
+	columns := q.SQLResult.Columns
+	for _, c := range columns {
+		if !c.FromTable() {
+			return nil, fmt.Errorf("column %q is not read from a table", c.Name)
+		}
+		if c.TableOID != columns[0].TableOID {
+			return nil, errors.New("the result combines several tables")
+		}
+	}
+	t := &EditTarget{
+		Schema:     columns[0].TableSchema,
+		Table:      columns[0].TableName,
+		KeyColumns: q.KeyColumns,
+		Defaults:   q.ColumnDefaults,
+		columns:    columns,
+	}
+	for _, key := range q.KeyColumns {
+		idx := slices.IndexFunc(columns, func(c database.ResultColumn) bool { return c.BaseColumn == key })
+		if idx < 0 {
+			return nil, fmt.Errorf("key column %q is not in the result", key)
+		}
+		t.keyIndexes = append(t.keyIndexes, idx)
+	}
+	return t, nil
+}
+
+// Column returns the result column at index col
+func (t *EditTarget) Column(col int) database.ResultColumn {
+	return t.columns[col]
+}
+
+// Default returns the default expression of the column at index col
+func (t *EditTarget) Default(col int) (string, bool) {
+	expr, ok := t.Defaults[t.columns[col].BaseColumn]
+	return expr, ok
+}
+
+// QualifiedName returns the quoted, schema-qualified table name
+func (t *EditTarget) QualifiedName() string {
+	return QualifiedName(t.Schema, t.Table)
+}
+
+// key returns the key values of a result row
+func (t *EditTarget) key(row []any) []any {
+	key := make([]any, len(t.keyIndexes))
+	for i, idx := range t.keyIndexes {
+		key[i] = row[idx]
+	}
+	return key
+}
+
+// RowKey identifies a result row by its key values
+func (t *EditTarget) RowKey(row []any) string {
+	return keyString(t.key(row))
+}
+
+// keyString identifies a row by its key values, for looking up edits
+func keyString(key []any) string {
+	parts := make([]string, len(key))
+	for i, v := range key {
+		parts[i] = TextValue(v, database.ResultColumn{})
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// CellEdit is a staged change to one cell
+type CellEdit struct {
+	// Key holds the values of the target's key columns for the edited row
+	Key    []any
+	Column int
+	// Value is the new value as text, nil for NULL
+	Value *string
+	// Original is the value read from the cell, which it must still hold
+	// when the edit is written
+	Original any
+}
+
+// RowInsert is a staged new row. Values maps column indexes to their value
+// as text, nil for NULL; columns without a value get their default.
+type RowInsert struct {
+	Values map[int]*string
+}
+
+type editKey struct {
+	row    string
+	column int
+}
+
+// EditSet stages changes to a table query's result until they are written
+// in one transaction: cell edits, new rows and deleted rows. Edits and
+// deletions are found by the row's key, so they follow their row across
+// pages and re-sorts.
+type EditSet struct {
+	target *EditTarget
+	edits  map[editKey]CellEdit
+	// order keeps rows in the order they were first edited
+	order   []editKey
+	inserts []*RowInsert
+	// deletes holds the keys of rows to delete, by keyString
+	deletes     map[string][]any
+	deleteOrder []string
+}
+
+// NewEditSet creates an empty edit set for target
+func NewEditSet(target *EditTarget) *EditSet {
+	return &EditSet{target: target, edits: map[editKey]CellEdit{}, deletes: map[string][]any{}}
+}
+
+// Target returns the table the edits are written to
+func (s *EditSet) Target() *EditTarget {
+	return s.target
+}
+
+// Len returns the number of staged changes: edited cells, new rows and
+// deleted rows
+func (s *EditSet) Len() int {
+	return len(s.edits) + len(s.inserts) + len(s.deletes)
+}
+
+// Set stages value (nil for NULL) for column col of row. Setting a cell back
+// to its original value drops its edit.
+func (s *EditSet) Set(row []any, col int, value *string) {
+	key := s.target.key(row)
+	k := editKey{row: keyString(key), column: col}
+	original := row[col]
+	unchanged := (value == nil && original == nil) ||
+		(value != nil && original != nil && *value == EditText(original, s.target.columns[col]))
+	if unchanged {
+		s.Revert(row, col)
+		return
+	}
+	if _, ok := s.edits[k]; !ok {
+		s.order = append(s.order, k)
+	}
+	s.edits[k] = CellEdit{Key: key, Column: col, Value: value, Original: original}
+}
+
+// Lookup returns the staged edit of column col of row, if any
+func (s *EditSet) Lookup(row []any, col int) (CellEdit, bool) {
+	e, ok := s.edits[editKey{row: keyString(s.target.key(row)), column: col}]
+	return e, ok
+}
+
+// RowEdits returns the staged edits of row, by column
+func (s *EditSet) RowEdits(row []any) []CellEdit {
+	rowKey := keyString(s.target.key(row))
+	var edits []CellEdit
+	for col := range s.target.columns {
+		if e, ok := s.edits[editKey{row: rowKey, column: col}]; ok {
+			edits = append(edits, e)
+		}
+	}
+	return edits
+}
+
+// Revert drops the edit of column col of row
+func (s *EditSet) Revert(row []any, col int) {
+	k := editKey{row: keyString(s.target.key(row)), column: col}
+	if _, ok := s.edits[k]; !ok {
+		return
+	}
+	delete(s.edits, k)
+	s.order = slices.DeleteFunc(s.order, func(o editKey) bool { return o == k })
+}
+
+// Insert stages a new row and returns its index among Inserts
+func (s *EditSet) Insert(values map[int]*string) int {
+	if values == nil {
+		values = map[int]*string{}
+	}
+	s.inserts = append(s.inserts, &RowInsert{Values: values})
+	return len(s.inserts) - 1
+}
+
+// DuplicateRow returns the values of a new row copied from row. Key columns
+// with a default, such as serials and identities, are left to it.
+func (s *EditSet) DuplicateRow(row []any) map[int]*string {
+	values := map[int]*string{}
+	for col, v := range row {
+		if _, hasDefault := s.target.Default(col); hasDefault && slices.Contains(s.target.keyIndexes, col) {
+			continue
+		}
+		if v == nil {
+			values[col] = nil
+			continue
+		}
+		text := EditText(v, s.target.columns[col])
+		values[col] = &text
+	}
+	return values
+}
+
+// Inserts returns the staged new rows
+func (s *EditSet) Inserts() []*RowInsert {
+	return s.inserts
+}
+
+// SetInsertValue sets column col of new row i to value, nil for NULL
+func (s *EditSet) SetInsertValue(i, col int, value *string) {
+	s.inserts[i].Values[col] = value
+}
+
+// ResetInsertValue leaves column col of new row i to its default
+func (s *EditSet) ResetInsertValue(i, col int) {
+	delete(s.inserts[i].Values, col)
+}
+
+// RemoveInsert drops new row i
+func (s *EditSet) RemoveInsert(i int) {
+	s.inserts = slices.Delete(s.inserts, i, i+1)
+}
+
+// ToggleDelete marks row for deletion, or unmarks it. It reports whether
+// the row is now marked.
+func (s *EditSet) ToggleDelete(row []any) bool {
+	key := s.target.key(row)
+	k := keyString(key)
+	if _, ok := s.deletes[k]; ok {
+		delete(s.deletes, k)
+		s.deleteOrder = slices.DeleteFunc(s.deleteOrder, func(o string) bool { return o == k })
+		return false
+	}
+	s.deletes[k] = key
+	s.deleteOrder = append(s.deleteOrder, k)
+	return true
+}
+
+// Deleted reports whether row is marked for deletion
+func (s *EditSet) Deleted(row []any) bool {
+	_, ok := s.deletes[keyString(s.target.key(row))]
+	return ok
+}
+
+// Clear drops all staged changes
+func (s *EditSet) Clear() {
+	s.edits = map[editKey]CellEdit{}
+	s.order = nil
+	s.inserts = nil
+	s.deletes = map[string][]any{}
+	s.deleteOrder = nil
+}
+
+// rows groups the edits by row, in the order rows were first edited. Rows
+// marked for deletion are left out.
+func (s *EditSet) rows() [][]CellEdit {
+	var rows [][]CellEdit
+	index := map[string]int{}
+	for _, k := range s.order {
+		if _, deleted := s.deletes[k.row]; deleted {
+			continue
+		}
+		e := s.edits[k]
+		i, ok := index[k.row]
+		if !ok {
+			i = len(rows)
+			index[k.row] = i
+			rows = append(rows, nil)
+		}
+		rows[i] = append(rows[i], e)
+	}
+	for _, row := range rows {
+		slices.SortFunc(row, func(a, b CellEdit) int { return a.Column - b.Column })
+	}
+	return rows
+}
+
+// sqlWriter writes values into statements, either as bind parameters or
+// inline as literals for previews
+type sqlWriter struct {
+	inline bool
+	args   []any
+}
+
+// text writes a value typed in as text, cast to the column's type
+func (w *sqlWriter) text(value *string, col database.ResultColumn) string {
+	switch {
+	case value == nil:
+		return "NULL"
+	case w.inline:
+		return quoteLiteral(*value)
+	}
+	w.args = append(w.args, *value)
+	return castText(fmt.Sprintf("$%d", len(w.args)), col)
+}
+
+// key writes a key value as read from the database
+func (w *sqlWriter) key(v any) string {
+	if w.inline {
+		return literal(v)
+	}
+	w.args = append(w.args, v)
+	return fmt.Sprintf("$%d", len(w.args))
+}
+
+// statements returns the UPDATEs of edited rows, then the DELETEs, then
+// the INSERTs, which read back the new rows with RETURNING
+func (s *EditSet) statements(inline bool) []database.Statement {
+	var statements []database.Statement
+	t := s.target
+	for _, row := range s.rows() {
+		w := &sqlWriter{inline: inline}
+		sets := make([]string, len(row))
+		for i, e := range row {
+			col := t.columns[e.Column]
+			sets[i] = quoteIdent(col.BaseColumn) + " = " + w.text(e.Value, col)
+		}
+		conditions := []string{t.keyCondition(row[0].Key, w)}
+		for _, e := range row {
+			conditions = append(conditions, originalCondition(e.Original, t.columns[e.Column], w))
+		}
+		sql := fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+			t.QualifiedName(), strings.Join(sets, ", "), strings.Join(conditions, " AND "))
+		statements = append(statements, database.Statement{SQL: sql, Args: w.args})
+	}
+	for _, k := range s.deleteOrder {
+		w := &sqlWriter{inline: inline}
+		sql := fmt.Sprintf("DELETE FROM %s WHERE %s", t.QualifiedName(), t.keyCondition(s.deletes[k], w))
+		statements = append(statements, database.Statement{SQL: sql, Args: w.args})
+	}
+	for _, insert := range s.inserts {
+		w := &sqlWriter{inline: inline}
+		var names, values []string
+		for col := range t.columns {
+			value, ok := insert.Values[col]
+			if !ok {
+				continue
+			}
+			names = append(names, quoteIdent(t.columns[col].BaseColumn))
+			values = append(values, w.text(value, t.columns[col]))
+		}
+		sql := fmt.Sprintf("INSERT INTO %s DEFAULT VALUES RETURNING *", t.QualifiedName())
+		if len(names) > 0 {
+			sql = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING *",
+				t.QualifiedName(), strings.Join(names, ", "), strings.Join(values, ", "))
+		}
+		statements = append(statements, database.Statement{SQL: sql, Args: w.args, Returning: true})
+	}
+	return statements
+}
+
+// Statements returns the statements that write the staged changes. New
+// values are sent as text and cast to the column's type by the server.
+func (s *EditSet) Statements() []database.Statement {
+	return s.statements(false)
+}
+
+// Preview returns the statements with their values written out, for
+// showing to the user before they run
+func (s *EditSet) Preview() []string {
+	var preview []string
+	for _, stmt := range s.statements(true) {
+		preview = append(preview, stmt.SQL+";")
+	}
+	return preview
+}
+
+// keyCondition matches the row with the given key values
+func (t *EditTarget) keyCondition(key []any, w *sqlWriter) string {
+	terms := make([]string, len(key))
+	for i, v := range key {
+		terms[i] = quoteIdent(t.KeyColumns[i]) + " = " + w.key(v)
+	}
+	return strings.Join(terms, " AND ")
+}
+
+// originalCondition matches a row only while col still holds the value v
+// read from it, so that an edit does not overwrite a change made meanwhile.
+// Types without an equality operator are compared by their text.
+func originalCondition(v any, col database.ResultColumn, w *sqlWriter) string {
+	name := quoteIdent(col.BaseColumn)
+	if v == nil {
+		return name + " IS NULL"
+	}
+	text := TextValue(v, col)
+	value := w.text(&text, col)
+	switch col.TypeOID {
+	case pgtype.JSONOID:
+		return name + "::jsonb = " + value + "::jsonb"
+	case pgtype.XMLOID, pgtype.PointOID, pgtype.LineOID, pgtype.LsegOID, pgtype.BoxOID,
+		pgtype.PathOID, pgtype.PolygonOID, pgtype.CircleOID:
+		return name + "::text = " + value + "::text"
+	}
+	return name + " = " + value
+}
+
+// castText casts a text parameter to the column's type without its
+// modifier: a cast to varchar(n) or char(n) would cut off longer values,
+// where assigning them to the column fails
+func castText(param string, col database.ResultColumn) string {
+	if col.BaseTypeName == "" {
+		return param
+	}
+	return "CAST(" + param + "::text AS " + col.BaseTypeName + ")"
+}
+
+// quoteLiteral quotes s as a SQL string literal
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// literal writes a key value as SQL in PostgreSQL's text format, unquoted
+// for finite numbers. Times are written with their offset, which timestamp
+// and date columns ignore.
+func literal(v any) string {
+	col := database.ResultColumn{}
+	if _, ok := v.(time.Time); ok {
+		col.TypeOID = pgtype.TimestamptzOID
+	}
+	text := TextValue(v, col)
+	if _, numeric := toFloat(v); numeric && numericPattern.MatchString(text) {
+		return text
+	}
+	return quoteLiteral(text)
+}
+
+// EditText returns v as text to edit: in PostgreSQL's text format rather
+// than as displayed, so that it reads back as the same value
+func EditText(v any, col database.ResultColumn) string {
+	return TextValue(v, col)
+}
+
+// EditOptions returns the values to pick from when editing col: the labels
+// of an enum, true and false for booleans, and nil for free text
+func EditOptions(col database.ResultColumn) []string {
+	if len(col.EnumLabels) > 0 {
+		return col.EnumLabels
+	}
+	if col.TypeOID == pgtype.BoolOID {
+		return []string{"true", "false"}
+	}
+	return nil
+}
+
+var (
+	numericPattern = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?$`)
+	uuidPattern    = regexp.MustCompile(`^\{?[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}\}?$`)
+)
+
+// ValidateInput checks that text is a valid value for col, for the types
+// that can be checked without asking the server. Everything else is left to
+// the cast when the edits are written.
+func ValidateInput(col database.ResultColumn, text string) error {
+	invalid := func(kind string) error {
+		return fmt.Errorf("%q is not a valid %s", text, kind)
+	}
+	trimmed := strings.TrimSpace(text)
+	switch col.TypeOID {
+	case pgtype.Int2OID, pgtype.Int4OID, pgtype.Int8OID:
+		bits := map[uint32]int{pgtype.Int2OID: 16, pgtype.Int4OID: 32, pgtype.Int8OID: 64}[col.TypeOID]
+		if _, err := strconv.ParseInt(trimmed, 10, bits); err != nil {
+			return invalid(fmt.Sprintf("%d-bit integer", bits))
+		}
+	case pgtype.Float4OID, pgtype.Float8OID:
+		switch strings.ToLower(trimmed) {
+		case "nan", "infinity", "-infinity":
+			return nil
+		}
+		if _, err := strconv.ParseFloat(trimmed, 64); err != nil {
+			return invalid("number")
+		}
+	case pgtype.NumericOID:
+		if !numericPattern.MatchString(trimmed) && !strings.EqualFold(trimmed, "NaN") {
+			return invalid("number")
+		}
+	case pgtype.BoolOID:
+		if _, err := parseBool(trimmed); err != nil {
+			return invalid("boolean")
+		}
+	case pgtype.DateOID:
+		if !isInfinity(trimmed) {
+			if _, err := time.Parse("2006-01-02", trimmed); err != nil {
+				return invalid("date (YYYY-MM-DD)")
+			}
+		}
+	case pgtype.TimestampOID, pgtype.TimestamptzOID:
+		if !isInfinity(trimmed) && !parsesAsTime(trimmed) {
+			return invalid("timestamp (YYYY-MM-DD HH:MM:SS)")
+		}
+	case pgtype.UUIDOID:
+		if !uuidPattern.MatchString(trimmed) {
+			return invalid("UUID")
+		}
+	case pgtype.JSONOID, pgtype.JSONBOID:
+		if !json.Valid([]byte(text)) {
+			return invalid("JSON value")
+		}
+	}
+	if len(col.EnumLabels) > 0 && !slices.Contains(col.EnumLabels, text) {
+		return fmt.Errorf("%q is not one of %s", text, strings.Join(col.EnumLabels, ", "))
+	}
+	return nil
+}
+
+func isInfinity(s string) bool {
+	return strings.EqualFold(s, "infinity") || strings.EqualFold(s, "-infinity")
+}
+
+// parsesAsTime reports whether s reads as a timestamp, with or without a
+// zone
+func parsesAsTime(s string) bool {
+	layouts := append([]string{"2006-01-02 15:04:05.999999999-07", "2006-01-02 15:04:05.999999999-07:00"}, timeLayouts...)
+	for _, layout := range layouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+	return false
+}
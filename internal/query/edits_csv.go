@@ -0,0 +1,129 @@
+package query
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/SavingFrame/dbettier/internal/database"
+)
+
+// CSVNull stands for NULL in CSV files, as in COPY's text format
+const CSVNull = `\N`
+
+// WriteCSV writes rows as CSV with a header of column names, for editing
+// outside dbettier. NULL is written as CSVNull.
+func (t *EditTarget) WriteCSV(w io.Writer, rows [][]any) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(database.ColumnNames(t.columns)); err != nil {
+		return err
+	}
+	record := make([]string, len(t.columns))
+	for _, row := range rows {
+		for i, v := range row {
+			record[i] = CSVNull
+			if v != nil {
+				record[i] = EditText(v, t.columns[i])
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvValue reads a CSV field, nil for NULL
+func csvValue(field string) *string {
+	if field == CSVNull {
+		return nil
+	}
+	return &field
+}
+
+// ApplyCSV stages the changes that turn rows into the rows read as CSV from
+// r, matching them by key: rows whose key changed nothing are left alone,
+// rows with other values are updated, rows missing from the CSV are
+// deleted, and rows with a new or empty key are inserted, with empty fields
+// of columns that have a default left to it. Nothing is staged if the CSV
+// cannot be read or holds an invalid value.
+func (s *EditSet) ApplyCSV(rows [][]any, r io.Reader) error {
+	t := s.target
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 || !slices.Equal(records[0], database.ColumnNames(t.columns)) {
+		return errors.New("the first line must name the columns as written")
+	}
+
+	keyText := func(fields []string) string {
+		parts := make([]string, len(t.keyIndexes))
+		for i, idx := range t.keyIndexes {
+			parts[i] = fields[idx]
+		}
+		return strings.Join(parts, "\x00")
+	}
+	originals := map[string]int{}
+	for i, row := range rows {
+		fields := make([]string, len(row))
+		for col, v := range row {
+			fields[col] = EditText(v, t.columns[col])
+		}
+		originals[keyText(fields)] = i
+	}
+
+	type update struct {
+		row    int
+		fields []string
+	}
+	var updates []update
+	var inserts []map[int]*string
+	seen := map[int]bool{}
+	for n, fields := range records[1:] {
+		line := n + 2
+		for col, field := range fields {
+			if _, hasDefault := t.Default(col); field == CSVNull || (field == "" && hasDefault) {
+				continue
+			}
+			if err := ValidateInput(t.columns[col], field); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		if i, ok := originals[keyText(fields)]; ok {
+			if seen[i] {
+				return fmt.Errorf("line %d: the row's key appears twice", line)
+			}
+			seen[i] = true
+			updates = append(updates, update{row: i, fields: fields})
+			continue
+		}
+		values := map[int]*string{}
+		for col, field := range fields {
+			if _, hasDefault := t.Default(col); hasDefault && field == "" {
+				continue
+			}
+			values[col] = csvValue(field)
+		}
+		inserts = append(inserts, values)
+	}
+
+	for _, u := range updates {
+		for col, field := range u.fields {
+			s.Set(rows[u.row], col, csvValue(field))
+		}
+	}
+	for i, row := range rows {
+		if !seen[i] && !s.Deleted(row) {
+			s.ToggleDelete(row)
+		}
+	}
+	for _, values := range inserts {
+		s.Insert(values)
+	}
+	return nil
+}
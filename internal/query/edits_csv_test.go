@@ -0,0 +1,43 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditSetApplyCSV(t *testing.T) {
+	rows := [][]any{
+		{int32(1), "ann", int16(30)},
+		{int32(2), "bob", nil},
+		{int32(3), "cid", int16(5)},
+	}
+	q := usersQuery(rows...)
+	q.ColumnDefaults = map[string]string{"id": "nextval('users_id_seq'::regclass)"}
+	target, err := q.EditTarget()
+	require.NoError(t, err)
+
+	var b strings.Builder
+	require.NoError(t, target.WriteCSV(&b, rows))
+	assert.Equal(t, "id,name,age\n1,ann,30\n2,bob,\\N\n3,cid,5\n", b.String())
+
+	// Change bob's age, drop cid and add a row with a generated id
+	edited := "id,name,age\n1,ann,30\n2,bob,41\n,dan,\\N\n"
+	edits := NewEditSet(target)
+	require.NoError(t, edits.ApplyCSV(rows, strings.NewReader(edited)))
+	assert.Equal(t, []string{
+		`UPDATE "public"."users" SET "age" = '41' WHERE "id" = 2 AND "age" IS NULL;`,
+		`DELETE FROM "public"."users" WHERE "id" = 3;`,
+		`INSERT INTO "public"."users" ("name", "age") VALUES ('dan', NULL) RETURNING *;`,
+	}, edits.Preview())
+
+	edits = NewEditSet(target)
+	err = edits.ApplyCSV(rows, strings.NewReader("id,name,age\n1,ann,old\n"))
+	assert.ErrorContains(t, err, "line 2")
+	assert.Zero(t, edits.Len())
+
+	err = edits.ApplyCSV(rows, strings.NewReader("name,id,age\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,171 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// usersQuery returns a table query over public.users with the given rows
+func usersQuery(rows ...[]any) *TableQuery {
+	column := func(name, typeName, baseTypeName string, oid uint32, attnum uint16) database.ResultColumn {
+		return database.ResultColumn{
+			Name: name, TableOID: 42, TableAttributeNumber: attnum,
+			TableSchema: "public", TableName: "users", BaseColumn: name,
+			TypeName: typeName, BaseTypeName: baseTypeName, TypeOID: oid,
+		}
+	}
+	q := NewTableQuery(`SELECT * FROM "users";`, 100)
+	q.SetKeyColumns([]string{"id"})
+	q.SetSQLResult(&SQLResultMsg{
+		Columns: []database.ResultColumn{
+			column("id", "integer", "integer", pgtype.Int4OID, 1),
+			column("name", "character varying(64)", "character varying", pgtype.VarcharOID, 2),
+			column("age", "smallint", "smallint", pgtype.Int2OID, 3),
+		},
+		Rows: rows,
+	})
+	return q
+}
+
+func TestEditTarget(t *testing.T) {
+	target, err := usersQuery().EditTarget()
+	require.NoError(t, err)
+	assert.Equal(t, `"public"."users"`, target.QualifiedName())
+
+	q := usersQuery()
+	q.SetKeyColumns(nil)
+	_, err = q.EditTarget()
+	assert.Error(t, err)
+
+	q = usersQuery()
+	q.SQLResult.Columns[1].TableOID = 0
+	_, err = q.EditTarget()
+	assert.Error(t, err)
+}
+
+func TestEditSetStatements(t *testing.T) {
+	rowA := []any{int32(1), "ann", int16(30)}
+	rowB := []any{int32(2), "bob", nil}
+	target, err := usersQuery(rowA, rowB).EditTarget()
+	require.NoError(t, err)
+
+	edits := NewEditSet(target)
+	name := "o'brien"
+	edits.Set(rowB, 1, &name)
+	edits.Set(rowA, 2, nil)
+	age := "41"
+	edits.Set(rowB, 2, &age)
+	assert.Equal(t, 3, edits.Len())
+
+	statements := edits.Statements()
+	require.Len(t, statements, 2)
+	// Values are cast to varchar without its length, so that assigning one
+	// that is too long fails rather than being cut off. Rows are only
+	// updated while the edited cells hold the values read.
+	assert.Equal(t, `UPDATE "public"."users" SET "name" = CAST($1::text AS character varying), "age" = CAST($2::text AS smallint) `+
+		`WHERE "id" = $3 AND "name" = CAST($4::text AS character varying) AND "age" IS NULL`, statements[0].SQL)
+	assert.Equal(t, []any{"o'brien", "41", int32(2), "bob"}, statements[0].Args)
+	assert.Equal(t, `UPDATE "public"."users" SET "age" = NULL WHERE "id" = $1 AND "age" = CAST($2::text AS smallint)`, statements[1].SQL)
+	assert.Equal(t, []any{int32(1), "30"}, statements[1].Args)
+
+	assert.Equal(t, []string{
+		`UPDATE "public"."users" SET "name" = 'o''brien', "age" = '41' WHERE "id" = 2 AND "name" = 'bob' AND "age" IS NULL;`,
+		`UPDATE "public"."users" SET "age" = NULL WHERE "id" = 1 AND "age" = '30';`,
+	}, edits.Preview())
+
+	// Edits are found by key, so a reloaded copy of the row still matches
+	e, ok := edits.Lookup([]any{int32(2), "bob", nil}, 1)
+	require.True(t, ok)
+	assert.Equal(t, "o'brien", *e.Value)
+
+	// Setting a cell back to its original value drops the edit
+	original := "bob"
+	edits.Set(rowB, 1, &original)
+	edits.Set(rowB, 2, nil)
+	_, ok = edits.Lookup(rowB, 1)
+	assert.False(t, ok)
+	assert.Equal(t, 1, edits.Len())
+
+	edits.Clear()
+	assert.Empty(t, edits.Statements())
+}
+
+func TestEditSetInsertsAndDeletes(t *testing.T) {
+	rowA := []any{int32(1), "ann", int16(30)}
+	rowB := []any{int32(2), "bob", nil}
+	q := usersQuery(rowA, rowB)
+	q.ColumnDefaults = map[string]string{"id": "nextval('users_id_seq'::regclass)"}
+	target, err := q.EditTarget()
+	require.NoError(t, err)
+
+	edits := NewEditSet(target)
+	name := "eve"
+	edits.Set(rowA, 1, &name)
+	assert.True(t, edits.ToggleDelete(rowA))
+	assert.True(t, edits.Deleted(rowA))
+	edits.Insert(nil)
+	// The serial key is left to its default
+	dup := edits.DuplicateRow(rowB)
+	assert.NotContains(t, dup, 0)
+	require.NotNil(t, dup[1])
+	assert.Equal(t, "bob", *dup[1])
+	assert.Contains(t, dup, 2)
+	assert.Nil(t, dup[2])
+	edits.Insert(dup)
+	assert.Equal(t, 4, edits.Len())
+
+	// The deleted row's edit is not written
+	statements := edits.Statements()
+	require.Len(t, statements, 3)
+	assert.Equal(t, `DELETE FROM "public"."users" WHERE "id" = $1`, statements[0].SQL)
+	assert.Equal(t, []any{int32(1)}, statements[0].Args)
+	assert.Equal(t, `INSERT INTO "public"."users" DEFAULT VALUES RETURNING *`, statements[1].SQL)
+	assert.True(t, statements[1].Returning)
+	assert.Equal(t, `INSERT INTO "public"."users" ("name", "age") VALUES (CAST($1::text AS character varying), NULL) RETURNING *`, statements[2].SQL)
+	assert.Equal(t, []any{"bob"}, statements[2].Args)
+
+	assert.False(t, edits.ToggleDelete(rowA))
+	edits.RemoveInsert(0)
+	assert.Equal(t, []string{
+		`UPDATE "public"."users" SET "name" = 'eve' WHERE "id" = 1 AND "name" = 'ann';`,
+		`INSERT INTO "public"."users" ("name", "age") VALUES ('bob', NULL) RETURNING *;`,
+	}, edits.Preview())
+}
+
+func TestValidateInput(t *testing.T) {
+	typed := func(oid uint32) database.ResultColumn { return database.ResultColumn{TypeOID: oid} }
+	tests := []struct {
+		col   database.ResultColumn
+		input string
+		valid bool
+	}{
+		{typed(pgtype.Int2OID), "32767", true},
+		{typed(pgtype.Int2OID), "32768", false},
+		{typed(pgtype.Int8OID), "12a", false},
+		{typed(pgtype.Float8OID), "-Infinity", true},
+		{typed(pgtype.NumericOID), "1.5e3", true},
+		{typed(pgtype.NumericOID), "1,5", false},
+		{typed(pgtype.BoolOID), "yes", true},
+		{typed(pgtype.BoolOID), "maybe", false},
+		{typed(pgtype.DateOID), "2024-02-30", false},
+		{typed(pgtype.TimestamptzOID), "2024-02-01 10:00:00+02", true},
+		{typed(pgtype.UUIDOID), "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", true},
+		{typed(pgtype.JSONBOID), `{"a":`, false},
+		{typed(pgtype.TextOID), "anything", true},
+		{database.ResultColumn{EnumLabels: []string{"draft", "sent"}}, "sent", true},
+		{database.ResultColumn{EnumLabels: []string{"draft", "sent"}}, "lost", false},
+	}
+	for _, tt := range tests {
+		err := ValidateInput(tt.col, tt.input)
+		if tt.valid {
+			assert.NoError(t, err, tt.input)
+		} else {
+			assert.Error(t, err, tt.input)
+		}
+	}
+}
@@ -4,8 +4,11 @@ import tea "charm.land/bubbletea/v2"
 
 type ExecutableQuery interface {
 	Compile() string
+	Args() []any
 	HandleSortChange(orderBy OrderByClauses) tea.Cmd
 	GetSortOrders() OrderByClauses
+	SetWhereClause(whereClause string) tea.Cmd
+	SortFilterMode() SortFilterMode
 	SetSQLResult(*SQLResultMsg) *SQLResult
 	GetSQLResult() *SQLResult
 	HasPreviousPage() bool
@@ -15,3 +18,30 @@ type ExecutableQuery interface {
 	Rows() [][]any
 	PageOffset() int
 }
+
+// SortFilterMode tells where a result is sorted and filtered
+type SortFilterMode int
+
+const (
+	sortFilterUnset SortFilterMode = iota
+	// SortFilterServer re-runs the query with ORDER BY and WHERE
+	SortFilterServer
+	// SortFilterLocal sorts and filters the rows held in memory
+	SortFilterLocal
+)
+
+func (m SortFilterMode) String() string {
+	if m == SortFilterLocal {
+		return "in memory"
+	}
+	return "on server"
+}
+
+// DisplayText returns the query as the user wrote it, which differs from
+// Compile for ad-hoc statements with named placeholders.
+func DisplayText(q ExecutableQuery) string {
+	if bq, ok := q.(*BasicSQLQuery); ok {
+		return bq.Query
+	}
+	return q.Compile()
+}
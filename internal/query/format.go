@@ -0,0 +1,461 @@
+package query
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DisplayOptions control how values are rendered in results
+type DisplayOptions struct {
+	// TimestampLayout is the Go time layout for timestamp values
+	TimestampLayout string
+	// ZoneLayout is appended to TimestampLayout for timestamptz values. When
+	// empty the offset is written as PostgreSQL does: +05, or +05:30 when it
+	// is not a whole number of hours.
+	ZoneLayout string
+	// DateLayout is the Go time layout for date values
+	DateLayout string
+	// Location is the time zone timestamptz values are shown in
+	Location *time.Location
+	// GroupDigits separates thousands in integers and numerics
+	GroupDigits bool
+	// NullText is shown for NULL
+	NullText string
+}
+
+// Display holds the options used by FormatCell. The defaults match psql.
+var Display = DisplayOptions{
+	TimestampLayout: "2006-01-02 15:04:05.999999",
+	DateLayout:      "2006-01-02",
+	Location:        time.Local,
+	NullText:        "NULL",
+}
+
+// textOptions render values in PostgreSQL's own text format, which reads
+// back as the same value: ISO dates and times in the value's time zone, and
+// no digit grouping
+var textOptions = DisplayOptions{
+	TimestampLayout: "2006-01-02 15:04:05.999999",
+	DateLayout:      "2006-01-02",
+	NullText:        "NULL",
+}
+
+// Formatter renders a non-NULL value of a column as text
+type Formatter func(v any, col database.ResultColumn) string
+
+// optionsFormatter renders a non-NULL value of a column as text with the
+// given options
+type optionsFormatter func(v any, col database.ResultColumn, o *DisplayOptions) string
+
+// builtinFormatters render the built-in types that have display options
+var builtinFormatters = map[uint32]optionsFormatter{
+	pgtype.Int2OID:        formatInteger,
+	pgtype.Int4OID:        formatInteger,
+	pgtype.Int8OID:        formatInteger,
+	pgtype.NumericOID:     formatNumeric,
+	pgtype.TimestampOID:   formatTimestamp,
+	pgtype.TimestamptzOID: formatTimestamp,
+	pgtype.DateOID:        formatTimestamp,
+	pgtype.RecordOID:      formatRecord,
+}
+
+// plainFormatters render the built-in types whose text pgx would render
+// differently from PostgreSQL
+var plainFormatters = map[uint32]Formatter{
+	pgtype.BoolOID:   formatBool,
+	pgtype.Float4OID: formatFloat,
+	pgtype.Float8OID: formatFloat,
+	pgtype.InetOID:   formatInet,
+}
+
+var (
+	formattersMu     sync.RWMutex
+	formattersByOID  = map[uint32]Formatter{}
+	formattersByName = map[string]Formatter{}
+)
+
+func init() {
+	for oid, f := range builtinFormatters {
+		RegisterFormatter(oid, withDisplay(f))
+	}
+	for oid, f := range plainFormatters {
+		RegisterFormatter(oid, f)
+	}
+	// Types without a fixed OID, such as those from extensions
+	RegisterFormatterByName("hstore", formatHstore)
+}
+
+// withDisplay renders with the display options
+func withDisplay(f optionsFormatter) Formatter {
+	return func(v any, col database.ResultColumn) string {
+		return f(v, col, &Display)
+	}
+}
+
+// RegisterFormatter sets the formatter for values of the type with the
+// given OID
+func RegisterFormatter(oid uint32, f Formatter) {
+	formattersMu.Lock()
+	defer formattersMu.Unlock()
+	formattersByOID[oid] = f
+}
+
+// RegisterFormatterByName sets the formatter for values of the named type,
+// for types without a fixed OID
+func RegisterFormatterByName(name string, f Formatter) {
+	formattersMu.Lock()
+	defer formattersMu.Unlock()
+	formattersByName[name] = f
+}
+
+// FormatCell renders a value of col for display
+func FormatCell(v any, col database.ResultColumn) string {
+	if v == nil {
+		return Display.NullText
+	}
+	formattersMu.RLock()
+	f, ok := formattersByOID[col.TypeOID]
+	if !ok {
+		f, ok = formattersByName[col.TypeName]
+	}
+	formattersMu.RUnlock()
+	if ok {
+		return f(v, col)
+	}
+	return formatDefault(v, col, &Display)
+}
+
+// FormatValue renders a value whose column type is unknown
+func FormatValue(v any) string {
+	return FormatCell(v, database.ResultColumn{})
+}
+
+// TextValue renders a value of col in PostgreSQL's text format, whatever
+// the display options, for text that is read back: values to edit, SQL
+// literals and exported files. NULL is "".
+func TextValue(v any, col database.ResultColumn) string {
+	if v == nil {
+		return ""
+	}
+	if f, ok := builtinFormatters[col.TypeOID]; ok {
+		return f(v, col, &textOptions)
+	}
+	if f, ok := plainFormatters[col.TypeOID]; ok {
+		return f(v, col)
+	}
+	return formatDefault(v, col, &textOptions)
+}
+
+// formatValue renders a value whose column type is unknown with o
+func formatValue(v any, o *DisplayOptions) string {
+	if v == nil {
+		return o.NullText
+	}
+	if o == &Display {
+		return FormatValue(v)
+	}
+	return formatDefault(v, database.ResultColumn{}, o)
+}
+
+var (
+	// textMap encodes values to PostgreSQL's text format; a pgtype.Map
+	// caches plans and is not safe for concurrent use
+	textMap   = pgtype.NewMap()
+	textMapMu sync.Mutex
+)
+
+// formatDefault renders v the way PostgreSQL prints its type with o,
+// falling back to its Go type when the column type is unknown
+func formatDefault(v any, col database.ResultColumn, o *DisplayOptions) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case time.Time, pgtype.InfinityModifier:
+		return formatTimestamp(v, col, o)
+	case pgtype.Range[any]:
+		return formatRange(v, o)
+	case pgtype.Multirange[pgtype.Range[any]]:
+		ranges := make([]string, len(v))
+		for i, r := range v {
+			ranges[i] = formatRange(r, o)
+		}
+		return "{" + strings.Join(ranges, ",") + "}"
+	case net.HardwareAddr:
+		return v.String()
+	case netip.Prefix:
+		return formatInet(v, col)
+	case pgtype.Hstore, map[string]*string:
+		return formatHstore(v, col)
+	}
+
+	if col.TypeOID != 0 {
+		textMapMu.Lock()
+		buf, err := textMap.Encode(col.TypeOID, pgtype.TextFormatCode, v, nil)
+		textMapMu.Unlock()
+		if err == nil && buf != nil {
+			return string(buf)
+		}
+	}
+
+	switch v := v.(type) {
+	case []byte:
+		return `\x` + hex.EncodeToString(v)
+	case [16]byte:
+		return fmt.Sprintf("%x-%x-%x-%x-%x", v[0:4], v[4:6], v[6:8], v[8:10], v[10:16])
+	case bool:
+		return formatBool(v, col)
+	case int8, int16, int32, int64, int, uint8, uint16, uint32, uint64, uint:
+		return formatInteger(v, col, o)
+	case float32, float64:
+		return formatFloat(v, col)
+	case pgtype.Numeric:
+		return formatNumeric(v, col, o)
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatValue(item, o)
+		}
+		return "{" + strings.Join(items, ",") + "}"
+	case map[string]any:
+		return fmt.Sprintf("%v", v)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func formatBool(v any, _ database.ResultColumn) string {
+	if b, ok := v.(bool); ok {
+		return strconv.FormatBool(b)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func formatInteger(v any, _ database.ResultColumn, o *DisplayOptions) string {
+	s := fmt.Sprintf("%d", v)
+	if o.GroupDigits {
+		return groupDigits(s)
+	}
+	return s
+}
+
+func formatFloat(v any, _ database.ResultColumn) string {
+	var f float64
+	bits := 64
+	switch v := v.(type) {
+	case float32:
+		f, bits = float64(v), 32
+	case float64:
+		f = v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+	return strconv.FormatFloat(f, 'g', -1, bits)
+}
+
+func formatNumeric(v any, _ database.ResultColumn, o *DisplayOptions) string {
+	n, ok := v.(pgtype.Numeric)
+	if !ok {
+		return formatDefault(v, database.ResultColumn{}, o)
+	}
+	buf, err := pgtype.NumericCodec{}.PlanEncode(nil, pgtype.NumericOID, pgtype.TextFormatCode, n).Encode(n, nil)
+	if err != nil {
+		return fmt.Sprintf("ERR: %v", err)
+	}
+	s := string(buf)
+	if o.GroupDigits && n.Valid && !n.NaN && n.InfinityModifier == pgtype.Finite {
+		return groupDigits(s)
+	}
+	return s
+}
+
+// groupDigits inserts thousands separators into the integer part of a
+// decimal number
+func groupDigits(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	intPart, frac, hasFrac := strings.Cut(s, ".")
+	var b strings.Builder
+	for i, d := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	if hasFrac {
+		return sign + b.String() + "." + frac
+	}
+	return sign + b.String()
+}
+
+// formatTimestamp renders dates and times, in o's time zone if it has one
+// and otherwise in the value's
+func formatTimestamp(v any, col database.ResultColumn, o *DisplayOptions) string {
+	switch v := v.(type) {
+	case pgtype.InfinityModifier:
+		return v.String()
+	case time.Time:
+		switch col.TypeOID {
+		case pgtype.DateOID:
+			return v.Format(o.DateLayout)
+		case pgtype.TimestamptzOID:
+			if o.Location != nil {
+				v = v.In(o.Location)
+			}
+			if o.ZoneLayout == "" {
+				return v.Format(o.TimestampLayout) + zoneOffset(v)
+			}
+			return v.Format(o.TimestampLayout + o.ZoneLayout)
+		}
+		return v.Format(o.TimestampLayout)
+	}
+	return formatDefault(v, database.ResultColumn{}, o)
+}
+
+// zoneOffset returns the UTC offset of t as PostgreSQL prints it: hours,
+// then minutes and seconds only when they are not zero
+func zoneOffset(t time.Time) string {
+	_, offset := t.Zone()
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	s := fmt.Sprintf("%c%02d", sign, offset/3600)
+	if rest := offset % 3600; rest != 0 {
+		s += fmt.Sprintf(":%02d", rest/60)
+		if rest%60 != 0 {
+			s += fmt.Sprintf(":%02d", rest%60)
+		}
+	}
+	return s
+}
+
+// formatInet leaves out the prefix length of single addresses, as
+// PostgreSQL does for inet
+func formatInet(v any, col database.ResultColumn) string {
+	p, ok := v.(netip.Prefix)
+	if !ok {
+		return formatDefault(v, database.ResultColumn{}, &Display)
+	}
+	if col.TypeOID != pgtype.CIDROID && p.IsSingleIP() {
+		return p.Addr().String()
+	}
+	return p.String()
+}
+
+// formatRange renders a range like [1,10) or empty
+func formatRange(r pgtype.Range[any], o *DisplayOptions) string {
+	if r.LowerType == pgtype.Empty {
+		return "empty"
+	}
+	var b strings.Builder
+	if r.LowerType == pgtype.Inclusive {
+		b.WriteByte('[')
+	} else {
+		b.WriteByte('(')
+	}
+	if r.LowerType != pgtype.Unbounded {
+		b.WriteString(quoteElement(formatValue(r.Lower, o), `"\()[],`))
+	}
+	b.WriteByte(',')
+	if r.UpperType != pgtype.Unbounded {
+		b.WriteString(quoteElement(formatValue(r.Upper, o), `"\()[],`))
+	}
+	if r.UpperType == pgtype.Inclusive {
+		b.WriteByte(']')
+	} else {
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+// formatRecord renders an anonymous record read in binary format, such as
+// SELECT ROW(1, 'a'). As in PostgreSQL a NULL field is left empty and an
+// empty string is written "".
+func formatRecord(v any, _ database.ResultColumn, o *DisplayOptions) string {
+	fields, ok := v.([]any)
+	if !ok {
+		return formatDefault(v, database.ResultColumn{}, o)
+	}
+	items := make([]string, len(fields))
+	for i, field := range fields {
+		if field != nil {
+			items[i] = quoteElement(formatValue(field, o), `"\(),`)
+		}
+	}
+	return "(" + strings.Join(items, ",") + ")"
+}
+
+// formatHstore renders hstore values with sorted keys, whether pgx decoded
+// them or returned PostgreSQL's text
+func formatHstore(v any, _ database.ResultColumn) string {
+	h, ok := v.(pgtype.Hstore)
+	if !ok {
+		if m, isMap := v.(map[string]*string); isMap {
+			h, ok = pgtype.Hstore(m), true
+		}
+	}
+	if !ok {
+		return formatDefault(v, database.ResultColumn{}, &Display)
+	}
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		value := "NULL"
+		if h[k] != nil {
+			value = quoteHstore(*h[k])
+		}
+		pairs[i] = quoteHstore(k) + "=>" + value
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// quoteElement quotes a record field or range bound the way PostgreSQL
+// writes them: in double quotes, with quotes and backslashes doubled, when
+// it is empty or holds whitespace or one of special
+func quoteElement(s, special string) string {
+	if s != "" && !strings.ContainsAny(s, special+" \t\n\r\v\f") {
+		return s
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		if r == '"' || r == '\\' {
+			b.WriteRune(r)
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// quoteHstore quotes an hstore key or value, escaping quotes and
+// backslashes with a backslash as hstore does
+func quoteHstore(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,114 @@
+package query
+
+import (
+	"math"
+	"math/big"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatCell(t *testing.T) {
+	col := func(oid uint32) database.ResultColumn { return database.ResultColumn{TypeOID: oid} }
+	ts := time.Date(2024, 3, 1, 12, 30, 0, 500000000, time.UTC)
+	text := func(s string) *string { return &s }
+
+	tests := []struct {
+		name string
+		v    any
+		col  database.ResultColumn
+		want string
+	}{
+		{"null", nil, col(pgtype.TextOID), "NULL"},
+		{"bool", true, col(pgtype.BoolOID), "true"},
+		{"int", int64(1234567), col(pgtype.Int8OID), "1234567"},
+		{"float", 1.5, col(pgtype.Float8OID), "1.5"},
+		{"float nan", math.NaN(), col(pgtype.Float8OID), "NaN"},
+		{"numeric", pgtype.Numeric{Int: big.NewInt(12345), Exp: -2, Valid: true}, col(pgtype.NumericOID), "123.45"},
+		{"timestamp", ts, col(pgtype.TimestampOID), "2024-03-01 12:30:00.5"},
+		{"date", ts, col(pgtype.DateOID), "2024-03-01"},
+		{"date infinity", pgtype.Infinity, col(pgtype.DateOID), "infinity"},
+		{"uuid", [16]byte{0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0, 0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0},
+			col(pgtype.UUIDOID), "12345678-9abc-def0-1234-56789abcdef0"},
+		{"bytea", []byte{0xde, 0xad}, col(pgtype.ByteaOID), `\xdead`},
+		{"interval", pgtype.Interval{Days: 1, Microseconds: 3600000000, Valid: true}, col(pgtype.IntervalOID), "1 day 01:00:00"},
+		{"inet", netip.MustParsePrefix("10.0.0.1/32"), col(pgtype.InetOID), "10.0.0.1"},
+		{"cidr", netip.MustParsePrefix("10.0.0.0/8"), col(pgtype.CIDROID), "10.0.0.0/8"},
+		{"macaddr", net.HardwareAddr{0x08, 0, 0x2b, 1, 2, 3}, col(pgtype.MacaddrOID), "08:00:2b:01:02:03"},
+		{"int array", []any{int32(1), int32(2)}, col(pgtype.Int4ArrayOID), "{1,2}"},
+		{"range", pgtype.Range[any]{Lower: int32(1), Upper: int32(10), LowerType: pgtype.Inclusive, UpperType: pgtype.Exclusive, Valid: true},
+			col(pgtype.Int4rangeOID), "[1,10)"},
+		{"unbounded range", pgtype.Range[any]{Lower: int32(1), LowerType: pgtype.Exclusive, UpperType: pgtype.Unbounded, Valid: true},
+			col(pgtype.Int4rangeOID), "(1,)"},
+		{"empty range", pgtype.Range[any]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true}, col(pgtype.Int4rangeOID), "empty"},
+		{"record", []any{int32(1), "a", nil, "", `say "hi", (x)`}, col(pgtype.RecordOID), `(1,a,,"","say ""hi"", (x)")`},
+		{"quoted range", pgtype.Range[any]{Lower: "a b", Upper: `c\d`, LowerType: pgtype.Inclusive, UpperType: pgtype.Inclusive, Valid: true},
+			database.ResultColumn{TypeName: "textrange"}, `["a b","c\\d"]`},
+		{"point", pgtype.Point{P: pgtype.Vec2{X: 1, Y: 2}, Valid: true}, col(pgtype.PointOID), "(1,2)"},
+		{"hstore", map[string]*string{"b": text(`say "hi"`), "a": nil}, database.ResultColumn{TypeOID: 16500, TypeName: "hstore"}, `"a"=>NULL, "b"=>"say \"hi\""`},
+		{"unknown type", "text", database.ResultColumn{}, "text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatCell(tt.v, tt.col))
+		})
+	}
+}
+
+func TestFormatCellOptions(t *testing.T) {
+	saved := Display
+	defer func() { Display = saved }()
+
+	Display.GroupDigits = true
+	assert.Equal(t, "-1,234,567", FormatCell(int64(-1234567), database.ResultColumn{TypeOID: pgtype.Int8OID}))
+	assert.Equal(t, "12,345.678", FormatCell(pgtype.Numeric{Int: big.NewInt(12345678), Exp: -3, Valid: true},
+		database.ResultColumn{TypeOID: pgtype.NumericOID}))
+
+	Display.Location = time.FixedZone("", 2*60*60)
+	Display.TimestampLayout = "02.01.2006 15:04"
+	ts := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	assert.Equal(t, "01.03.2024 14:30+02", FormatCell(ts, database.ResultColumn{TypeOID: pgtype.TimestamptzOID}))
+	Display.Location = time.FixedZone("", -(5*60*60 + 30*60))
+	assert.Equal(t, "01.03.2024 07:00-05:30", FormatCell(ts, database.ResultColumn{TypeOID: pgtype.TimestamptzOID}))
+
+	RegisterFormatter(pgtype.BoolOID, func(v any, _ database.ResultColumn) string {
+		if v.(bool) {
+			return "✓"
+		}
+		return "✗"
+	})
+	defer RegisterFormatter(pgtype.BoolOID, formatBool)
+	assert.Equal(t, "✓", FormatCell(true, database.ResultColumn{TypeOID: pgtype.BoolOID}))
+}
+
+func TestTextValue(t *testing.T) {
+	saved := Display
+	defer func() { Display = saved }()
+	Display.GroupDigits = true
+	Display.Location = time.UTC
+	Display.TimestampLayout = "02.01.2006 15:04"
+	Display.DateLayout = "02.01.2006"
+
+	kolkata := time.FixedZone("IST", 5*60*60+30*60)
+	ts := time.Date(2024, 1, 1, 17, 30, 0, 250000000, kolkata)
+	col := func(oid uint32) database.ResultColumn { return database.ResultColumn{TypeOID: oid} }
+	assert.Equal(t, "", TextValue(nil, col(pgtype.TextOID)))
+	assert.Equal(t, "48213", TextValue(int64(48213), col(pgtype.Int8OID)))
+	assert.Equal(t, "true", TextValue(true, col(pgtype.BoolOID)))
+	assert.Equal(t, "Infinity", TextValue(math.Inf(1), col(pgtype.Float8OID)))
+	assert.Equal(t, "12345.678", TextValue(pgtype.Numeric{Int: big.NewInt(12345678), Exp: -3, Valid: true}, col(pgtype.NumericOID)))
+	assert.Equal(t, "2024-01-01 17:30:00.25+05:30", TextValue(ts, col(pgtype.TimestamptzOID)))
+	assert.Equal(t, "2024-01-01 17:30:00.25", TextValue(ts, col(pgtype.TimestampOID)))
+	assert.Equal(t, "2024-01-01", TextValue(ts, col(pgtype.DateOID)))
+	assert.Equal(t, "(48213,)", TextValue([]any{int64(48213), nil}, col(pgtype.RecordOID)))
+	assert.Equal(t, "[1000,2000)", TextValue(pgtype.Range[any]{Lower: int64(1000), Upper: int64(2000),
+		LowerType: pgtype.Inclusive, UpperType: pgtype.Exclusive, Valid: true}, col(pgtype.Int8rangeOID)))
+
+	// Display is unchanged
+	assert.Equal(t, "01.01.2024 12:00+00", FormatCell(ts, col(pgtype.TimestamptzOID)))
+}
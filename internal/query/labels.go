@@ -0,0 +1,103 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SavingFrame/dbettier/internal/database"
+)
+
+// LabelLookup resolves a foreign key of a result to a column of the
+// referenced row that tells a person what the key stands for, such as a
+// customer's name next to its customer_id
+type LabelLookup struct {
+	// Column is the result column the label is shown next to: the last
+	// column of the key, which for composite keys is usually the one that
+	// differs between rows
+	Column int
+	// Columns are the result columns holding the foreign key
+	Columns     []int
+	RefSchema   string
+	RefTable    string
+	RefColumns  []string
+	LabelColumn string
+	// Labels maps the LabelKey of a foreign key's values to their label
+	Labels map[string]string
+}
+
+// LabelKey identifies foreign key values in LabelLookup.Labels. Values of
+// different integer types that are equal get the same key, as a key column
+// may be an int4 referencing an int8.
+func LabelKey(values []any) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = TextValue(v, database.ResultColumn{})
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// key returns the foreign key values of row, or false if one is NULL and
+// the row references nothing
+func (l *LabelLookup) key(row []any) ([]any, bool) {
+	values := make([]any, len(l.Columns))
+	for i, col := range l.Columns {
+		if col >= len(row) || row[col] == nil {
+			return nil, false
+		}
+		values[i] = row[col]
+	}
+	return values, true
+}
+
+// Label returns the label of the row referenced by row
+func (l *LabelLookup) Label(row []any) (string, bool) {
+	values, ok := l.key(row)
+	if !ok {
+		return "", false
+	}
+	label, ok := l.Labels[LabelKey(values)]
+	return label, ok
+}
+
+// MissingKeys returns the distinct foreign key values of rows that have
+// not been looked up yet
+func (l *LabelLookup) MissingKeys(rows [][]any) [][]any {
+	var keys [][]any
+	seen := map[string]bool{}
+	for _, row := range rows {
+		values, ok := l.key(row)
+		if !ok {
+			continue
+		}
+		k := LabelKey(values)
+		if _, known := l.Labels[k]; known || seen[k] {
+			continue
+		}
+		seen[k] = true
+		keys = append(keys, values)
+	}
+	return keys
+}
+
+// Query returns one statement reading the labels of all keys, which
+// returns the key columns followed by the label as text
+func (l *LabelLookup) Query(keys [][]any) (string, []any) {
+	columns := make([]string, len(l.RefColumns))
+	for i, col := range l.RefColumns {
+		columns[i] = quoteIdent(col)
+	}
+	var args []any
+	tuples := make([]string, len(keys))
+	for i, key := range keys {
+		params := make([]string, len(key))
+		for j, v := range key {
+			args = append(args, v)
+			params[j] = fmt.Sprintf("$%d", len(args))
+		}
+		tuples[i] = "(" + strings.Join(params, ", ") + ")"
+	}
+	keyList := strings.Join(columns, ", ")
+	return fmt.Sprintf("SELECT %s, %s::text FROM %s.%s WHERE (%s) IN (%s)",
+		keyList, quoteIdent(l.LabelColumn), quoteIdent(l.RefSchema), quoteIdent(l.RefTable),
+		keyList, strings.Join(tuples, ", ")), args
+}
@@ -0,0 +1,36 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelLookup(t *testing.T) {
+	l := &LabelLookup{
+		Column: 2, Columns: []int{1, 2},
+		RefSchema: "public", RefTable: "users", RefColumns: []string{"tenant", "id"},
+		LabelColumn: "name",
+	}
+	rows := [][]any{
+		{int32(1), int32(7), int32(10)},
+		{int32(2), int32(7), nil},
+		{int32(3), int32(7), int32(10)},
+		{int32(4), int32(7), int32(11)},
+	}
+	keys := l.MissingKeys(rows)
+	assert.Equal(t, [][]any{{int32(7), int32(10)}, {int32(7), int32(11)}}, keys)
+
+	sql, args := l.Query(keys)
+	assert.Equal(t, `SELECT "tenant", "id", "name"::text FROM "public"."users" WHERE ("tenant", "id") IN (($1, $2), ($3, $4))`, sql)
+	assert.Equal(t, []any{int32(7), int32(10), int32(7), int32(11)}, args)
+
+	// The referenced key may be read back as a wider integer type
+	l.Labels = map[string]string{LabelKey([]any{int64(7), int64(10)}): "ann"}
+	label, ok := l.Label(rows[0])
+	assert.True(t, ok)
+	assert.Equal(t, "ann", label)
+	_, ok = l.Label(rows[1])
+	assert.False(t, ok)
+	assert.Equal(t, [][]any{{int32(7), int32(11)}}, l.MissingKeys(rows))
+}
@@ -0,0 +1,376 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SavingFrame/dbettier/internal/database"
+)
+
+// RowPredicate reports whether a row passes a filter
+type RowPredicate func(row []any) bool
+
+// errNoCondition means the filter text is not a list of conditions and is
+// matched as plain text instead
+var errNoCondition = errors.New("not a condition")
+
+// ParseLocalFilter parses a filter for rows held in memory. It understands
+// a subset of SQL conditions joined by AND:
+//
+//	col = 'value'    col <> 1    col >= 2024-01-01
+//	col LIKE 'a%'    col NOT ILIKE '%b'
+//	col IS NULL      col IS NOT NULL
+//
+// Values are compared using the column's values' types, and text matches
+// against cells as they are displayed. Text that does not start with a
+// condition matches rows with any cell containing it, ignoring case.
+func ParseLocalFilter(expr string, columns []database.ResultColumn) (RowPredicate, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return func([]any) bool { return true }, nil
+	}
+	tokens, err := tokenizeFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := filterParser{tokens: tokens, columns: columns}
+	predicate, err := p.parse()
+	if errors.Is(err, errNoCondition) {
+		return containsText(expr, columns), nil
+	}
+	return predicate, err
+}
+
+func containsText(text string, columns []database.ResultColumn) RowPredicate {
+	text = strings.ToLower(text)
+	return func(row []any) bool {
+		for i, cell := range row {
+			if cell != nil && strings.Contains(strings.ToLower(FormatCell(cell, columns[i])), text) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+type filterTokenKind int
+
+const (
+	tokenWord filterTokenKind = iota
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+type filterToken struct {
+	kind  filterTokenKind
+	value string
+}
+
+// keyword reports whether t is the given unquoted keyword
+func (t filterToken) keyword(kw string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.value, kw)
+}
+
+func tokenizeFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'' || c == '"':
+			var value strings.Builder
+			end := i + 1
+			for ; end < len(expr); end++ {
+				if expr[end] != c {
+					value.WriteByte(expr[end])
+					continue
+				}
+				if end+1 < len(expr) && expr[end+1] == c {
+					value.WriteByte(c)
+					end++
+					continue
+				}
+				break
+			}
+			if end >= len(expr) {
+				return nil, fmt.Errorf("unterminated %c in filter", c)
+			}
+			kind := tokenString
+			if c == '"' {
+				kind = tokenIdent
+			}
+			tokens = append(tokens, filterToken{kind: kind, value: value.String()})
+			i = end + 1
+		case strings.ContainsRune("=<>!", rune(c)):
+			end := i + 1
+			if end < len(expr) && strings.ContainsRune("=>", rune(expr[end])) {
+				end++
+			}
+			tokens = append(tokens, filterToken{kind: tokenOperator, value: expr[i:end]})
+			i = end
+		default:
+			end := i
+			for end < len(expr) && !strings.ContainsRune(" \t\n\r'\"=<>!", rune(expr[end])) {
+				end++
+			}
+			tokens = append(tokens, filterToken{kind: tokenWord, value: expr[i:end]})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens  []filterToken
+	pos     int
+	columns []database.ResultColumn
+}
+
+func (p *filterParser) next() (filterToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return filterToken{}, false
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	return t, true
+}
+
+func (p *filterParser) peek() (filterToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return filterToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *filterParser) parse() (RowPredicate, error) {
+	var conditions []RowPredicate
+	for {
+		condition, err := p.condition()
+		if err != nil {
+			if len(conditions) == 0 && errors.Is(err, errNoCondition) {
+				return nil, errNoCondition
+			}
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+
+		t, ok := p.next()
+		if !ok {
+			break
+		}
+		if !t.keyword("AND") {
+			return nil, fmt.Errorf("expected AND, got %q", t.value)
+		}
+	}
+	return func(row []any) bool {
+		for _, condition := range conditions {
+			if !condition(row) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func (p *filterParser) condition() (RowPredicate, error) {
+	col, ok := p.next()
+	if !ok || (col.kind != tokenWord && col.kind != tokenIdent) {
+		return nil, errNoCondition
+	}
+	op, ok := p.next()
+	if !ok {
+		return nil, errNoCondition
+	}
+
+	negate := false
+	if op.keyword("NOT") {
+		negate = true
+		if op, ok = p.next(); !ok || !(op.keyword("LIKE") || op.keyword("ILIKE")) {
+			return nil, errNoCondition
+		}
+	}
+	isOperator := op.kind == tokenOperator || op.keyword("IS") || op.keyword("LIKE") || op.keyword("ILIKE")
+	if !isOperator {
+		return nil, errNoCondition
+	}
+
+	index := slices.IndexFunc(p.columns, func(c database.ResultColumn) bool { return c.Name == col.value })
+	if index < 0 && col.kind == tokenWord {
+		index = slices.IndexFunc(p.columns, func(c database.ResultColumn) bool { return strings.EqualFold(c.Name, col.value) })
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("unknown column %q", col.value)
+	}
+
+	switch {
+	case op.keyword("IS"):
+		return p.nullCondition(index)
+	case op.keyword("LIKE"), op.keyword("ILIKE"):
+		pattern, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		re, err := likeRegexp(pattern, op.keyword("ILIKE"))
+		if err != nil {
+			return nil, err
+		}
+		column := p.columns[index]
+		return func(row []any) bool {
+			cell := row[index]
+			return cell != nil && re.MatchString(FormatCell(cell, column)) != negate
+		}, nil
+	}
+
+	compare, err := comparisonOperator(op.value)
+	if err != nil {
+		return nil, err
+	}
+	literal, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	column := p.columns[index]
+	return func(row []any) bool {
+		cell := row[index]
+		if cell == nil {
+			return false
+		}
+		c, ok := compareLiteral(cell, column, literal)
+		return ok && compare(c)
+	}, nil
+}
+
+func (p *filterParser) nullCondition(index int) (RowPredicate, error) {
+	t, ok := p.next()
+	notNull := false
+	if ok && t.keyword("NOT") {
+		notNull = true
+		t, ok = p.next()
+	}
+	if !ok || !t.keyword("NULL") {
+		return nil, errors.New("expected NULL after IS")
+	}
+	return func(row []any) bool {
+		return (row[index] == nil) != notNull
+	}, nil
+}
+
+func (p *filterParser) value() (string, error) {
+	t, ok := p.next()
+	if !ok || (t.kind != tokenWord && t.kind != tokenString) {
+		return "", errors.New("expected a value")
+	}
+	// A bare word followed by more words is a value with spaces, e.g. an
+	// unquoted timestamp; stop at the next AND.
+	value := t.value
+	for t.kind == tokenWord {
+		next, ok := p.peek()
+		if !ok || next.kind != tokenWord || next.keyword("AND") {
+			break
+		}
+		p.pos++
+		value += " " + next.value
+	}
+	return value, nil
+}
+
+func comparisonOperator(op string) (func(int) bool, error) {
+	switch op {
+	case "=":
+		return func(c int) bool { return c == 0 }, nil
+	case "!=", "<>":
+		return func(c int) bool { return c != 0 }, nil
+	case "<":
+		return func(c int) bool { return c < 0 }, nil
+	case "<=":
+		return func(c int) bool { return c <= 0 }, nil
+	case ">":
+		return func(c int) bool { return c > 0 }, nil
+	case ">=":
+		return func(c int) bool { return c >= 0 }, nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", op)
+}
+
+// timeLayouts are the layouts tried when comparing a literal to a time
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// compareLiteral compares cell with a literal parsed as the cell's type.
+// ok is false if the literal cannot be read as that type.
+func compareLiteral(cell any, column database.ResultColumn, literal string) (c int, ok bool) {
+	if _, numeric := toFloat(cell); numeric {
+		f, err := strconv.ParseFloat(literal, 64)
+		if err != nil {
+			return 0, false
+		}
+		return CompareValues(cell, f), true
+	}
+	switch cell := cell.(type) {
+	case time.Time:
+		for _, layout := range timeLayouts {
+			if t, err := time.ParseInLocation(layout, literal, cell.Location()); err == nil {
+				return cell.Compare(t), true
+			}
+		}
+		return 0, false
+	case bool:
+		b, err := parseBool(literal)
+		if err != nil {
+			return 0, false
+		}
+		return CompareValues(cell, b), true
+	}
+	return strings.Compare(FormatCell(cell, column), literal), true
+}
+
+// parseBool accepts PostgreSQL's spellings of boolean literals
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "t", "true", "y", "yes", "on", "1":
+		return true, nil
+	case "f", "false", "n", "no", "off", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", s)
+}
+
+// likeRegexp translates a LIKE pattern to a regular expression
+func likeRegexp(pattern string, ignoreCase bool) (*regexp.Regexp, error) {
+	var b strings.Builder
+	if ignoreCase {
+		b.WriteString("(?i)")
+	}
+	b.WriteString("^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			b.WriteString("(?s:.*)")
+		case r == '_':
+			b.WriteString("(?s:.)")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
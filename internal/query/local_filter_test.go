@@ -0,0 +1,70 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLocalFilter(t *testing.T) {
+	columns := columnsNamed("id", "name", "created_at", "active")
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 12, 0, 0, 0, time.UTC) }
+	rows := [][]any{
+		{int64(1), "Alice", day(1), true},
+		{int64(2), "bob", day(2), false},
+		{int64(10), nil, day(3), nil},
+	}
+
+	tests := []struct {
+		filter string
+		want   []int64
+	}{
+		{"", []int64{1, 2, 10}},
+		{"id > 2", []int64{10}},
+		{"id >= 2 AND active = false", []int64{2}},
+		{"name = 'Alice'", []int64{1}},
+		{"name <> 'Alice'", []int64{2}},
+		{"name ILIKE 'a%'", []int64{1}},
+		{"name NOT LIKE 'A%'", []int64{2}},
+		{"name IS NULL", []int64{10}},
+		{`"name" IS NOT NULL`, []int64{1, 2}},
+		{"created_at >= 2024-01-02", []int64{2, 10}},
+		{"created_at < 2024-01-02 13:00", []int64{1, 2}},
+		{"BOB", []int64{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			predicate, err := ParseLocalFilter(tt.filter, columns)
+			require.NoError(t, err)
+			var got []int64
+			for _, row := range rows {
+				if predicate(row) {
+					got = append(got, row[0].(int64))
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, filter := range []string{"missing = 1", "id =", "name = 'open", "id = 1 OR id = 2"} {
+		_, err := ParseLocalFilter(filter, columns)
+		assert.Error(t, err, filter)
+	}
+}
+
+func TestSortRows(t *testing.T) {
+	columns := []string{"n", "s"}
+	rows := [][]any{
+		{int32(10), "b"},
+		{nil, "a"},
+		{int32(9), "c"},
+		{int32(10), "a"},
+	}
+	SortRows(rows, columns, OrderByClauses{{ColumnName: "n", Direction: "ASC"}, {ColumnName: "s", Direction: "DESC"}})
+	assert.Equal(t, [][]any{{int32(9), "c"}, {int32(10), "b"}, {int32(10), "a"}, {nil, "a"}}, rows)
+
+	SortRows(rows, columns, OrderByClauses{{ColumnName: "n", Direction: "DESC"}})
+	assert.Equal(t, []any{nil, "a"}, rows[0])
+}
@@ -1,8 +1,10 @@
 package query
 
+import "github.com/SavingFrame/dbettier/internal/database"
+
 type SQLResultMsg struct {
 	Rows       [][]any
-	Columns    []string // Maybe change, set types for columns, etc
+	Columns    []database.ResultColumn
 	Query      ExecutableQuery
 	DatabaseID string
 }
@@ -15,3 +17,23 @@ type UpdateTableMsg struct {
 type ReapplyTableQueryMsg struct {
 	Query ExecutableQuery
 }
+
+// FetchingRowsMsg is sent when a streamed query starts reading another page
+// from the server. Fetched is the number of rows read so far.
+type FetchingRowsMsg struct {
+	Query   ExecutableQuery
+	Fetched int
+}
+
+// FetchFailedMsg reports that reading more rows of a streamed query failed
+type FetchFailedMsg struct {
+	Query ExecutableQuery
+	Err   error
+}
+
+// SortFilterFailedMsg reports that a result could not be sorted or filtered
+// in memory, e.g. because the filter does not parse
+type SortFilterFailedMsg struct {
+	Query ExecutableQuery
+	Err   error
+}
@@ -23,6 +23,9 @@ func ParseOrderByClauses(s string) (OrderByClauses, error) {
 	var clauses OrderByClauses
 	for orderClause := range strings.SplitSeq(s, ",") {
 		orderClause = strings.TrimSpace(orderClause)
+		if orderClause == "" {
+			continue
+		}
 		parts := strings.SplitN(orderClause, " ", 2)
 		var columnName, direction string
 		switch len(parts) {
@@ -0,0 +1,266 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ParsedParams describes the bind parameters found in a SQL statement.
+type ParsedParams struct {
+	// SQL is the statement with named placeholders rewritten to positional
+	// ones, ready to be sent to the server.
+	SQL string
+	// Labels holds one label per positional parameter: "$1" for positional
+	// placeholders or ":name" for named ones. Labels[i] belongs to $i+1.
+	Labels []string
+}
+
+// ParseParams finds $N and :name placeholders in sql, skipping string
+// literals, quoted identifiers, dollar-quoted bodies, comments, :: casts and
+// the bounds of array slices such as arr[lo:hi].
+// Named placeholders are numbered after the highest positional one and every
+// occurrence of the same name shares a number.
+func ParseParams(sql string) ParsedParams {
+	type placeholder struct {
+		start, end int
+		name       string
+		index      int
+	}
+	var found []placeholder
+	maxPositional := 0
+	// subscripts holds whether each open bracket is a subscript, whose
+	// colons are slice bounds, rather than an ARRAY[...] constructor
+	var subscripts []bool
+
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end + 1
+			}
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			i = skipBlockComment(sql, i)
+		case c == '\'':
+			escapes := i > 0 && (sql[i-1] == 'E' || sql[i-1] == 'e') && (i < 2 || !isIdentChar(sql[i-2]))
+			i = skipQuoted(sql, i, '\'', escapes)
+		case c == '"':
+			i = skipQuoted(sql, i, '"', false)
+		case c == '$':
+			if i+1 < len(sql) && isDigit(sql[i+1]) && (i == 0 || !isIdentChar(sql[i-1])) {
+				end := i + 1
+				for end < len(sql) && isDigit(sql[end]) {
+					end++
+				}
+				n, _ := strconv.Atoi(sql[i+1 : end])
+				if n > 0 {
+					found = append(found, placeholder{start: i, end: end, index: n})
+					maxPositional = max(maxPositional, n)
+				}
+				i = end
+				continue
+			}
+			if tag, ok := dollarQuoteTag(sql, i); ok && (i == 0 || !isIdentChar(sql[i-1])) {
+				closing := strings.Index(sql[i+len(tag):], tag)
+				if closing < 0 {
+					i = len(sql)
+				} else {
+					i += len(tag) + closing + len(tag)
+				}
+				continue
+			}
+			i++
+		case c == '[':
+			subscripts = append(subscripts, isSubscript(sql, i))
+			i++
+		case c == ']':
+			if len(subscripts) > 0 {
+				subscripts = subscripts[:len(subscripts)-1]
+			}
+			i++
+		case c == ':':
+			if i+1 < len(sql) && sql[i+1] == ':' {
+				i += 2
+				continue
+			}
+			inSlice := len(subscripts) > 0 && subscripts[len(subscripts)-1]
+			if i+1 < len(sql) && isIdentStart(sql[i+1]) && !inSlice && (i == 0 || !isIdentChar(sql[i-1]) && sql[i-1] != ':') {
+				end := i + 1
+				for end < len(sql) && isIdentChar(sql[end]) {
+					end++
+				}
+				found = append(found, placeholder{start: i, end: end, name: sql[i+1 : end]})
+				i = end
+				continue
+			}
+			i++
+		default:
+			i++
+		}
+	}
+
+	if len(found) == 0 {
+		return ParsedParams{SQL: sql}
+	}
+
+	labels := make([]string, maxPositional)
+	for n := range labels {
+		labels[n] = fmt.Sprintf("$%d", n+1)
+	}
+	named := make(map[string]int)
+	for i := range found {
+		if found[i].name == "" {
+			continue
+		}
+		index, ok := named[found[i].name]
+		if !ok {
+			labels = append(labels, ":"+found[i].name)
+			index = len(labels)
+			named[found[i].name] = index
+		}
+		found[i].index = index
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, p := range found {
+		b.WriteString(sql[last:p.start])
+		fmt.Fprintf(&b, "$%d", p.index)
+		last = p.end
+	}
+	b.WriteString(sql[last:])
+
+	return ParsedParams{SQL: b.String(), Labels: labels}
+}
+
+// HasParams reports whether any placeholder was found.
+func (p ParsedParams) HasParams() bool {
+	return len(p.Labels) > 0
+}
+
+// isSubscript reports whether the bracket at i subscripts the expression
+// before it, as in arr[1] or (f())[1:2], rather than opening ARRAY[...] or
+// an inner array of one
+func isSubscript(sql string, i int) bool {
+	before := strings.TrimRight(sql[:i], " \t\r\n")
+	if before == "" {
+		return false
+	}
+	switch last := before[len(before)-1]; {
+	case last == ')' || last == ']' || last == '"':
+		return true
+	case !isIdentChar(last):
+		return false
+	}
+	word := len(before)
+	for word > 0 && isIdentChar(before[word-1]) {
+		word--
+	}
+	return !strings.EqualFold(before[word:], "array")
+}
+
+func skipBlockComment(sql string, i int) int {
+	depth := 0
+	for i < len(sql) {
+		switch {
+		case strings.HasPrefix(sql[i:], "/*"):
+			depth++
+			i += 2
+		case strings.HasPrefix(sql[i:], "*/"):
+			depth--
+			i += 2
+			if depth == 0 {
+				return i
+			}
+		default:
+			i++
+		}
+	}
+	return i
+}
+
+func skipQuoted(sql string, i int, quote byte, backslashEscapes bool) int {
+	i++
+	for i < len(sql) {
+		switch sql[i] {
+		case '\\':
+			if backslashEscapes {
+				i += 2
+				continue
+			}
+		case quote:
+			if i+1 < len(sql) && sql[i+1] == quote {
+				i += 2
+				continue
+			}
+			return i + 1
+		}
+		i++
+	}
+	return i
+}
+
+// dollarQuoteTag returns the $tag$ opening at i, if any
+func dollarQuoteTag(sql string, i int) (string, bool) {
+	end := i + 1
+	if end < len(sql) && isIdentStart(sql[end]) {
+		for end < len(sql) && (isIdentStart(sql[end]) || isDigit(sql[end])) {
+			end++
+		}
+	}
+	if end < len(sql) && sql[end] == '$' {
+		return sql[i : end+1], true
+	}
+	return "", false
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '$'
+}
+
+var (
+	paramHistory   = make(map[string]map[string]*string)
+	paramHistoryMu sync.Mutex
+)
+
+// RememberParamValues stores the values last used for a statement, keyed by
+// parameter label. A nil value means NULL.
+func RememberParamValues(sql string, labels []string, values []*string) {
+	paramHistoryMu.Lock()
+	defer paramHistoryMu.Unlock()
+	key := strings.TrimSpace(sql)
+	saved := make(map[string]*string, len(labels))
+	for i, label := range labels {
+		if i < len(values) {
+			saved[label] = values[i]
+		}
+	}
+	paramHistory[key] = saved
+}
+
+// LastParamValues returns the values last used for a statement. ok is false
+// for labels that have never been filled in.
+func LastParamValues(sql string, labels []string) (values []*string, ok []bool) {
+	paramHistoryMu.Lock()
+	defer paramHistoryMu.Unlock()
+	saved := paramHistory[strings.TrimSpace(sql)]
+	values = make([]*string, len(labels))
+	ok = make([]bool, len(labels))
+	for i, label := range labels {
+		values[i], ok[i] = saved[label]
+	}
+	return values, ok
+}
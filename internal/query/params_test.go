@@ -0,0 +1,80 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		sql    string
+		want   string
+		labels []string
+	}{
+		{
+			name: "no params",
+			sql:  "SELECT 1",
+			want: "SELECT 1",
+		},
+		{
+			name:   "positional",
+			sql:    "SELECT * FROM users WHERE id = $1 AND org = $2",
+			want:   "SELECT * FROM users WHERE id = $1 AND org = $2",
+			labels: []string{"$1", "$2"},
+		},
+		{
+			name:   "named share a number",
+			sql:    "SELECT * FROM users WHERE id = :user_id OR parent_id = :user_id",
+			want:   "SELECT * FROM users WHERE id = $1 OR parent_id = $1",
+			labels: []string{":user_id"},
+		},
+		{
+			name:   "named after positional",
+			sql:    "SELECT $1, :name",
+			want:   "SELECT $1, $2",
+			labels: []string{"$1", ":name"},
+		},
+		{
+			name:   "casts are not params",
+			sql:    "SELECT :value::int, now()::date",
+			want:   "SELECT $1::int, now()::date",
+			labels: []string{":value"},
+		},
+		{
+			name:   "array slices are not params",
+			sql:    "SELECT arr[lo:hi], arr[:n], (f())[2:n], ARRAY[:a, :b], ARRAY[[:c]] FROM t WHERE x = a:d",
+			want:   "SELECT arr[lo:hi], arr[:n], (f())[2:n], ARRAY[$1, $2], ARRAY[[$3]] FROM t WHERE x = a:d",
+			labels: []string{":a", ":b", ":c"},
+		},
+		{
+			name: "literals, identifiers and comments are skipped",
+			sql: `SELECT ':a', E'\':b', "c:d", $$ :e $1 $$, $fn$ :f $fn$ -- :g
+/* :h /* $2 */ */ FROM t`,
+			want: `SELECT ':a', E'\':b', "c:d", $$ :e $1 $$, $fn$ :f $fn$ -- :g
+/* :h /* $2 */ */ FROM t`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseParams(tt.sql)
+			assert.Equal(t, tt.want, got.SQL)
+			assert.Equal(t, tt.labels, got.Labels)
+			assert.Equal(t, len(tt.labels) > 0, got.HasParams())
+		})
+	}
+}
+
+func TestParamHistory(t *testing.T) {
+	sql := "SELECT :a, :b"
+	value := "42"
+	RememberParamValues(sql, []string{":a", ":b"}, []*string{&value, nil})
+
+	values, ok := LastParamValues("  "+sql+"\n", []string{":b", ":a", ":c"})
+	assert.Equal(t, []bool{true, true, false}, ok)
+	assert.Nil(t, values[0])
+	assert.Equal(t, "42", *values[1])
+	assert.Nil(t, values[2])
+}
@@ -1,9 +1,18 @@
 package query
 
+import "github.com/SavingFrame/dbettier/internal/database"
+
 type SQLResult struct {
 	Rows          [][]any
-	Columns       []string // Maybe change, set types for columns, etc
-	Total         int      // Total rows available (for pagination)
-	TotalFetched  int      // Total rows fetched in this result
-	CanFetchTotal bool     // Whether more rows can be fetched
+	Columns       []database.ResultColumn
+	Total         int  // Total rows available (for pagination), -1 if unknown
+	TotalFetched  int  // Total rows fetched in this result
+	CanFetchTotal bool // Whether more rows can be fetched
+
+	TotalIsEstimate bool // Total is the planner's estimate rather than a count
+}
+
+// ColumnNames returns the names of the result's columns
+func (r *SQLResult) ColumnNames() []string {
+	return database.ColumnNames(r.Columns)
 }
@@ -0,0 +1,323 @@
+package query
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// TopValues is the number of most frequent values in column statistics
+const TopValues = 5
+
+// StatsSource tells which rows column statistics were computed from
+type StatsSource int
+
+const (
+	// StatsPage statistics are of the rows loaded in the tab
+	StatsPage StatsSource = iota
+	// StatsTable statistics are of every row of the table, or of those
+	// matching a table tab's filter
+	StatsTable
+	// StatsEstimate statistics are the planner's, read from pg_stats
+	StatsEstimate
+)
+
+// ValueCount is a value of a column and the number of rows holding it
+type ValueCount struct {
+	Value string
+	Count int64
+}
+
+// ColumnStats summarizes the values of a column. Counts of a StatsEstimate
+// are the planner's estimates.
+type ColumnStats struct {
+	Source   StatsSource
+	Rows     int64
+	Nulls    int64
+	Distinct int64
+	// Min and Max are the least and greatest values, "" when the type has
+	// no order or every value is NULL
+	Min, Max string
+	// Mean and Median are set for numeric columns with a value
+	HasMean      bool
+	Mean, Median float64
+	// Top are the most frequent values, NULL left out, most frequent first
+	Top []ValueCount
+	// Lengths are the least, 25th percentile, median, 75th percentile and
+	// greatest length in characters of a text column's values, and
+	// MeanLength their mean; set when there is a value
+	HasLengths bool
+	Lengths    [5]int
+	MeanLength float64
+	// AvgWidth is the average width in bytes of the values, of a
+	// StatsEstimate only
+	AvgWidth int
+}
+
+// NullPercent returns the share of NULL values as a percentage
+func (s ColumnStats) NullPercent() float64 {
+	if s.Rows <= 0 {
+		return 0
+	}
+	return float64(s.Nulls) * 100 / float64(s.Rows)
+}
+
+// lengthQuantiles are the fractions of ColumnStats.Lengths
+var lengthQuantiles = [5]float64{0, 0.25, 0.5, 0.75, 1}
+
+// hasMean reports whether the mean and median of a column are computed
+func hasMean(col database.ResultColumn) bool {
+	switch col.TypeOID {
+	case pgtype.Int2OID, pgtype.Int4OID, pgtype.Int8OID,
+		pgtype.Float4OID, pgtype.Float8OID, pgtype.NumericOID:
+		return true
+	}
+	return false
+}
+
+// isOrdered reports whether min and max are defined on a column's type
+func isOrdered(col database.ResultColumn) bool {
+	if hasMean(col) || col.IsText() || len(col.EnumLabels) > 0 {
+		return true
+	}
+	switch col.TypeOID {
+	case pgtype.OIDOID, pgtype.DateOID, pgtype.TimeOID, pgtype.TimestampOID,
+		pgtype.TimestamptzOID, pgtype.IntervalOID, pgtype.InetOID:
+		return true
+	}
+	return false
+}
+
+// PageStats computes the statistics of column col of rows
+func PageStats(rows [][]any, col int, column database.ResultColumn) ColumnStats {
+	s := ColumnStats{Source: StatsPage, Rows: int64(len(rows))}
+	counts := map[string]int64{}
+	var values []any
+	var numbers []float64
+	var lengths []int
+	for _, row := range rows {
+		if col >= len(row) || row[col] == nil {
+			s.Nulls++
+			continue
+		}
+		v := row[col]
+		text := FormatCell(v, column)
+		counts[text]++
+		values = append(values, v)
+		if f, ok := toFloat(v); ok && hasMean(column) && !math.IsNaN(f) {
+			numbers = append(numbers, f)
+		}
+		if column.IsText() {
+			lengths = append(lengths, utf8.RuneCountInString(text))
+		}
+	}
+	s.Distinct = int64(len(counts))
+
+	if len(values) > 0 && isOrdered(column) {
+		compare := CompareValues
+		if len(column.EnumLabels) > 0 {
+			compare = func(a, b any) int {
+				return cmp.Compare(slices.Index(column.EnumLabels, FormatValue(a)), slices.Index(column.EnumLabels, FormatValue(b)))
+			}
+		}
+		s.Min = FormatCell(slices.MinFunc(values, compare), column)
+		s.Max = FormatCell(slices.MaxFunc(values, compare), column)
+	}
+	if len(numbers) > 0 {
+		slices.Sort(numbers)
+		sum := 0.0
+		for _, f := range numbers {
+			sum += f
+		}
+		s.HasMean = true
+		s.Mean = sum / float64(len(numbers))
+		// As percentile_cont(0.5) computes it
+		mid := len(numbers) / 2
+		s.Median = numbers[mid]
+		if len(numbers)%2 == 0 {
+			s.Median = (numbers[mid-1] + numbers[mid]) / 2
+		}
+	}
+	if len(lengths) > 0 {
+		slices.Sort(lengths)
+		sum := 0
+		for _, n := range lengths {
+			sum += n
+		}
+		s.HasLengths = true
+		s.MeanLength = float64(sum) / float64(len(lengths))
+		// As percentile_disc computes them
+		for i, q := range lengthQuantiles {
+			s.Lengths[i] = lengths[max(0, int(math.Ceil(q*float64(len(lengths))))-1)]
+		}
+	}
+
+	for value, count := range counts {
+		s.Top = append(s.Top, ValueCount{Value: value, Count: count})
+	}
+	slices.SortFunc(s.Top, func(a, b ValueCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Value, b.Value))
+	})
+	s.Top = s.Top[:min(len(s.Top), TopValues)]
+	return s
+}
+
+// TableStatsStatements returns the statements computing the statistics of
+// a table column over the rows matching where, or over every row if where
+// is empty: one for the counts and summaries, one for the top values. Read
+// their rows with ParseTableStats.
+func TableStatsStatements(column database.ResultColumn, where string) []database.Statement {
+	c := quoteIdent(column.BaseColumn)
+	from := QualifiedName(column.TableSchema, column.TableName)
+	value := c
+	if !isOrdered(column) {
+		// Types such as json have no equality to group or count by
+		value = c + "::text"
+	}
+
+	aggregates := []string{"count(*)", "count(" + c + ")", "count(DISTINCT " + value + ")"}
+	if isOrdered(column) {
+		aggregates = append(aggregates, "min("+c+")", "max("+c+")")
+	} else {
+		aggregates = append(aggregates, "NULL", "NULL")
+	}
+	if hasMean(column) {
+		aggregates = append(aggregates,
+			"avg("+c+")::float8",
+			"percentile_cont(0.5) WITHIN GROUP (ORDER BY "+c+"::float8)")
+	} else {
+		aggregates = append(aggregates, "NULL::float8", "NULL::float8")
+	}
+	if column.IsText() {
+		aggregates = append(aggregates,
+			"percentile_disc(ARRAY[0, 0.25, 0.5, 0.75, 1]) WITHIN GROUP (ORDER BY length("+c+"))",
+			"avg(length("+c+"))::float8")
+	} else {
+		aggregates = append(aggregates, "NULL::int[]", "NULL::float8")
+	}
+	summary := "SELECT " + strings.Join(aggregates, ", ") + " FROM " + from
+	notNull := c + " IS NOT NULL"
+	if where != "" {
+		summary += " WHERE " + where
+		notNull = fmt.Sprintf("(%s) AND %s", where, notNull)
+	}
+	top := fmt.Sprintf("SELECT %s, count(*) FROM %s WHERE %s GROUP BY 1 ORDER BY 2 DESC, 1 LIMIT %d",
+		value, from, notNull, TopValues)
+	return []database.Statement{{SQL: summary}, {SQL: top}}
+}
+
+// ParseTableStats reads the rows of the TableStatsStatements of column
+func ParseTableStats(results [][][]any, column database.ResultColumn) (ColumnStats, error) {
+	if len(results) != 2 || len(results[0]) != 1 || len(results[0][0]) != 9 {
+		return ColumnStats{}, errors.New("unexpected column statistics")
+	}
+	row := results[0][0]
+	s := ColumnStats{Source: StatsTable}
+	s.Rows = toInt64(row[0])
+	s.Nulls = s.Rows - toInt64(row[1])
+	s.Distinct = toInt64(row[2])
+	if row[3] != nil {
+		s.Min, s.Max = FormatCell(row[3], column), FormatCell(row[4], column)
+	}
+	if row[5] != nil {
+		s.HasMean = true
+		s.Mean, _ = toFloat(row[5])
+		s.Median, _ = toFloat(row[6])
+	}
+	if lengths, ok := row[7].([]any); ok && len(lengths) == len(s.Lengths) {
+		s.HasLengths = true
+		for i, n := range lengths {
+			s.Lengths[i] = int(toInt64(n))
+		}
+		s.MeanLength, _ = toFloat(row[8])
+	}
+	for _, r := range results[1] {
+		s.Top = append(s.Top, ValueCount{Value: FormatCell(r[0], column), Count: toInt64(r[1])})
+	}
+	return s, nil
+}
+
+// EstimateStatsStatement returns the statement reading the planner's
+// statistics of a table column from pg_stats. Read its rows with
+// ParseEstimateStats.
+func EstimateStatsStatement(column database.ResultColumn) database.Statement {
+	return database.Statement{
+		SQL: `SELECT c.reltuples::float8,
+       s.null_frac::float8,
+       s.n_distinct::float8,
+       array_to_json(s.most_common_vals::text::text[])::text,
+       array_to_json(s.most_common_freqs)::text,
+       array_to_json(s.histogram_bounds::text::text[])::text,
+       s.avg_width
+  FROM pg_stats s
+  JOIN pg_namespace n ON n.nspname = s.schemaname
+  JOIN pg_class c ON c.relnamespace = n.oid AND c.relname = s.tablename
+ WHERE s.schemaname = $1
+   AND s.tablename = $2
+   AND s.attname = $3
+ ORDER BY s.inherited DESC
+ LIMIT 1`,
+		Args: []any{column.TableSchema, column.TableName, column.BaseColumn},
+	}
+}
+
+// ParseEstimateStats reads the rows of the EstimateStatsStatement. Values
+// are shown as PostgreSQL prints them.
+func ParseEstimateStats(rows [][]any) (ColumnStats, error) {
+	if len(rows) == 0 {
+		return ColumnStats{}, errors.New("the table has no statistics yet; run ANALYZE on it")
+	}
+	row := rows[0]
+	if len(row) != 7 {
+		return ColumnStats{}, errors.New("unexpected column statistics")
+	}
+	total, _ := toFloat(row[0])
+	total = max(0, total)
+	nullFrac, _ := toFloat(row[1])
+	distinct, _ := toFloat(row[2])
+	s := ColumnStats{
+		Source: StatsEstimate,
+		Rows:   int64(math.Round(total)),
+		Nulls:  int64(math.Round(nullFrac * total)),
+		// A negative n_distinct is the number of distinct values divided by
+		// the number of rows, for columns expected to grow with the table
+		Distinct: int64(math.Round(distinct)),
+		AvgWidth: int(toInt64(row[6])),
+	}
+	if distinct < 0 {
+		s.Distinct = int64(math.Round(-distinct * total))
+	}
+
+	var values, bounds []string
+	var freqs []float64
+	for i, dst := range []any{&values, &freqs, &bounds} {
+		if text, ok := row[3+i].(string); ok {
+			if err := json.Unmarshal([]byte(text), dst); err != nil {
+				return ColumnStats{}, err
+			}
+		}
+	}
+	for i, v := range values {
+		if i < len(freqs) && i < TopValues {
+			s.Top = append(s.Top, ValueCount{Value: v, Count: int64(math.Round(freqs[i] * total))})
+		}
+	}
+	if len(bounds) > 0 {
+		s.Min, s.Max = bounds[0], bounds[len(bounds)-1]
+	}
+	return s, nil
+}
+
+// toInt64 converts an integer or numeric value to int64, 0 if it is not one
+func toInt64(v any) int64 {
+	f, _ := toFloat(v)
+	return int64(f)
+}
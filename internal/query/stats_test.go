@@ -0,0 +1,83 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageStats(t *testing.T) {
+	rows := [][]any{
+		{int32(3), "ann"},
+		{int32(1), "bob"},
+		{nil, "ann"},
+		{int32(4), nil},
+		{int32(1), "chloé"},
+	}
+	numbers := PageStats(rows, 0, database.ResultColumn{TypeOID: pgtype.Int4OID})
+	assert.Equal(t, ColumnStats{
+		Rows: 5, Nulls: 1, Distinct: 3,
+		Min: "1", Max: "4",
+		HasMean: true, Mean: 2.25, Median: 2,
+		Top: []ValueCount{{"1", 2}, {"3", 1}, {"4", 1}},
+	}, numbers)
+
+	text := PageStats(rows, 1, database.ResultColumn{TypeOID: pgtype.TextOID})
+	assert.Equal(t, ColumnStats{
+		Rows: 5, Nulls: 1, Distinct: 3,
+		Min: "ann", Max: "chloé",
+		Top:        []ValueCount{{"ann", 2}, {"bob", 1}, {"chloé", 1}},
+		HasLengths: true, Lengths: [5]int{3, 3, 3, 3, 5}, MeanLength: 3.5,
+	}, text)
+	assert.Equal(t, 20.0, text.NullPercent())
+
+	// Enums order by their labels, and unordered types have no min or max
+	enum := PageStats([][]any{{"sad"}, {"ok"}, {"happy"}}, 0, database.ResultColumn{EnumLabels: []string{"sad", "ok", "happy"}})
+	assert.Equal(t, "sad", enum.Min)
+	assert.Equal(t, "happy", enum.Max)
+	json := PageStats([][]any{{"{}"}}, 0, database.ResultColumn{TypeOID: pgtype.JSONOID})
+	assert.Equal(t, "", json.Min)
+}
+
+func TestTableStats(t *testing.T) {
+	column := database.ResultColumn{TypeOID: pgtype.TextOID, TableSchema: "public", TableName: "users", BaseColumn: "name"}
+	statements := TableStatsStatements(column, "age > 30")
+	require.Len(t, statements, 2)
+	assert.Equal(t, `SELECT count(*), count("name"), count(DISTINCT "name"), min("name"), max("name"), NULL::float8, NULL::float8, `+
+		`percentile_disc(ARRAY[0, 0.25, 0.5, 0.75, 1]) WITHIN GROUP (ORDER BY length("name")), avg(length("name"))::float8 `+
+		`FROM "public"."users" WHERE age > 30`, statements[0].SQL)
+	assert.Equal(t, `SELECT "name", count(*) FROM "public"."users" WHERE (age > 30) AND "name" IS NOT NULL GROUP BY 1 ORDER BY 2 DESC, 1 LIMIT 5`, statements[1].SQL)
+
+	jsonColumn := database.ResultColumn{TypeOID: pgtype.JSONOID, TableSchema: "public", TableName: "users", BaseColumn: "prefs"}
+	assert.Equal(t, `SELECT "prefs"::text, count(*) FROM "public"."users" WHERE "prefs" IS NOT NULL GROUP BY 1 ORDER BY 2 DESC, 1 LIMIT 5`,
+		TableStatsStatements(jsonColumn, "")[1].SQL)
+
+	s, err := ParseTableStats([][][]any{
+		{{int64(10), int64(8), int64(3), "ann", "bob", nil, nil, []any{int32(3), int32(3), int32(3), int32(3), int32(5)}, 3.25}},
+		{{"ann", int64(6)}, {"bob", int64(2)}},
+	}, column)
+	require.NoError(t, err)
+	assert.Equal(t, ColumnStats{
+		Source: StatsTable, Rows: 10, Nulls: 2, Distinct: 3,
+		Min: "ann", Max: "bob",
+		Top:        []ValueCount{{"ann", 6}, {"bob", 2}},
+		HasLengths: true, Lengths: [5]int{3, 3, 3, 3, 5}, MeanLength: 3.25,
+	}, s)
+}
+
+func TestEstimateStats(t *testing.T) {
+	s, err := ParseEstimateStats([][]any{{1000.0, 0.1, -0.5, `["ann","bob"]`, `[0.25,0.05]`, `["carl","zoe"]`, int32(6)}})
+	require.NoError(t, err)
+	assert.Equal(t, ColumnStats{
+		Source: StatsEstimate, Rows: 1000, Nulls: 100, Distinct: 500,
+		Min: "carl", Max: "zoe",
+		Top:      []ValueCount{{"ann", 250}, {"bob", 50}},
+		AvgWidth: 6,
+	}, s)
+
+	_, err = ParseEstimateStats(nil)
+	assert.Error(t, err)
+}
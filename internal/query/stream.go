@@ -0,0 +1,52 @@
+package query
+
+import (
+	"context"
+	"strings"
+)
+
+// PageSize is the number of rows shown per page of an ad-hoc query result,
+// and the number of rows read from the server per fetch when streaming.
+const PageSize = 500
+
+// MaxBufferedRows caps how many rows of a streamed result are kept in
+// memory. Once exceeded, the oldest pages are dropped.
+var MaxBufferedRows = 50_000
+
+// RowFetcher reads further rows of a result that is streamed from the
+// server. database.Cursor implements it.
+type RowFetcher interface {
+	Fetch(ctx context.Context, n int) ([][]any, error)
+	Exhausted() bool
+	Close(ctx context.Context) error
+}
+
+// IsStreamable reports whether sql is a statement that can be read through
+// a cursor: a SELECT, WITH, VALUES or TABLE query.
+func IsStreamable(sql string) bool {
+	for i := 0; i < len(sql); {
+		switch c := sql[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '(':
+			i++
+		case strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				return false
+			}
+			i += end + 1
+		case strings.HasPrefix(sql[i:], "/*"):
+			i = skipBlockComment(sql, i)
+		default:
+			end := i
+			for end < len(sql) && isIdentChar(sql[end]) {
+				end++
+			}
+			switch strings.ToUpper(sql[i:end]) {
+			case "SELECT", "WITH", "VALUES", "TABLE":
+				return true
+			}
+			return false
+		}
+	}
+	return false
+}
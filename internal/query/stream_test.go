@@ -0,0 +1,33 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsStreamable(t *testing.T) {
+	streamable := []string{
+		"SELECT 1",
+		"  select * from t",
+		"-- comment\nWITH x AS (SELECT 1) SELECT * FROM x",
+		"/* hint */ (SELECT 1) UNION (SELECT 2)",
+		"VALUES (1), (2)",
+		"TABLE users",
+	}
+	for _, sql := range streamable {
+		assert.True(t, IsStreamable(sql), sql)
+	}
+
+	notStreamable := []string{
+		"",
+		"-- only a comment",
+		"INSERT INTO t VALUES (1)",
+		"UPDATE t SET a = 1",
+		"EXPLAIN SELECT 1",
+		"SELECTED",
+	}
+	for _, sql := range notStreamable {
+		assert.False(t, IsStreamable(sql), sql)
+	}
+}
@@ -3,6 +3,8 @@ package query
 import (
 	"fmt"
 	"log"
+	"slices"
+	"strings"
 
 	tea "charm.land/bubbletea/v2"
 )
@@ -14,37 +16,259 @@ type TableQuery struct {
 	Offset      int
 	SQLResult   *SQLResult
 	WhereClause string
+
+	// ColumnDefaults maps column names to their default expressions, for
+	// pre-filling new rows
+	ColumnDefaults map[string]string
+
+	// KeyColumns uniquely identify a row. When set, pages are read by
+	// keyset (rows after the last one shown) instead of OFFSET.
+	KeyColumns []string
+	// pageStarts holds, for every page read by keyset, the ordering values
+	// of the last row on the page before it. Pages reached by JumpToPage are
+	// read with OFFSET and keyset pagination continues from there.
+	pageStarts [][]any
+
+	// EstimatedTotal is the planner's row count estimate, -1 if unknown
+	EstimatedTotal int64
+	// ExactTotal is the result of count(*) for the current WhereClause,
+	// -1 until counted
+	ExactTotal int64
 }
 
 func NewTableQuery(baseQuery string, limit int) *TableQuery {
 	return &TableQuery{
-		BaseQuery: baseQuery,
-		Limit:     limit + 1,
-		Offset:    0,
+		BaseQuery:      baseQuery,
+		Limit:          limit + 1,
+		Offset:         0,
+		EstimatedTotal: -1,
+		ExactTotal:     -1,
 	}
 }
 
+// SetKeyColumns enables keyset pagination by the given unique key
+func (q *TableQuery) SetKeyColumns(columns []string) {
+	q.KeyColumns = columns
+	q.resetPages()
+}
+
+// UsesKeyset reports whether pages are read by keyset rather than OFFSET
+func (q *TableQuery) UsesKeyset() bool {
+	return len(q.KeyColumns) > 0
+}
+
 func (q *TableQuery) Compile() string {
+	sql, _ := q.compile()
+	return sql
+}
+
+// Args returns the bind parameter values for the compiled query
+func (q *TableQuery) Args() []any {
+	_, args := q.compile()
+	return args
+}
+
+func (q *TableQuery) compile() (string, []any) {
 	// TODO: Rewrite to strings.Builder for efficiency
-	fullQuery := q.BaseQuery
-	if fullQuery[len(fullQuery)-1] == ';' {
-		fullQuery = fullQuery[:len(fullQuery)-1]
-	}
+	fullQuery := strings.TrimSuffix(q.BaseQuery, ";")
+
+	var conditions []string
 	if q.WhereClause != "" {
-		fullQuery += " WHERE " + q.WhereClause
+		conditions = append(conditions, q.WhereClause)
+	}
+	var args []any
+	if q.UsesKeyset() && len(q.pageStarts) > 0 {
+		var predicate string
+		predicate, args = keysetPredicate(q.ordering(), q.KeyColumns, q.pageStarts[len(q.pageStarts)-1], len(q.SortOrders) == 0)
+		conditions = append(conditions, predicate)
+	}
+	switch len(conditions) {
+	case 1:
+		fullQuery += " WHERE " + conditions[0]
+	case 2:
+		fullQuery += fmt.Sprintf(" WHERE (%s) AND (%s)", conditions[0], conditions[1])
 	}
-	if len(q.SortOrders) > 0 {
 
-		orderByClause := " ORDER BY " + q.SortOrders.String()
+	if ordering := q.ordering(); len(ordering) > 0 {
+		orderByClause := " ORDER BY " + ordering.String()
 		fullQuery = fullQuery + orderByClause
 	}
 	if q.Limit > 0 {
 		fullQuery = fmt.Sprintf("%s LIMIT %d", fullQuery, q.Limit)
 	}
-	if q.Offset > 0 {
+	if q.Offset > 0 && (!q.UsesKeyset() || len(q.pageStarts) == 0) {
 		fullQuery = fmt.Sprintf("%s OFFSET %d", fullQuery, q.Offset)
 	}
-	return fullQuery
+	return fullQuery, args
+}
+
+// UnpagedQuery returns a statement reading every row matching WhereClause,
+// in the order pages are shown
+func (q *TableQuery) UnpagedQuery() string {
+	sql := strings.TrimSuffix(q.BaseQuery, ";")
+	if q.WhereClause != "" {
+		sql += " WHERE " + q.WhereClause
+	}
+	if ordering := q.ordering(); len(ordering) > 0 {
+		sql += " ORDER BY " + ordering.String()
+	}
+	return sql
+}
+
+// CountQuery returns a statement counting the rows matching WhereClause
+func (q *TableQuery) CountQuery() string {
+	baseQuery := strings.TrimSuffix(q.BaseQuery, ";")
+	if q.WhereClause != "" {
+		baseQuery += " WHERE " + q.WhereClause
+	}
+	return fmt.Sprintf("SELECT count(*) FROM (%s) AS counted", baseQuery)
+}
+
+// SetExactTotal records the result of CountQuery
+func (q *TableQuery) SetExactTotal(total int64) {
+	q.ExactTotal = total
+	if q.SQLResult != nil {
+		q.SQLResult.Total = int(total)
+		q.SQLResult.TotalIsEstimate = false
+	}
+}
+
+// total returns the best known row count and whether it is an estimate.
+// The table estimate says nothing about filtered results, so it is only
+// used without a WhereClause.
+func (q *TableQuery) total() (total int, estimate bool) {
+	switch {
+	case q.ExactTotal >= 0:
+		return int(q.ExactTotal), false
+	case q.EstimatedTotal >= 0 && q.WhereClause == "":
+		return int(q.EstimatedTotal), true
+	}
+	return -1, false
+}
+
+// JumpToPage moves to the given 1-based page. The page is read with OFFSET
+// and later pages continue by keyset when possible.
+func (q *TableQuery) JumpToPage(page int) tea.Cmd {
+	if page < 1 {
+		return nil
+	}
+	q.pageStarts = nil
+	q.Offset = (page - 1) * (q.Limit - 1)
+	return func() tea.Msg {
+		return ReapplyTableQueryMsg{
+			Query: q,
+		}
+	}
+}
+
+// PageCount returns the number of pages given the best known total, or -1
+// if the total is unknown
+func (q *TableQuery) PageCount() int {
+	total, _ := q.total()
+	if total < 0 {
+		return -1
+	}
+	return max(1, (total+q.Limit-2)/(q.Limit-1))
+}
+
+// ordering returns the user's sort orders followed by any key column they
+// don't already cover, so the order is total and keyset pages are stable.
+func (q *TableQuery) ordering() OrderByClauses {
+	ordering := slices.Clone(q.SortOrders)
+	for _, col := range q.KeyColumns {
+		if !slices.ContainsFunc(ordering, func(o OrderByClause) bool { return o.ColumnName == col }) {
+			ordering = append(ordering, OrderByClause{ColumnName: col, Direction: "ASC"})
+		}
+	}
+	return ordering
+}
+
+// keysetPredicate builds the condition selecting rows that sort after last.
+// NULLs sort last in ascending and first in descending order, as they do in
+// PostgreSQL by default, so they are matched explicitly rather than compared.
+// Key columns are NOT NULL and need no such care.
+func keysetPredicate(ordering OrderByClauses, keyColumns []string, last []any, plainKey bool) (string, []any) {
+	var args []any
+	placeholder := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if plainKey {
+		// Key columns are NOT NULL and all ascending: a row comparison can
+		// use the index directly.
+		cols := make([]string, len(ordering))
+		values := make([]string, len(ordering))
+		for i, o := range ordering {
+			cols[i] = quoteIdent(o.ColumnName)
+			values[i] = placeholder(last[i])
+		}
+		return fmt.Sprintf("(%s) > (%s)", strings.Join(cols, ", "), strings.Join(values, ", ")), args
+	}
+
+	var alternatives []string
+	for i, o := range ordering {
+		var terms []string
+		for j := range i {
+			terms = append(terms, equalTerm(quoteIdent(ordering[j].ColumnName), last[j], placeholder))
+		}
+		notNull := slices.Contains(keyColumns, o.ColumnName)
+		after := afterTerm(quoteIdent(o.ColumnName), o.Direction == "DESC", notNull, last[i], placeholder)
+		if after == "" {
+			continue
+		}
+		terms = append(terms, after)
+		alternatives = append(alternatives, "("+strings.Join(terms, " AND ")+")")
+	}
+	if len(alternatives) == 0 {
+		return "FALSE", args
+	}
+	return strings.Join(alternatives, " OR "), args
+}
+
+// MatchClause returns a WHERE clause selecting the rows whose columns hold
+// values, as literals so that it can be shown and edited as a filter
+func MatchClause(columns []string, values []any) string {
+	terms := make([]string, len(columns))
+	for i, col := range columns {
+		terms[i] = equalTerm(quoteIdent(col), values[i], literal)
+	}
+	return strings.Join(terms, " AND ")
+}
+
+func equalTerm(col string, v any, placeholder func(any) string) string {
+	if v == nil {
+		return col + " IS NULL"
+	}
+	return col + " = " + placeholder(v)
+}
+
+// afterTerm returns the condition for col sorting strictly after v, or ""
+// if nothing can.
+func afterTerm(col string, desc, notNull bool, v any, placeholder func(any) string) string {
+	switch {
+	case notNull && desc:
+		return col + " < " + placeholder(v)
+	case notNull:
+		return col + " > " + placeholder(v)
+	case v == nil && desc:
+		return col + " IS NOT NULL"
+	case v == nil:
+		return ""
+	case desc:
+		return col + " < " + placeholder(v)
+	default:
+		return fmt.Sprintf("(%s > %s OR %s IS NULL)", col, placeholder(v), col)
+	}
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// QualifiedName returns the quoted, schema-qualified name of a table
+func QualifiedName(schema, table string) string {
+	return quoteIdent(schema) + "." + quoteIdent(table)
 }
 
 func (q *TableQuery) GetSortOrders() OrderByClauses {
@@ -53,11 +277,14 @@ func (q *TableQuery) GetSortOrders() OrderByClauses {
 
 func (q *TableQuery) SetSQLResult(msg *SQLResultMsg) *SQLResult {
 	canFetchTotal := len(msg.Rows) > 500
+	total, estimate := q.total()
 	q.SQLResult = &SQLResult{
-		Rows:          msg.Rows,
-		Columns:       msg.Columns,
-		TotalFetched:  len(msg.Rows) + q.Offset,
-		CanFetchTotal: canFetchTotal,
+		Rows:            msg.Rows,
+		Columns:         msg.Columns,
+		Total:           total,
+		TotalIsEstimate: estimate,
+		TotalFetched:    len(msg.Rows) + q.Offset,
+		CanFetchTotal:   canFetchTotal,
 	}
 	return q.SQLResult
 }
@@ -75,12 +302,22 @@ func (q *TableQuery) HasPreviousPage() bool {
 	return q.Offset > 0
 }
 
-// NextPage increments the offset and returns a message to reapply the query
+// NextPage moves past the current page and returns a message to reapply the query
 func (q *TableQuery) NextPage() tea.Cmd {
 	if !q.HasNextPage() {
 		log.Println("No next page available")
 		return nil
 	}
+	if q.UsesKeyset() {
+		start, ok := q.lastRowOrdering()
+		if !ok {
+			log.Println("Ordering columns missing from result, paginating with OFFSET")
+			q.KeyColumns = nil
+			q.pageStarts = nil
+		} else {
+			q.pageStarts = append(q.pageStarts, start)
+		}
+	}
 	q.Offset += q.Limit - 1
 	return func() tea.Msg {
 		return ReapplyTableQueryMsg{
@@ -94,7 +331,10 @@ func (q *TableQuery) PreviousPage() tea.Cmd {
 		log.Println("No previous page available")
 		return nil
 	}
-	q.Offset -= max(0, q.Limit-1)
+	if len(q.pageStarts) > 0 {
+		q.pageStarts = q.pageStarts[:len(q.pageStarts)-1]
+	}
+	q.Offset = max(0, q.Offset-(q.Limit-1))
 	return func() tea.Msg {
 		return ReapplyTableQueryMsg{
 			Query: q,
@@ -102,6 +342,28 @@ func (q *TableQuery) PreviousPage() tea.Cmd {
 	}
 }
 
+// lastRowOrdering returns the ordering values of the last row on the page
+func (q *TableQuery) lastRowOrdering() ([]any, bool) {
+	ordering := q.ordering()
+	row := q.SQLResult.Rows[q.Limit-2]
+	columns := q.SQLResult.ColumnNames()
+	values := make([]any, len(ordering))
+	for i, o := range ordering {
+		idx := slices.Index(columns, o.ColumnName)
+		if idx < 0 || idx >= len(row) {
+			return nil, false
+		}
+		values[i] = row[idx]
+	}
+	return values, true
+}
+
+// resetPages goes back to the first page
+func (q *TableQuery) resetPages() {
+	q.Offset = 0
+	q.pageStarts = nil
+}
+
 func (q *TableQuery) Rows() [][]any {
 	if len(q.SQLResult.Rows) > q.Limit-1 {
 		log.Printf("Returning rows for current page: limit=%d", q.Limit)
@@ -115,7 +377,11 @@ func (q *TableQuery) PageOffset() int {
 }
 
 func (q *TableQuery) SetWhereClause(whereClause string) tea.Cmd {
+	if whereClause != q.WhereClause {
+		q.ExactTotal = -1
+	}
 	q.WhereClause = whereClause
+	q.resetPages()
 	return func() tea.Msg {
 		return ReapplyTableQueryMsg{
 			Query: q,
@@ -123,8 +389,15 @@ func (q *TableQuery) SetWhereClause(whereClause string) tea.Cmd {
 	}
 }
 
+// SortFilterMode returns SortFilterServer: table queries are always sorted
+// and filtered by the server
+func (q *TableQuery) SortFilterMode() SortFilterMode {
+	return SortFilterServer
+}
+
 func (q *TableQuery) HandleSortChange(orderBy OrderByClauses) tea.Cmd {
 	q.SortOrders = orderBy
+	q.resetPages()
 	return func() tea.Msg {
 		return ReapplyTableQueryMsg{
 			Query: q,
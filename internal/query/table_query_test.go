@@ -0,0 +1,155 @@
+package query
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pageOf returns a result with a full page plus the look-ahead row
+func pageOf(q *TableQuery, columns []string, last []any) *SQLResultMsg {
+	rows := make([][]any, q.Limit)
+	for i := range rows {
+		rows[i] = make([]any, len(columns))
+	}
+	rows[q.Limit-2] = last
+	return &SQLResultMsg{Columns: columnsNamed(columns...), Rows: rows}
+}
+
+// columnsNamed returns untyped result columns with the given names
+func columnsNamed(names ...string) []database.ResultColumn {
+	columns := make([]database.ResultColumn, len(names))
+	for i, name := range names {
+		columns[i].Name = name
+	}
+	return columns
+}
+
+func TestTableQueryOffsetPagination(t *testing.T) {
+	q := NewTableQuery(`SELECT * FROM "events";`, 500)
+	assert.Equal(t, `SELECT * FROM "events" LIMIT 501`, q.Compile())
+
+	q.SetSQLResult(pageOf(q, []string{"id"}, []any{int32(7)}))
+	q.NextPage()
+	assert.Equal(t, `SELECT * FROM "events" LIMIT 501 OFFSET 500`, q.Compile())
+	assert.Empty(t, q.Args())
+}
+
+func TestTableQueryKeysetPagination(t *testing.T) {
+	q := NewTableQuery(`SELECT * FROM "events"`, 500)
+	q.SetKeyColumns([]string{"tenant", "id"})
+	q.WhereClause = "kind = 'click'"
+	assert.Equal(t, `SELECT * FROM "events" WHERE kind = 'click' ORDER BY "tenant" ASC, "id" ASC LIMIT 501`, q.Compile())
+
+	columns := []string{"id", "tenant", "kind"}
+	q.SetSQLResult(pageOf(q, columns, []any{int32(7), int32(2), "click"}))
+	q.NextPage()
+	assert.Equal(t,
+		`SELECT * FROM "events" WHERE (kind = 'click') AND (("tenant", "id") > ($1, $2)) ORDER BY "tenant" ASC, "id" ASC LIMIT 501`,
+		q.Compile())
+	assert.Equal(t, []any{int32(2), int32(7)}, q.Args())
+	assert.Equal(t, 500, q.PageOffset())
+
+	q.PreviousPage()
+	assert.Equal(t, `SELECT * FROM "events" WHERE kind = 'click' ORDER BY "tenant" ASC, "id" ASC LIMIT 501`, q.Compile())
+	assert.Equal(t, 0, q.PageOffset())
+}
+
+func TestTableQueryKeysetWithSortOrders(t *testing.T) {
+	q := NewTableQuery(`SELECT * FROM "events"`, 500)
+	q.SetKeyColumns([]string{"id"})
+	q.HandleSortChange(OrderByClauses{{ColumnName: "created_at", Direction: "DESC"}})
+
+	columns := []string{"id", "created_at"}
+	q.SetSQLResult(pageOf(q, columns, []any{int32(7), "2024-01-01"}))
+	q.NextPage()
+	assert.Equal(t,
+		`SELECT * FROM "events" WHERE ("created_at" < $1) OR ("created_at" = $2 AND "id" > $3) ORDER BY "created_at" DESC, "id" ASC LIMIT 501`,
+		q.Compile())
+	assert.Equal(t, []any{"2024-01-01", "2024-01-01", int32(7)}, q.Args())
+
+	// A NULL in a descending column: only the remaining NULLs tie with it
+	q.SetSQLResult(pageOf(q, columns, []any{int32(9), nil}))
+	q.NextPage()
+	assert.Equal(t,
+		`SELECT * FROM "events" WHERE ("created_at" IS NOT NULL) OR ("created_at" IS NULL AND "id" > $1) ORDER BY "created_at" DESC, "id" ASC LIMIT 501`,
+		q.Compile())
+	assert.Equal(t, []any{int32(9)}, q.Args())
+}
+
+func TestTableQueryJumpToPage(t *testing.T) {
+	q := NewTableQuery(`SELECT * FROM "events"`, 500)
+	q.SetKeyColumns([]string{"id"})
+	q.EstimatedTotal = 1200
+	assert.Equal(t, 3, q.PageCount())
+
+	q.JumpToPage(3)
+	assert.Equal(t, `SELECT * FROM "events" ORDER BY "id" ASC LIMIT 501 OFFSET 1000`, q.Compile())
+
+	// Pages after a jump continue by keyset, and going back returns to OFFSET
+	q.SetSQLResult(pageOf(q, []string{"id"}, []any{int32(1500)}))
+	q.NextPage()
+	assert.Equal(t, `SELECT * FROM "events" WHERE ("id") > ($1) ORDER BY "id" ASC LIMIT 501`, q.Compile())
+	q.PreviousPage()
+	assert.Equal(t, `SELECT * FROM "events" ORDER BY "id" ASC LIMIT 501 OFFSET 1000`, q.Compile())
+	q.PreviousPage()
+	assert.Equal(t, `SELECT * FROM "events" ORDER BY "id" ASC LIMIT 501 OFFSET 500`, q.Compile())
+}
+
+func TestTableQueryTotals(t *testing.T) {
+	q := NewTableQuery(`SELECT * FROM "events";`, 500)
+	assert.Equal(t, -1, q.PageCount())
+	assert.Equal(t, `SELECT count(*) FROM (SELECT * FROM "events") AS counted`, q.CountQuery())
+
+	q.EstimatedTotal = 1_000_000
+	result := q.SetSQLResult(&SQLResultMsg{Columns: columnsNamed("id")})
+	assert.Equal(t, 1_000_000, result.Total)
+	assert.True(t, result.TotalIsEstimate)
+
+	// The estimate covers the whole table, not a filtered result
+	q.SetWhereClause("kind = 'click'")
+	assert.Equal(t, `SELECT count(*) FROM (SELECT * FROM "events" WHERE kind = 'click') AS counted`, q.CountQuery())
+	result = q.SetSQLResult(&SQLResultMsg{Columns: columnsNamed("id")})
+	assert.Equal(t, -1, result.Total)
+
+	q.SetExactTotal(42)
+	assert.Equal(t, 42, q.SQLResult.Total)
+	assert.False(t, q.SQLResult.TotalIsEstimate)
+	assert.Equal(t, 1, q.PageCount())
+
+	q.SetWhereClause("kind = 'view'")
+	assert.Equal(t, int64(-1), q.ExactTotal)
+}
+
+func TestTableQueryUnpagedQuery(t *testing.T) {
+	q := NewTableQuery(`SELECT * FROM "events";`, 100)
+	q.SetKeyColumns([]string{"id"})
+	q.SetSQLResult(pageOf(q, []string{"id", "kind"}, []any{int32(100), "a"}))
+	q.NextPage()
+	q.SetWhereClause("kind = 'click'")
+	q.SortOrders = OrderByClauses{{ColumnName: "kind", Direction: "DESC"}}
+	assert.Equal(t, `SELECT * FROM "events" WHERE kind = 'click' ORDER BY "kind" DESC, "id" ASC`, q.UnpagedQuery())
+}
+
+func TestMatchClause(t *testing.T) {
+	assert.Equal(t, `"tenant" = 7 AND "code" = 'o''k'`, MatchClause([]string{"tenant", "code"}, []any{int32(7), "o'k"}))
+	assert.Equal(t, `"parent" IS NULL`, MatchClause([]string{"parent"}, []any{nil}))
+
+	// Literals ignore the display options
+	saved := Display
+	defer func() { Display = saved }()
+	Display.GroupDigits = true
+	Display.TimestampLayout = "02.01.2006 15:04"
+	Display.Location = time.UTC
+	at := time.Date(2024, 1, 1, 17, 30, 0, 0, time.FixedZone("IST", 5*60*60+30*60))
+	assert.Equal(t, `"id" = 48213 AND "price" = 1234.5 AND "at" = '2024-01-01 17:30:00+05:30' AND "score" = 'NaN'`,
+		MatchClause([]string{"id", "price", "at", "score"}, []any{int64(48213),
+			pgtype.Numeric{Int: big.NewInt(12345), Exp: -1, Valid: true}, at, math.NaN()}))
+	assert.Equal(t, "48213", keyString([]any{int64(48213)}))
+}
@@ -4,10 +4,16 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/SavingFrame/dbettier/internal/components"
+	"github.com/SavingFrame/dbettier/internal/components/tableview"
 	"github.com/SavingFrame/dbettier/internal/database"
+	"github.com/SavingFrame/dbettier/internal/query"
+	"github.com/SavingFrame/dbettier/pkgs/clipboard"
 	zone "github.com/lrstanley/bubblezone/v2"
 )
 
@@ -25,9 +31,86 @@ func setupDebugLog() func() {
 	return func() {}
 }
 
+// applyRowLimit lets DBETTIER_MAX_ROWS override how many rows of a streamed
+// query result are kept in memory.
+func applyRowLimit() {
+	v := os.Getenv("DBETTIER_MAX_ROWS")
+	if v == "" {
+		return
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < query.PageSize {
+		fmt.Printf("Warning: ignoring DBETTIER_MAX_ROWS=%q, expected a number of at least %d\n", v, query.PageSize)
+		return
+	}
+	query.MaxBufferedRows = n
+}
+
+// applyDisplayOptions reads how values are shown from the environment:
+// DBETTIER_TIMEZONE for timestamptz values, DBETTIER_TIMESTAMP_FORMAT as a Go
+// time layout, and DBETTIER_NUMERIC_GROUPING to separate thousands.
+func applyDisplayOptions() {
+	if v := os.Getenv("DBETTIER_TIMEZONE"); v != "" {
+		loc, err := time.LoadLocation(v)
+		if err != nil {
+			fmt.Printf("Warning: ignoring DBETTIER_TIMEZONE=%q: %v\n", v, err)
+		} else {
+			query.Display.Location = loc
+		}
+	}
+	if v := os.Getenv("DBETTIER_TIMESTAMP_FORMAT"); v != "" {
+		query.Display.TimestampLayout = v
+	}
+	if v := os.Getenv("DBETTIER_NUMERIC_GROUPING"); v != "" {
+		group, err := strconv.ParseBool(v)
+		if err != nil {
+			fmt.Printf("Warning: ignoring DBETTIER_NUMERIC_GROUPING=%q, expected true or false\n", v)
+		} else {
+			query.Display.GroupDigits = group
+		}
+	}
+}
+
+// applyLabelColumns reads DBETTIER_LABEL_COLUMNS, a comma-separated list of
+// table=column pairs naming the column shown as the label of a table's rows
+// in foreign key lookups, e.g. "customers=company,billing.invoices=number".
+func applyLabelColumns() {
+	v := os.Getenv("DBETTIER_LABEL_COLUMNS")
+	if v == "" {
+		return
+	}
+	for _, pair := range strings.Split(v, ",") {
+		table, column, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || table == "" || column == "" {
+			fmt.Printf("Warning: ignoring %q in DBETTIER_LABEL_COLUMNS, expected table=column\n", pair)
+			continue
+		}
+		database.LabelColumns[table] = column
+	}
+}
+
+// applyClipboard reads DBETTIER_CLIPBOARD, how copied text reaches the
+// clipboard: auto, osc52 or native.
+func applyClipboard() {
+	v := os.Getenv("DBETTIER_CLIPBOARD")
+	if v == "" {
+		return
+	}
+	method, err := clipboard.ParseMethod(v)
+	if err != nil {
+		fmt.Printf("Warning: ignoring DBETTIER_CLIPBOARD: %v\n", err)
+		return
+	}
+	clipboard.Preferred = method
+}
+
 func main() {
 	cleanup := setupDebugLog()
 	defer cleanup()
+	applyRowLimit()
+	applyDisplayOptions()
+	applyLabelColumns()
+	applyClipboard()
 	zone.NewGlobal()
 
 	// Create database registry and load connections
@@ -36,6 +119,10 @@ func main() {
 		fmt.Println("Warning: could not load connections:", err)
 	}
 
+	if err := tableview.LoadLayouts(".layouts.json"); err != nil {
+		fmt.Println("Warning: could not load column layouts:", err)
+	}
+
 	v := components.RootScreen(registry)
 
 	if _, err := tea.NewProgram(v).Run(); err != nil {
@@ -0,0 +1,91 @@
+package chart
+
+// brailleDots are the bits of the dots of a braille character, by row and
+// column of the dot.
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// canvas is a grid of braille characters, each 2×4 dots, that lines are
+// plotted on. A character takes the series of the last dot set in it.
+type canvas struct {
+	width, height int
+	dots          [][]rune
+	series        [][]int
+}
+
+// newCanvas returns an empty canvas of width×height characters.
+func newCanvas(width, height int) *canvas {
+	c := &canvas{width: width, height: height}
+	c.dots = make([][]rune, height)
+	c.series = make([][]int, height)
+	for row := range height {
+		c.dots[row] = make([]rune, width)
+		c.series[row] = make([]int, width)
+	}
+	return c
+}
+
+// set sets the dot at x, y, counted from the top left, for a series.
+func (c *canvas) set(x, y, series int) {
+	if x < 0 || y < 0 || x >= c.width*2 || y >= c.height*4 {
+		return
+	}
+	c.dots[y/4][x/2] |= brailleDots[y%4][x%2]
+	c.series[y/4][x/2] = series
+}
+
+// line sets the dots on the line from x0, y0 to x1, y1.
+func (c *canvas) line(x0, y0, x1, y1, series int) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	// Bresenham's line algorithm
+	err := dx + dy
+	for {
+		c.set(x0, y0, series)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// cells returns the characters of the canvas.
+func (c *canvas) cells() [][]cell {
+	cells := make([][]cell, c.height)
+	for row := range cells {
+		cells[row] = make([]cell, c.width)
+		for col := range cells[row] {
+			if dots := c.dots[row][col]; dots != 0 {
+				cells[row][col] = cell{r: 0x2800 + dots, series: c.series[row][col]}
+			} else {
+				cells[row][col] = cell{r: ' ', series: -1}
+			}
+		}
+	}
+	return cells
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
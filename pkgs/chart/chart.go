@@ -0,0 +1,507 @@
+// Package chart draws line, bar and histogram charts of numbers in the
+// terminal: lines with braille dots and bars with block characters.
+package chart
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Kind is the kind of a chart.
+type Kind int
+
+const (
+	// Line plots each series as a line through its points.
+	Line Kind = iota
+	// Bar draws a bar for each value of each series, side by side.
+	Bar
+	// Histogram draws the number of values of the first series in ranges
+	// of equal width.
+	Histogram
+)
+
+// Kinds are the kinds of charts, in the order they are cycled through.
+var Kinds = []Kind{Line, Bar, Histogram}
+
+func (k Kind) String() string {
+	switch k {
+	case Bar:
+		return "bar"
+	case Histogram:
+		return "histogram"
+	}
+	return "line"
+}
+
+// XKind tells how the X values of a chart are placed and labelled.
+type XKind int
+
+const (
+	// XNumber values are placed by value.
+	XNumber XKind = iota
+	// XTime values are Unix times in seconds, placed by value and labelled
+	// as dates and times.
+	XTime
+	// XCategory values are placed in order and labelled by Labels.
+	XCategory
+)
+
+// Series is a named list of Y values, one for each X value. NaN leaves a
+// gap.
+type Series struct {
+	Name   string
+	Values []float64
+}
+
+// Chart is the data of a chart and how to draw it.
+type Chart struct {
+	Kind  Kind
+	XKind XKind
+	// X holds the X values of XNumber and XTime charts
+	X []float64
+	// Labels are the X values as text, shown under bars and on the X axis
+	// of XCategory charts
+	Labels []string
+	Series []Series
+	// Location is the time zone of XTime labels, time.Local if nil
+	Location *time.Location
+	// Styles are the styles of the chart, DefaultStyles if it has none
+	Styles Styles
+}
+
+// maxLabelWidth caps the width of a label under a bar.
+const maxLabelWidth = 16
+
+// cell is a character of the plot area, drawn in the style of a series,
+// or in the plot style when series is -1.
+type cell struct {
+	r      rune
+	series int
+}
+
+// xTick is a label on the X axis at a column of the plot area.
+type xTick struct {
+	col   int
+	label string
+}
+
+// Render draws the chart in width×height cells: a legend, the plot area
+// with the Y axis on its left, and the X axis under it.
+func (c Chart) Render(width, height int) string {
+	if len(c.Styles.Series) == 0 {
+		c.Styles = DefaultStyles()
+	}
+	note := ""
+	if c.Kind == Histogram {
+		var ok bool
+		if c, note, ok = c.histogram(width); !ok {
+			return c.Styles.Label.Render(note)
+		}
+	}
+	plotHeight := height - 3
+	if plotHeight < 2 || width < 20 {
+		return c.Styles.Label.Render("Too small to plot")
+	}
+	lo, hi, ok := c.yRange()
+	if !ok {
+		return c.Styles.Label.Render("No numbers to plot")
+	}
+	yTicks := numberTicks(lo, hi, niceStep(lo, hi, max(1, plotHeight/3)))
+	labelWidth := 0
+	for _, t := range yTicks {
+		labelWidth = max(labelWidth, len(t.label))
+	}
+	plotWidth := width - labelWidth - 1
+
+	var cells [][]cell
+	var xTicks []xTick
+	// rows is the height of a tick at hi above one at lo: a line plot's
+	// top and bottom rows, and a bar's top and its base on the axis
+	rows := plotHeight
+	if c.Kind == Line {
+		cells, xTicks = c.drawLine(plotWidth, plotHeight, lo, hi)
+		rows = plotHeight - 1
+	} else {
+		var shown string
+		cells, xTicks, shown = c.drawBars(plotWidth, plotHeight, lo, hi)
+		note = strings.Join(slices.DeleteFunc([]string{note, shown}, func(s string) bool { return s == "" }), " · ")
+	}
+
+	// The label of each tick is on its row; a bar chart's lowest is on
+	// the axis line under the plot
+	yLabels := make([]string, plotHeight+1)
+	for _, t := range yTicks {
+		row := plotHeight - 1 - int(math.Round(fraction(t.value, lo, hi)*float64(rows)))
+		if c.Kind != Line {
+			row++
+		}
+		if row >= 0 && row <= plotHeight && yLabels[row] == "" {
+			yLabels[row] = t.label
+		}
+	}
+	lines := []string{c.legend(note, width)}
+	for row := range plotHeight {
+		axis := "│"
+		if yLabels[row] != "" {
+			axis = "┤"
+		}
+		lines = append(lines, c.Styles.Label.Render(fmt.Sprintf("%*s", labelWidth, yLabels[row]))+
+			c.Styles.Axis.Render(axis)+c.renderCells(cells[row]))
+	}
+	axis, labels := c.xAxis(xTicks, plotWidth)
+	corner := "└"
+	if yLabels[plotHeight] != "" {
+		corner = "┴"
+	}
+	lines = append(lines,
+		c.Styles.Label.Render(fmt.Sprintf("%*s", labelWidth, yLabels[plotHeight]))+c.Styles.Axis.Render(corner+axis),
+		c.Styles.Plot.Render(strings.Repeat(" ", labelWidth+1))+c.Styles.Label.Render(labels),
+	)
+	return strings.Join(lines, "\n")
+}
+
+// yRange returns the least and greatest Y values, widened to include 0
+// for bars, or false if there are none.
+func (c Chart) yRange() (lo, hi float64, ok bool) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, s := range c.Series {
+		for _, v := range s.Values {
+			if !math.IsNaN(v) && !math.IsInf(v, 0) {
+				lo, hi = min(lo, v), max(hi, v)
+			}
+		}
+	}
+	if lo > hi {
+		return 0, 0, false
+	}
+	if c.Kind != Line {
+		lo, hi = min(lo, 0), max(hi, 0)
+	}
+	if lo == hi {
+		d := max(math.Abs(lo)/10, 1)
+		lo, hi = lo-d, hi+d
+	}
+	return lo, hi, true
+}
+
+// fraction returns where v lies from lo to hi, from 0 to 1. It works on
+// halves so that the widest ranges of float64 do not overflow.
+func fraction(v, lo, hi float64) float64 {
+	f := (v/2 - lo/2) / (hi/2 - lo/2)
+	if !finite(f) {
+		return 0
+	}
+	return min(1, max(0, f))
+}
+
+// legend returns the names of the series in their styles, and a note.
+func (c Chart) legend(note string, width int) string {
+	var parts []string
+	for i, s := range c.Series {
+		parts = append(parts, c.Styles.series(i).Render("■")+c.Styles.Label.Render(" "+s.Name))
+	}
+	if note != "" {
+		parts = append(parts, c.Styles.Label.Render(note))
+	}
+	return ansi.Truncate(strings.Join(parts, c.Styles.Label.Render("  ")), width, "…")
+}
+
+// xValues returns the positions of the X values: their values, or their
+// indexes for categories.
+func (c Chart) xValues() []float64 {
+	if c.XKind != XCategory {
+		return c.X
+	}
+	n := 0
+	for _, s := range c.Series {
+		n = max(n, len(s.Values))
+	}
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = float64(i)
+	}
+	return x
+}
+
+// drawLine plots each series as a line through its points in order of X.
+func (c Chart) drawLine(width, height int, lo, hi float64) ([][]cell, []xTick) {
+	x := c.xValues()
+	order := make([]int, 0, len(x))
+	xlo, xhi := math.Inf(1), math.Inf(-1)
+	for i, v := range x {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			order = append(order, i)
+			xlo, xhi = min(xlo, v), max(xhi, v)
+		}
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		switch {
+		case x[a] < x[b]:
+			return -1
+		case x[a] > x[b]:
+			return 1
+		}
+		return 0
+	})
+	cv := newCanvas(width, height)
+	if len(order) == 0 {
+		return cv.cells(), nil
+	}
+	if xlo == xhi {
+		xlo, xhi = xlo-1, xhi+1
+	}
+	col := func(v float64, cols int) int {
+		return int(math.Round(fraction(v, xlo, xhi) * float64(cols-1)))
+	}
+
+	for s, series := range c.Series {
+		px, py := -1, -1
+		for _, i := range order {
+			if i >= len(series.Values) || math.IsNaN(series.Values[i]) || math.IsInf(series.Values[i], 0) {
+				px = -1
+				continue
+			}
+			dx := col(x[i], width*2)
+			dy := int(math.Round((1 - fraction(series.Values[i], lo, hi)) * float64(height*4-1)))
+			if px < 0 {
+				cv.set(dx, dy, s)
+			} else {
+				cv.line(px, py, dx, dy, s)
+			}
+			px, py = dx, dy
+		}
+	}
+
+	var ticks []xTick
+	switch c.XKind {
+	case XCategory:
+		for i, label := range c.Labels {
+			ticks = append(ticks, xTick{col: col(float64(i), width), label: label})
+		}
+	case XTime:
+		loc := c.Location
+		if loc == nil {
+			loc = time.Local
+		}
+		for _, t := range timeTicks(xlo, xhi, max(1, width/16), loc) {
+			ticks = append(ticks, xTick{col: col(t.value, width), label: t.label})
+		}
+	default:
+		for _, t := range numberTicks(xlo, xhi, niceStep(xlo, xhi, max(1, width/10))) {
+			ticks = append(ticks, xTick{col: col(t.value, width), label: t.label})
+		}
+	}
+	return cv.cells(), ticks
+}
+
+// eighths are the blocks filling the bottom eighths of a cell.
+var eighths = []rune(" ▁▂▃▄▅▆▇█")
+
+// drawBars draws a group of bars for each X value, one for each series,
+// rising from 0 or hanging below it. Groups that do not fit are left out,
+// and noted.
+func (c Chart) drawBars(width, height int, lo, hi float64) ([][]cell, []xTick, string) {
+	n := 0
+	for _, s := range c.Series {
+		n = max(n, len(s.Values))
+	}
+	cells := make([][]cell, height)
+	for row := range cells {
+		cells[row] = make([]cell, width)
+		for col := range cells[row] {
+			cells[row][col] = cell{r: ' ', series: -1}
+		}
+	}
+	if n == 0 {
+		return cells, nil, ""
+	}
+
+	// Each group has a gap after it
+	shown := min(n, max(1, width/(len(c.Series)+1)))
+	barWidth := min(6, max(1, (width/shown-1)/len(c.Series)))
+	groupWidth := barWidth*len(c.Series) + 1
+	note := ""
+	if shown < n {
+		note = fmt.Sprintf("first %d of %d", shown, n)
+	}
+
+	// Heights are in eighths of a row above the bottom of the plot
+	units := float64(height * 8)
+	eighth := func(v float64) int {
+		return int(math.Round(fraction(v, lo, hi) * units))
+	}
+	base := eighth(0) / 8 * 8
+	layout := c.barTimeLayout(shown)
+	var ticks []xTick
+	for g := range shown {
+		for s, series := range c.Series {
+			if g >= len(series.Values) || math.IsNaN(series.Values[g]) || math.IsInf(series.Values[g], 0) {
+				continue
+			}
+			top := eighth(series.Values[g])
+			for u := base; u < top; u += 8 {
+				r := eighths[min(8, top-u)]
+				for col := g*groupWidth + s*barWidth; col < g*groupWidth+(s+1)*barWidth; col++ {
+					cells[height-1-u/8][col] = cell{r: r, series: s}
+				}
+			}
+			// Below 0 bars fill whole rows
+			for u := base - 8; u >= top-4 && u >= 0; u -= 8 {
+				for col := g*groupWidth + s*barWidth; col < g*groupWidth+(s+1)*barWidth; col++ {
+					cells[height-1-u/8][col] = cell{r: '█', series: s}
+				}
+			}
+		}
+		if label, ok := c.barLabel(g, layout); ok {
+			ticks = append(ticks, xTick{col: g*groupWidth + (groupWidth-1)/2, label: label})
+		}
+	}
+	return cells, ticks, note
+}
+
+// barTimeLayout returns the layout of the labels of the first n bars of
+// an XTime chart, fine enough to tell bars as far apart as the average
+// apart; "" for other charts.
+func (c Chart) barTimeLayout(n int) string {
+	if c.XKind != XTime || len(c.X) < n || n == 0 {
+		return ""
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, x := range c.X[:n] {
+		if !math.IsNaN(x) {
+			lo, hi = min(lo, x), max(hi, x)
+		}
+	}
+	if lo > hi {
+		return ""
+	}
+	unit := year
+	for _, u := range []timeUnit{second, minute, hour, day, month} {
+		if (hi-lo)/float64(max(1, n-1)) < unitSeconds[u+1] {
+			unit = u
+			break
+		}
+	}
+	loc := c.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	return timeLayout(unit, unixTime(lo, loc), unixTime(hi, loc))
+}
+
+// barLabel returns the label under the bars of X value i: its time in
+// layout if there is one, or else its label.
+func (c Chart) barLabel(i int, layout string) (string, bool) {
+	if layout != "" && i < len(c.X) && !math.IsNaN(c.X[i]) {
+		loc := c.Location
+		if loc == nil {
+			loc = time.Local
+		}
+		return unixTime(c.X[i], loc).Format(layout), true
+	}
+	if i < len(c.Labels) {
+		return c.Labels[i], true
+	}
+	return "", false
+}
+
+// histogram returns a bar chart of the number of values of the first
+// series in ranges of a round width, about enough to fill width, and a
+// note of the width; false and why if the values cannot be counted.
+func (c Chart) histogram(width int) (Chart, string, bool) {
+	var values []float64
+	if len(c.Series) > 0 {
+		for _, v := range c.Series[0].Values {
+			if !math.IsNaN(v) && !math.IsInf(v, 0) {
+				values = append(values, v)
+			}
+		}
+	}
+	if len(values) == 0 {
+		return c, "No numbers to plot", false
+	}
+	lo, hi := slices.Min(values), slices.Max(values)
+	// Twice the cube root of the count, as the Rice rule has it, of bars
+	// at least three cells wide
+	bins := min(max(1, (width-8)/4), max(1, int(math.Ceil(2*math.Cbrt(float64(len(values)))))))
+	step := niceStep(lo, hi, bins)
+	start := math.Floor(lo/step) * step
+	n := math.Floor((hi - start) / step)
+	if !finite(step, start, n) || n < 0 || n >= maxTicks {
+		return c, "Too wide a range to count in bins", false
+	}
+	counts := make([]float64, int(n)+1)
+	for _, v := range values {
+		counts[min(len(counts)-1, int(math.Floor((v-start)/step)))]++
+	}
+	edges := numberTicks(start, start+step*float64(len(counts)-1), step)
+	labels := make([]string, len(counts))
+	for i := range labels {
+		if i < len(edges) {
+			labels[i] = edges[i].label
+		}
+	}
+	h := Chart{
+		Kind:   Bar,
+		XKind:  XCategory,
+		Labels: labels,
+		Series: []Series{{Name: c.Series[0].Name, Values: counts}},
+		Styles: c.Styles,
+	}
+	note := ""
+	if ticks := numberTicks(step, step, step); len(ticks) > 0 {
+		note = "bins of " + ticks[0].label
+	}
+	return h, note, true
+}
+
+// xAxis returns the X axis line and the labels under it. Labels are
+// centered on their tick, and left out where they would overlap.
+func (c Chart) xAxis(ticks []xTick, width int) (axis, labels string) {
+	line := []rune(strings.Repeat("─", width))
+	var text strings.Builder
+	end, next := 0, 0
+	for _, t := range ticks {
+		label := ansi.Truncate(strings.ReplaceAll(t.label, "\n", " "), maxLabelWidth, "…")
+		w := ansi.StringWidth(label)
+		if w > width || t.col < 0 || t.col >= width {
+			continue
+		}
+		start := min(max(0, t.col-(w-1)/2), width-w)
+		if start < next {
+			continue
+		}
+		line[t.col] = '┬'
+		text.WriteString(strings.Repeat(" ", start-end) + label)
+		end = start + w
+		next = end + 1
+	}
+	text.WriteString(strings.Repeat(" ", width-end))
+	return string(line), text.String()
+}
+
+// renderCells renders a row of the plot area, a run of cells of the same
+// series at a time.
+func (c Chart) renderCells(cells []cell) string {
+	var b strings.Builder
+	for i := 0; i < len(cells); {
+		j := i
+		var run []rune
+		for j < len(cells) && cells[j].series == cells[i].series {
+			run = append(run, cells[j].r)
+			j++
+		}
+		style := c.Styles.Plot
+		if cells[i].series >= 0 {
+			style = c.Styles.series(cells[i].series)
+		}
+		b.WriteString(style.Render(string(run)))
+		i = j
+	}
+	return b.String()
+}
@@ -0,0 +1,108 @@
+package chart
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+)
+
+func tickLabels(ticks []tick) []string {
+	labels := make([]string, len(ticks))
+	for i, t := range ticks {
+		labels[i] = t.label
+	}
+	return labels
+}
+
+func TestNumberTicks(t *testing.T) {
+	assert.Equal(t, 2.0, niceStep(0, 9, 5))
+	assert.Equal(t, 0.05, niceStep(0.1, 0.3, 4))
+	assert.Equal(t, []string{"0", "2", "4", "6", "8"}, tickLabels(numberTicks(0, 9, 2)))
+	assert.Equal(t, []string{"0.10", "0.15", "0.20"}, tickLabels(numberTicks(0.1, 0.2, 0.05)))
+	assert.Equal(t, []string{"-25k", "0k", "25k", "50k"}, tickLabels(numberTicks(-25000, 50000, 25000)))
+	assert.Equal(t, []string{"1.5M", "2.0M"}, tickLabels(numberTicks(1.2e6, 2e6, 5e5)))
+
+	// Ranges that cannot be divided end without ticks rather than looping
+	assert.Empty(t, numberTicks(-math.MaxFloat64, math.MaxFloat64, niceStep(-math.MaxFloat64, math.MaxFloat64, 5)))
+	assert.Empty(t, numberTicks(0, math.Inf(1), 1))
+	assert.Empty(t, numberTicks(0, 1, 0))
+	assert.Len(t, numberTicks(math.MaxFloat64/2, math.MaxFloat64, math.MaxFloat64/4), 3)
+	assert.Len(t, numberTicks(1e300, 1e300, 1e280), 1)
+}
+
+func TestTimeTicks(t *testing.T) {
+	at := func(s string) float64 {
+		tm, err := time.Parse(time.DateTime, s)
+		assert.NoError(t, err)
+		return float64(tm.Unix())
+	}
+	assert.Equal(t, []string{"09:00", "12:00", "15:00", "18:00"},
+		tickLabels(timeTicks(at("2026-05-04 08:10:00"), at("2026-05-04 19:00:00"), 5, time.UTC)))
+	assert.Equal(t, []string{"05-04 18:00", "05-05 00:00", "05-05 06:00"},
+		tickLabels(timeTicks(at("2026-05-04 17:00:00"), at("2026-05-05 08:00:00"), 4, time.UTC)))
+	assert.Equal(t, []string{"Apr 2026", "Jul 2026", "Oct 2026", "Jan 2027"},
+		tickLabels(timeTicks(at("2026-02-11 00:00:00"), at("2027-02-01 00:00:00"), 4, time.UTC)))
+
+	// Ticks fall on whole days of the time zone shown
+	loc := time.FixedZone("UTC+3", 3*3600)
+	ticks := timeTicks(at("2026-05-01 00:00:00"), at("2026-05-04 00:00:00"), 3, loc)
+	assert.Equal(t, []string{"May 02", "May 03", "May 04"}, tickLabels(ticks))
+	assert.Equal(t, at("2026-05-01 21:00:00"), ticks[0].value)
+}
+
+func TestRenderExtremes(t *testing.T) {
+	for _, kind := range Kinds {
+		c := Chart{
+			Kind:   kind,
+			XKind:  XNumber,
+			X:      []float64{-math.MaxFloat64, 0, math.MaxFloat64},
+			Series: []Series{{Name: "v", Values: []float64{-math.MaxFloat64, 1, math.MaxFloat64}}},
+		}
+		assert.NotEmpty(t, c.Render(40, 10), kind.String())
+	}
+}
+
+func TestRender(t *testing.T) {
+	c := Chart{
+		Kind:   Bar,
+		XKind:  XCategory,
+		Labels: []string{"a", "b"},
+		Series: []Series{{Name: "total", Values: []float64{4, 2}}},
+	}
+	assert.Equal(t, strings.Join([]string{
+		"■ total",
+		"4┤██████            ",
+		" │██████            ",
+		" │██████            ",
+		"2┤██████ ▄▄▄▄▄▄     ",
+		" │██████ ██████     ",
+		" │██████ ██████     ",
+		" │██████ ██████     ",
+		"0┴───┬──────┬───────",
+		"     a      b       ",
+	}, "\n"), ansi.Strip(c.Render(20, 10)))
+
+	c.Kind = Line
+	c.Series[0].Values = []float64{0, math.NaN()}
+	assert.Equal(t, strings.Join([]string{
+		"■ total",
+		" │                  ",
+		"0┤⠄                 ",
+		" │                  ",
+		" └┬────────────────┬",
+		"  a                b",
+	}, "\n"), ansi.Strip(c.Render(20, 6)))
+
+	c.Kind = Histogram
+	c.Series[0].Values = []float64{1, 2, 2, 3, 9, math.NaN()}
+	assert.Equal(t, "■ total  bins of 2", ansi.Strip(strings.Split(c.Render(40, 8), "\n")[0]))
+
+	c.Series[0].Values = nil
+	assert.Equal(t, "No numbers to plot", ansi.Strip(c.Render(40, 8)))
+	c.Kind = Line
+	assert.Equal(t, "Too small to plot", ansi.Strip(c.Render(10, 8)))
+}
@@ -0,0 +1,41 @@
+package chart
+
+import (
+	"image/color"
+
+	"charm.land/lipgloss/v2"
+	"github.com/SavingFrame/dbettier/internal/theme"
+)
+
+// Styles are the styles a chart is drawn with.
+type Styles struct {
+	// Plot is the style of the plot area where nothing is drawn
+	Plot lipgloss.Style
+	// Axis is the style of the axis lines and ticks
+	Axis lipgloss.Style
+	// Label is the style of tick labels, the legend and messages
+	Label lipgloss.Style
+	// Series are the styles of the series, in order, repeated when there
+	// are more series
+	Series []lipgloss.Style
+}
+
+// DefaultStyles returns the styles of the current theme.
+func DefaultStyles() Styles {
+	colors := theme.Current().Colors
+	base := lipgloss.NewStyle().Background(colors.Base)
+	styles := Styles{
+		Plot:  base,
+		Axis:  base.Foreground(colors.Border),
+		Label: base.Foreground(colors.Subtle),
+	}
+	for _, c := range []color.Color{colors.Blue, colors.Peach, colors.Green, colors.Pink, colors.Teal, colors.Yellow, colors.Purple} {
+		styles.Series = append(styles.Series, base.Foreground(c))
+	}
+	return styles
+}
+
+// series returns the style of the series at index i.
+func (s Styles) series(i int) lipgloss.Style {
+	return s.Series[i%len(s.Series)]
+}
@@ -0,0 +1,220 @@
+package chart
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// tick is a labelled value on an axis.
+type tick struct {
+	value float64
+	label string
+}
+
+// niceStep returns the round step, 1, 2 or 5 times a power of ten, that
+// divides lo to hi into closest to n parts.
+func niceStep(lo, hi float64, n int) float64 {
+	span := hi - lo
+	if span <= 0 {
+		span = max(math.Abs(lo), 1)
+	}
+	raw := span / float64(max(1, n))
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	switch f := raw / mag; {
+	case f < 1.5:
+		return mag
+	case f < 3:
+		return 2 * mag
+	case f < 7:
+		return 5 * mag
+	}
+	return 10 * mag
+}
+
+// maxTicks bounds the ticks of an axis, and the bins of a histogram.
+const maxTicks = 1000
+
+// finite reports whether none of vs is infinite or NaN.
+func finite(vs ...float64) bool {
+	for _, v := range vs {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// numberTicks returns the multiples of step from lo to hi. It returns none
+// when the range or the step is not finite, or when they are so large or so
+// far apart that the ticks cannot be told apart or counted.
+func numberTicks(lo, hi, step float64) []tick {
+	if !finite(lo, hi, step, hi-lo) || step <= 0 || (hi-lo)/step > maxTicks {
+		return nil
+	}
+	// Large numbers are labelled in thousands, millions and so on
+	scale, suffix := 1.0, ""
+	if top := max(math.Abs(lo), math.Abs(hi)); top >= 1e4 {
+		for _, u := range []struct {
+			scale  float64
+			suffix string
+		}{{1e12, "T"}, {1e9, "G"}, {1e6, "M"}, {1e3, "k"}} {
+			if top >= u.scale {
+				scale, suffix = u.scale, u.suffix
+				break
+			}
+		}
+	}
+	decimals := max(0, int(math.Ceil(-math.Log10(step/scale)-1e-9)))
+
+	var ticks []tick
+	first := math.Ceil(lo/step - 1e-9)
+	for i := 0.0; ; i++ {
+		v := (first + i) * step
+		if v > hi+step*1e-9 || !finite(v) || len(ticks) > 0 && v <= ticks[len(ticks)-1].value {
+			// Past hi, or so large that adding step overflows or no longer
+			// changes v
+			break
+		}
+		if math.Abs(v) < step*1e-9 {
+			// Not -0 or a rounding error
+			v = 0
+		}
+		label := strconv.FormatFloat(v/scale, 'f', decimals, 64)
+		if math.Abs(v/scale) >= 1e6 {
+			// Beyond trillions in exponent form
+			label = strconv.FormatFloat(v, 'g', 3, 64)
+			ticks = append(ticks, tick{value: v, label: label})
+			continue
+		}
+		ticks = append(ticks, tick{value: v, label: label + suffix})
+	}
+	return ticks
+}
+
+// timeUnit is a calendar unit time ticks are a multiple of.
+type timeUnit int
+
+const (
+	second timeUnit = iota
+	minute
+	hour
+	day
+	month
+	year
+)
+
+// timeSteps are the steps between time ticks, from the finest.
+var timeSteps = []struct {
+	unit timeUnit
+	n    int
+}{
+	{second, 1}, {second, 5}, {second, 15}, {second, 30},
+	{minute, 1}, {minute, 5}, {minute, 15}, {minute, 30},
+	{hour, 1}, {hour, 3}, {hour, 6}, {hour, 12},
+	{day, 1}, {day, 2}, {day, 7}, {day, 14},
+	{month, 1}, {month, 3}, {month, 6},
+	{year, 1}, {year, 2}, {year, 5}, {year, 10}, {year, 20}, {year, 50}, {year, 100},
+}
+
+// unitSeconds are the approximate lengths of the time units.
+var unitSeconds = map[timeUnit]float64{
+	second: 1,
+	minute: 60,
+	hour:   3600,
+	day:    86400,
+	month:  30.44 * 86400,
+	year:   365.25 * 86400,
+}
+
+// timeTicks returns about n ticks on whole seconds, minutes, hours, days,
+// months or years from lo to hi, given in Unix seconds, labelled in loc.
+func timeTicks(lo, hi float64, n int, loc *time.Location) []tick {
+	if !finite(lo, hi) || hi < lo {
+		return nil
+	}
+	step := timeSteps[len(timeSteps)-1]
+	for _, s := range timeSteps {
+		if (hi-lo)/(float64(s.n)*unitSeconds[s.unit]) <= float64(max(1, n)) {
+			step = s
+			break
+		}
+	}
+
+	start, end := unixTime(lo, loc), unixTime(hi, loc)
+	layout := timeLayout(step.unit, start, end)
+	var ticks []tick
+	for t := truncateTime(start, step.unit, step.n); !t.After(end); t = addTime(t, step.unit, step.n) {
+		if !t.Before(start) {
+			ticks = append(ticks, tick{value: float64(t.UnixNano()) / 1e9, label: t.Format(layout)})
+		}
+	}
+	return ticks
+}
+
+// timeLayout returns the layout of the labels of ticks on a multiple of
+// unit from start to end: enough to tell the ticks apart, and the date
+// when the ticks span days.
+func timeLayout(unit timeUnit, start, end time.Time) string {
+	sameYear := start.Year() == end.Year()
+	sameDay := sameYear && start.YearDay() == end.YearDay()
+	switch unit {
+	case second:
+		if !sameDay {
+			return "01-02 15:04:05"
+		}
+		return "15:04:05"
+	case minute, hour:
+		if !sameDay {
+			return "01-02 15:04"
+		}
+		return "15:04"
+	case day:
+		if !sameYear {
+			return "2006-01-02"
+		}
+		return "Jan 02"
+	case month:
+		return "Jan 2006"
+	}
+	return "2006"
+}
+
+// truncateTime returns the start of the multiple of n units t falls in.
+func truncateTime(t time.Time, unit timeUnit, n int) time.Time {
+	y, mo, d := t.Date()
+	h, mi, s := t.Clock()
+	loc := t.Location()
+	switch unit {
+	case second:
+		return time.Date(y, mo, d, h, mi, s-s%n, 0, loc)
+	case minute:
+		return time.Date(y, mo, d, h, mi-mi%n, 0, 0, loc)
+	case hour:
+		return time.Date(y, mo, d, h-h%n, 0, 0, 0, loc)
+	case day:
+		return time.Date(y, mo, d-(d-1)%n, 0, 0, 0, 0, loc)
+	case month:
+		return time.Date(y, mo-(mo-1)%time.Month(n), 1, 0, 0, 0, 0, loc)
+	}
+	return time.Date(y-y%n, time.January, 1, 0, 0, 0, 0, loc)
+}
+
+// addTime adds n units to t.
+func addTime(t time.Time, unit timeUnit, n int) time.Time {
+	switch unit {
+	case day:
+		return t.AddDate(0, 0, n)
+	case month:
+		return t.AddDate(0, n, 0)
+	case year:
+		return t.AddDate(n, 0, 0)
+	}
+	return t.Add(time.Duration(n*int(unitSeconds[unit])) * time.Second)
+}
+
+// unixTime returns the time of Unix seconds v in loc.
+func unixTime(v float64, loc *time.Location) time.Time {
+	sec := math.Floor(v)
+	return time.Unix(int64(sec), int64((v-sec)*1e9)).In(loc)
+}
@@ -0,0 +1,181 @@
+// Package clipboard copies text to the system clipboard from a Bubble Tea
+// program, through the terminal with OSC 52 escapes or with a native
+// clipboard tool.
+package clipboard
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Method is how text is put on the clipboard.
+type Method int
+
+const (
+	// Auto uses a native tool in a local graphical session, where one can
+	// reach the clipboard, and otherwise OSC 52 when the terminal is likely
+	// to support it and the text is not too large for it.
+	Auto Method = iota
+	// OSC52 always writes an OSC 52 escape to the terminal. This works over
+	// SSH, but cannot tell whether the terminal took it.
+	OSC52
+	// Native always runs a clipboard tool such as pbcopy, wl-copy or xclip.
+	Native
+)
+
+// Preferred is the method Copy uses.
+var Preferred = Auto
+
+// ParseMethod returns the method named auto, osc52 or native.
+func ParseMethod(name string) (Method, error) {
+	switch strings.ToLower(name) {
+	case "auto":
+		return Auto, nil
+	case "osc52":
+		return OSC52, nil
+	case "native":
+		return Native, nil
+	}
+	return Auto, fmt.Errorf("unknown clipboard method %q, expected auto, osc52 or native", name)
+}
+
+// maxOSC52 is the size of the largest text sent with OSC 52 in Auto mode.
+// Many terminals silently drop longer escapes.
+const maxOSC52 = 100 << 10
+
+// ErrNoTool is reported when no native clipboard tool is installed.
+var ErrNoTool = errors.New("no clipboard tool found; install wl-copy, xclip or xsel, or set DBETTIER_CLIPBOARD=osc52 if the terminal supports OSC 52")
+
+// CopiedMsg is sent when text was copied, or failed to be copied.
+type CopiedMsg struct {
+	Text string
+	Err  error
+}
+
+// Copy returns a command that puts text on the clipboard and reports back
+// with a CopiedMsg.
+func Copy(text string) tea.Cmd {
+	if useOSC52(Preferred, text) {
+		return tea.Sequence(
+			tea.Raw(osc52(text)),
+			func() tea.Msg { return CopiedMsg{Text: text} },
+		)
+	}
+	return func() tea.Msg {
+		return CopiedMsg{Text: text, Err: copyNative(text)}
+	}
+}
+
+// useOSC52 reports whether text is copied with OSC 52 rather than a native
+// tool.
+func useOSC52(method Method, text string) bool {
+	switch method {
+	case OSC52:
+		return true
+	case Native:
+		return false
+	}
+	if len(text) > maxOSC52 {
+		return false
+	}
+	// Many terminals, such as GNOME Terminal and others built on VTE,
+	// ignore OSC 52 without telling, so a native tool is more reliable
+	// wherever one can reach the clipboard
+	if localSession() && haveNativeTool() {
+		return false
+	}
+	// The Linux console and macOS Terminal ignore OSC 52
+	term := os.Getenv("TERM")
+	return term != "linux" && term != "dumb" && os.Getenv("TERM_PROGRAM") != "Apple_Terminal"
+}
+
+// localSession reports whether the program runs in a graphical session on
+// the machine in front of the user rather than over SSH.
+func localSession() bool {
+	if os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != "" {
+		return false
+	}
+	switch runtime.GOOS {
+	case "darwin", "windows":
+		return true
+	}
+	return os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != ""
+}
+
+// osc52 returns the escape that sets the clipboard to text. Inside tmux and
+// GNU Screen it is wrapped to be passed through to the outer terminal.
+func osc52(text string) string {
+	seq := ansi.SetSystemClipboard(text)
+	switch {
+	case os.Getenv("TMUX") != "":
+		return ansi.TmuxPassthrough(seq)
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		// Screen limits string escapes to 768 bytes
+		return ansi.ScreenPassthrough(seq, 768)
+	}
+	return seq
+}
+
+// tool is a native clipboard command that reads the text from stdin.
+type tool struct {
+	name string
+	args []string
+}
+
+// nativeTools returns the clipboard tools to look for, in order of
+// preference.
+func nativeTools() []tool {
+	switch runtime.GOOS {
+	case "darwin":
+		return []tool{{name: "pbcopy"}}
+	case "windows":
+		return []tool{{name: "clip.exe"}}
+	}
+	var tools []tool
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		tools = append(tools, tool{name: "wl-copy"})
+	}
+	return append(tools,
+		tool{name: "xclip", args: []string{"-selection", "clipboard"}},
+		tool{name: "xsel", args: []string{"--clipboard", "--input"}},
+		// Windows' own, under WSL
+		tool{name: "clip.exe"},
+	)
+}
+
+// haveNativeTool reports whether a clipboard tool is installed.
+func haveNativeTool() bool {
+	for _, t := range nativeTools() {
+		if _, err := exec.LookPath(t.name); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// copyNative runs the first clipboard tool found with text as its input.
+// The tool is run directly rather than through a shell.
+func copyNative(text string) error {
+	for _, t := range nativeTools() {
+		path, err := exec.LookPath(t.name)
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, t.args...)
+		cmd.Stdin = strings.NewReader(text)
+		// xclip and xsel leave a process serving the selection which keeps
+		// any output pipe open, so none is attached
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s: %w", t.name, err)
+		}
+		return nil
+	}
+	return ErrNoTool
+}
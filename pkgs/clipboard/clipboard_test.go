@@ -0,0 +1,76 @@
+package clipboard
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOSC52(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("TERM", "xterm-256color")
+	assert.Equal(t, "\x1b]52;c;aGk=\x07", osc52("hi"))
+
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	t.Setenv("TERM", "tmux-256color")
+	assert.Equal(t, "\x1bPtmux;\x1b\x1b]52;c;aGk=\x07\x1b\\", osc52("hi"))
+
+	t.Setenv("TMUX", "")
+	t.Setenv("TERM", "screen")
+	assert.Equal(t, "\x1bP\x1b]52;c;aGk=\x07\x1b\\", osc52("hi"))
+}
+
+func TestUseOSC52(t *testing.T) {
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("TERM_PROGRAM", "")
+	t.Setenv("SSH_TTY", "/dev/pts/0")
+	assert.True(t, useOSC52(Auto, "hi"))
+	assert.False(t, useOSC52(Auto, strings.Repeat("x", maxOSC52+1)))
+	assert.True(t, useOSC52(OSC52, strings.Repeat("x", maxOSC52+1)))
+	assert.False(t, useOSC52(Native, "hi"))
+
+	t.Setenv("TERM_PROGRAM", "Apple_Terminal")
+	assert.False(t, useOSC52(Auto, "hi"))
+}
+
+func TestUseOSC52LocalSession(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script as a fake clipboard tool")
+	}
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("TERM_PROGRAM", "")
+	t.Setenv("SSH_TTY", "")
+	t.Setenv("SSH_CONNECTION", "")
+	t.Setenv("DISPLAY", ":0")
+	t.Setenv("WAYLAND_DISPLAY", "")
+
+	// Without a tool OSC 52 is the only way
+	t.Setenv("PATH", t.TempDir())
+	assert.True(t, useOSC52(Auto, "hi"))
+
+	// A local session prefers its clipboard tool, which the terminal may not
+	// forward OSC 52 to
+	dir := t.TempDir()
+	for _, name := range []string{"pbcopy", "xclip"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0o755))
+	}
+	t.Setenv("PATH", dir)
+	assert.False(t, useOSC52(Auto, "hi"))
+
+	// Over SSH the tool would copy on the remote machine
+	t.Setenv("SSH_CONNECTION", "10.0.0.1 50000 10.0.0.2 22")
+	assert.True(t, useOSC52(Auto, "hi"))
+}
+
+func TestParseMethod(t *testing.T) {
+	m, err := ParseMethod("OSC52")
+	assert.NoError(t, err)
+	assert.Equal(t, OSC52, m)
+	_, err = ParseMethod("pbcopy")
+	assert.Error(t, err)
+}
@@ -16,6 +16,8 @@ type KeyMap struct {
 	Space            key.Binding
 	EndLineEdge      key.Binding
 	StartLineEdge    key.Binding
+	YankLine         key.Binding
+	YankAll          key.Binding
 }
 
 var NormalModeKeymap = KeyMap{
@@ -55,6 +57,14 @@ var NormalModeKeymap = KeyMap{
 	EndLineEdge: key.NewBinding(
 		key.WithKeys("$"),
 	),
+	YankLine: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy line"),
+	),
+	YankAll: key.NewBinding(
+		key.WithKeys("Y"),
+		key.WithHelp("Y", "copy all"),
+	),
 }
 
 var InsertModeKeymap = KeyMap{
@@ -3,6 +3,7 @@ package editor
 import (
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/pkgs/clipboard"
 )
 
 func (m SQLEditor) Update(msg tea.Msg) (SQLEditor, tea.Cmd) {
@@ -67,6 +68,10 @@ func (m *SQLEditor) processNormalModeKey(msg tea.KeyMsg) tea.Cmd {
 	case key.Matches(msg, NormalModeKeymap.StartLineEdge):
 		m.cursor.gotoStartEdge(m.buffer)
 		cmd = func() tea.Msg { return EditorCursorMovedMsg{Row: m.cursor.row, Col: m.cursor.col} }
+	case key.Matches(msg, NormalModeKeymap.YankLine):
+		cmd = clipboard.Copy(m.buffer.lines[m.cursor.row])
+	case key.Matches(msg, NormalModeKeymap.YankAll):
+		cmd = clipboard.Copy(m.GetContent())
 	case key.Matches(msg, NormalModeKeymap.Exit):
 		cmd = tea.Quit
 	}
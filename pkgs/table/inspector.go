@@ -0,0 +1,512 @@
+package table
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/SavingFrame/dbettier/internal/theme"
+	"github.com/SavingFrame/dbettier/pkgs/clipboard"
+	"github.com/alecthomas/chroma/v2/quick"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// inspectMode is how the inspector shows a value.
+type inspectMode int
+
+const (
+	inspectText inspectMode = iota
+	inspectHex
+)
+
+// inspector is an overlay showing the full value of one cell, wrapped and
+// scrollable, with JSON and XML pretty-printed and a hex dump for binary
+// values.
+type inspector struct {
+	title  string
+	value  string
+	null   bool
+	format string // "json", "xml" or "" for plain text
+	mode   inspectMode
+
+	width  int
+	height int
+	offset int
+
+	// lines are the rendered lines; plain holds them without styling for
+	// searching
+	lines []string
+	plain []string
+
+	searchMode  bool
+	searchQuery string
+	matches     []int // lines containing the query
+	matchIndex  int
+}
+
+// newInspector creates an inspector for a cell value of the given column.
+func newInspector(col Column, value, nullText string, width, height int) *inspector {
+	in := &inspector{
+		title:      col.Title,
+		value:      value,
+		null:       value == Null,
+		matchIndex: -1,
+	}
+	if in.null {
+		in.value = nullText
+	}
+	if col.Type != "" {
+		in.title += " " + col.Type
+	}
+	if !in.null {
+		in.format = detectFormat(col.Type, value)
+		if col.Type == "bytea" {
+			in.mode = inspectHex
+		}
+	}
+	in.resize(width, height)
+	return in
+}
+
+// detectFormat tells JSON and XML values apart from plain text, by column
+// type or, for text columns and unknown types, by content.
+func detectFormat(colType, value string) string {
+	switch colType {
+	case "json", "jsonb":
+		return "json"
+	case "xml":
+		return "xml"
+	}
+	trimmed := strings.TrimSpace(value)
+	if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+		return "json"
+	}
+	if strings.HasPrefix(trimmed, "<") && strings.HasSuffix(trimmed, ">") {
+		if _, err := indentXML(trimmed); err == nil {
+			return "xml"
+		}
+	}
+	return ""
+}
+
+// resize sets the size of the overlay, borders included, and re-renders
+// the value to fit.
+func (in *inspector) resize(width, height int) {
+	in.width = max(width, 24)
+	in.height = max(height, 6)
+	in.render()
+}
+
+// bodySize returns the space left for the value inside the border, padding,
+// title and footer.
+func (in *inspector) bodySize() (width, height int) {
+	return in.width - 4, in.height - 4
+}
+
+// render lays out the value in the current mode.
+func (in *inspector) render() {
+	width, _ := in.bodySize()
+	var content string
+	switch {
+	case in.null:
+		content = in.value
+	case in.mode == inspectHex:
+		content = hexDump(valueBytes(in.value), width)
+	default:
+		content = in.prettyValue()
+	}
+
+	in.lines, in.plain = nil, nil
+	for _, line := range strings.Split(content, "\n") {
+		wrapped := strings.Split(ansi.Wrap(line, width, ""), "\n")
+		for _, w := range wrapped {
+			in.lines = append(in.lines, w)
+			in.plain = append(in.plain, ansi.Strip(w))
+		}
+	}
+	in.updateMatches()
+	in.clampOffset()
+}
+
+// prettyValue indents and highlights JSON and XML values, returning other
+// values unchanged.
+func (in *inspector) prettyValue() string {
+	switch in.format {
+	case "json":
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, []byte(strings.TrimSpace(in.value)), "", "  "); err != nil {
+			return in.value
+		}
+		return highlight(buf.String(), "json")
+	case "xml":
+		pretty, err := indentXML(in.value)
+		if err != nil {
+			return in.value
+		}
+		return highlight(pretty, "xml")
+	}
+	return in.value
+}
+
+// highlight colors code with the current theme's syntax style.
+func highlight(code, lexer string) string {
+	var buf bytes.Buffer
+	if err := quick.Highlight(&buf, code, lexer, "terminal256", theme.Current().Name); err != nil {
+		return code
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// indentXML re-indents an XML document, keeping text next to its tags.
+func indentXML(value string) (string, error) {
+	dec := xml.NewDecoder(strings.NewReader(value))
+	dec.Strict = false
+	var b strings.Builder
+	depth := 0
+	// inline is true after a start tag or text, when the next end tag
+	// closes on the same line
+	inline := false
+	newline := func() {
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(strings.Repeat("  ", depth))
+	}
+	for {
+		tok, err := dec.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			newline()
+			b.WriteString("<" + xmlName(t.Name))
+			for _, attr := range t.Attr {
+				b.WriteString(" " + xmlName(attr.Name) + `="`)
+				_ = xml.EscapeText(&b, []byte(attr.Value))
+				b.WriteString(`"`)
+			}
+			b.WriteString(">")
+			depth++
+			inline = true
+		case xml.EndElement:
+			depth--
+			if !inline {
+				newline()
+			}
+			b.WriteString("</" + xmlName(t.Name) + ">")
+			inline = false
+		case xml.CharData:
+			text := strings.TrimSpace(string(t))
+			if text == "" {
+				continue
+			}
+			if !inline {
+				newline()
+			}
+			_ = xml.EscapeText(&b, []byte(text))
+		case xml.Comment:
+			newline()
+			b.WriteString("<!--" + string(t) + "-->")
+			inline = false
+		case xml.ProcInst:
+			newline()
+			b.WriteString("<?" + t.Target + " " + string(t.Inst) + "?>")
+			inline = false
+		case xml.Directive:
+			newline()
+			b.WriteString("<!" + string(t) + ">")
+			inline = false
+		}
+	}
+	if depth != 0 {
+		return "", errors.New("unclosed element")
+	}
+	return b.String(), nil
+}
+
+func xmlName(n xml.Name) string {
+	if n.Space != "" {
+		return n.Space + ":" + n.Local
+	}
+	return n.Local
+}
+
+// valueBytes returns the bytes of a value, decoding bytea's \x hex format.
+func valueBytes(value string) []byte {
+	if strings.HasPrefix(value, `\x`) {
+		if b, err := hex.DecodeString(value[2:]); err == nil {
+			return b
+		}
+	}
+	return []byte(value)
+}
+
+// hexDump formats data like hexdump -C, with as many bytes per line as fit
+// in width, in multiples of 4.
+func hexDump(data []byte, width int) string {
+	// offset (8) + 2 spaces, 3 columns per byte, then 2 for the | and 1 per
+	// byte of ASCII
+	perLine := max(4, (width-12)/4/4*4)
+	var b strings.Builder
+	for off := 0; off < len(data); off += perLine {
+		chunk := data[off:min(off+perLine, len(data))]
+		fmt.Fprintf(&b, "%08x  ", off)
+		for i := range perLine {
+			if i < len(chunk) {
+				fmt.Fprintf(&b, "%02x ", chunk[i])
+			} else {
+				b.WriteString("   ")
+			}
+		}
+		b.WriteString("|")
+		for _, c := range chunk {
+			if c >= 32 && c < 127 {
+				b.WriteByte(c)
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteString("|\n")
+	}
+	fmt.Fprintf(&b, "%08x", len(data))
+	return b.String()
+}
+
+// update handles keys while the inspector is open. It reports false when
+// the inspector was closed.
+func (in *inspector) update(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if in.searchMode {
+		in.handleSearchInput(msg)
+		return true, nil
+	}
+
+	_, pageHeight := in.bodySize()
+	switch msg.String() {
+	case "esc", "i":
+		if in.searchQuery != "" && msg.String() == "esc" {
+			in.searchQuery = ""
+			in.updateMatches()
+			return true, nil
+		}
+		return false, nil
+	case "j", "down":
+		in.offset++
+	case "k", "up":
+		in.offset--
+	case "ctrl+d":
+		in.offset += pageHeight / 2
+	case "ctrl+u":
+		in.offset -= pageHeight / 2
+	case "ctrl+f", "pgdown", "space":
+		in.offset += pageHeight
+	case "ctrl+b", "pgup":
+		in.offset -= pageHeight
+	case "g":
+		in.offset = 0
+	case "G":
+		in.offset = len(in.lines)
+	case "tab", "x":
+		if !in.null {
+			if in.mode == inspectText {
+				in.mode = inspectHex
+			} else {
+				in.mode = inspectText
+			}
+			in.offset = 0
+			in.render()
+		}
+	case "/":
+		in.searchMode = true
+		in.searchQuery = ""
+		in.updateMatches()
+	case "n":
+		in.jumpToMatch(1)
+	case "N":
+		in.jumpToMatch(-1)
+	case "y":
+		if !in.null {
+			return true, clipboard.Copy(in.value)
+		}
+	}
+	in.clampOffset()
+	return true, nil
+}
+
+// handleSearchInput handles typing a search query.
+func (in *inspector) handleSearchInput(msg tea.KeyMsg) {
+	switch msg.String() {
+	case "esc":
+		in.searchMode = false
+		in.searchQuery = ""
+	case "enter":
+		in.searchMode = false
+	case "backspace":
+		if len(in.searchQuery) > 0 {
+			in.searchQuery = in.searchQuery[:len(in.searchQuery)-1]
+		}
+	default:
+		if len(msg.String()) == 1 && msg.String()[0] >= 32 && msg.String()[0] < 127 {
+			in.searchQuery += msg.String()
+		} else if msg.String() == "space" {
+			in.searchQuery += " "
+		}
+	}
+	in.updateMatches()
+	if len(in.matches) > 0 {
+		in.offset = in.matches[in.matchIndex]
+		in.clampOffset()
+	}
+}
+
+// updateMatches finds the lines containing the search query.
+func (in *inspector) updateMatches() {
+	in.matches = nil
+	in.matchIndex = -1
+	if in.searchQuery == "" {
+		return
+	}
+	query := strings.ToLower(in.searchQuery)
+	for i, line := range in.plain {
+		if strings.Contains(strings.ToLower(line), query) {
+			in.matches = append(in.matches, i)
+		}
+	}
+	if len(in.matches) > 0 {
+		// Start from the first match at or after the top of the view
+		in.matchIndex = 0
+		for i, line := range in.matches {
+			if line >= in.offset {
+				in.matchIndex = i
+				break
+			}
+		}
+	}
+}
+
+// jumpToMatch scrolls to the next (dir 1) or previous (dir -1) match.
+func (in *inspector) jumpToMatch(dir int) {
+	if len(in.matches) == 0 {
+		return
+	}
+	in.matchIndex = (in.matchIndex + dir + len(in.matches)) % len(in.matches)
+	in.offset = in.matches[in.matchIndex]
+}
+
+func (in *inspector) clampOffset() {
+	_, pageHeight := in.bodySize()
+	in.offset = max(0, min(in.offset, len(in.lines)-pageHeight))
+}
+
+// searching reports whether the search query is being typed.
+func (in *inspector) searching() bool {
+	return in.searchMode
+}
+
+// view renders the inspector overlay.
+func (in *inspector) view() string {
+	colors := theme.Current().Colors
+	bodyWidth, bodyHeight := in.bodySize()
+	surface := lipgloss.NewStyle().Background(colors.Surface)
+
+	mode := "text"
+	switch {
+	case in.mode == inspectHex:
+		mode = "hex"
+	case in.format != "":
+		mode = in.format
+	}
+	size := fmt.Sprintf("%d chars", len([]rune(in.value)))
+	if in.mode == inspectHex {
+		size = fmt.Sprintf("%d bytes", len(valueBytes(in.value)))
+	}
+	title := inspectorTitleStyle().Render(in.title) +
+		surface.Render(" ") +
+		inspectorDimStyle().Render(mode+" · "+size)
+
+	var body []string
+	end := min(in.offset+bodyHeight, len(in.lines))
+	for i := in.offset; i < end; i++ {
+		line := in.lines[i]
+		if in.null {
+			line = DefaultStyles().Null.Render(line)
+		} else if in.searchQuery != "" {
+			line = highlightMatches(in.plain[i], in.searchQuery, i == in.currentMatchLine())
+		}
+		body = append(body, line)
+	}
+	for len(body) < bodyHeight {
+		body = append(body, "")
+	}
+	content := lipgloss.NewStyle().
+		Width(bodyWidth).
+		Height(bodyHeight).
+		Background(colors.Surface).
+		Render(strings.Join(body, "\n"))
+
+	footer := "tab hex/text · / search · y yank · esc close"
+	switch {
+	case in.searchMode:
+		footer = "/" + in.searchQuery + "_"
+		if in.searchQuery != "" {
+			footer += fmt.Sprintf(" [%d lines]", len(in.matches))
+		}
+	case in.searchQuery != "":
+		footer = fmt.Sprintf("Search: %q [%d/%d] (n/N to navigate)", in.searchQuery, in.matchIndex+1, len(in.matches))
+	}
+	if len(in.lines) > bodyHeight {
+		footer += fmt.Sprintf(" · %d-%d/%d", in.offset+1, end, len(in.lines))
+	}
+	footer = inspectorDimStyle().Render(ansi.Truncate(footer, bodyWidth, "…"))
+
+	return inspectorStyle().
+		Width(in.width).
+		Render(lipgloss.JoinVertical(lipgloss.Left, title, content, footer))
+}
+
+// currentMatchLine returns the line of the current search match, or -1.
+func (in *inspector) currentMatchLine() int {
+	if in.matchIndex < 0 || in.matchIndex >= len(in.matches) {
+		return -1
+	}
+	return in.matches[in.matchIndex]
+}
+
+// highlightMatches renders a line with every occurrence of query marked.
+func highlightMatches(line, query string, current bool) string {
+	matchStyle := DefaultStyles().SearchMatch.Padding(0)
+	if current {
+		matchStyle = DefaultStyles().SearchMatchActive.Padding(0)
+	}
+	lower := strings.ToLower(line)
+	query = strings.ToLower(query)
+	if len(lower) != len(line) {
+		// Case folding changed byte offsets; mark the whole line
+		if strings.Contains(lower, query) {
+			return matchStyle.Render(line)
+		}
+		return line
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, query)
+		if i < 0 {
+			b.WriteString(line)
+			break
+		}
+		b.WriteString(line[:i])
+		b.WriteString(matchStyle.Render(line[i : i+len(query)]))
+		line, lower = line[i+len(query):], lower[i+len(query):]
+	}
+	return b.String()
+}
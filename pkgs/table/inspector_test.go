@@ -0,0 +1,53 @@
+package table
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectFormat(t *testing.T) {
+	assert.Equal(t, "json", detectFormat("jsonb", `"scalar"`))
+	assert.Equal(t, "json", detectFormat("text", `{"a": [1, 2]}`))
+	assert.Equal(t, "xml", detectFormat("", `<a><b>1</b></a>`))
+	assert.Equal(t, "", detectFormat("text", `{not json}`))
+	assert.Equal(t, "", detectFormat("text", `<a> and <b>`))
+}
+
+func TestIndentXML(t *testing.T) {
+	got, err := indentXML(`<a x="1"><b>text</b><c/><!-- note --></a>`)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		`<a x="1">`,
+		`  <b>text</b>`,
+		`  <c></c>`,
+		`  <!-- note -->`,
+		`</a>`,
+	}, "\n"), got)
+
+	_, err = indentXML(`<a><b></a>`)
+	assert.Error(t, err)
+}
+
+func TestHexDump(t *testing.T) {
+	data := valueBytes(`\x00414243ff`)
+	assert.Equal(t, []byte{0, 'A', 'B', 'C', 0xff}, data)
+	assert.Equal(t,
+		"00000000  00 41 42 43 |.ABC|\n"+
+			"00000004  ff          |.|\n"+
+			"00000005",
+		hexDump(data, 28))
+}
+
+func TestInspectorSearch(t *testing.T) {
+	in := newInspector(Column{Title: "notes"}, "first line\nsecond LINE\nthird", "NULL", 40, 10)
+	in.searchQuery = "line"
+	in.updateMatches()
+	assert.Equal(t, []int{0, 1}, in.matches)
+	in.jumpToMatch(1)
+	assert.Equal(t, 1, in.currentMatchLine())
+	in.jumpToMatch(1)
+	assert.Equal(t, 0, in.currentMatchLine())
+}
@@ -0,0 +1,296 @@
+package table
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// Layout is how the columns of a table are arranged: the order they are
+// shown in, the columns left out, how many of the shown columns stay at
+// the left while scrolling horizontally, and the columns resized. Columns
+// are named by title, so a layout still applies after columns are added or
+// removed.
+type Layout struct {
+	// Order lists column titles in the order shown; columns it leaves out
+	// follow in their own order. Empty keeps the order of the columns.
+	Order []string `json:"order,omitempty"`
+	// Hidden lists the titles of columns that are not shown.
+	Hidden []string `json:"hidden,omitempty"`
+	// Frozen is the number of shown columns kept in view.
+	Frozen int `json:"frozen,omitempty"`
+	// Widths holds the widths of resized columns, by title.
+	Widths map[string]int `json:"widths,omitempty"`
+}
+
+// Empty reports whether the layout shows all columns in their own order
+// and width with none frozen.
+func (l Layout) Empty() bool {
+	return len(l.Order) == 0 && len(l.Hidden) == 0 && l.Frozen == 0 && len(l.Widths) == 0
+}
+
+// LayoutChangedMsg is sent when the layout is changed in the column chooser
+// or a column is resized with a key.
+type LayoutChangedMsg struct {
+	Layout Layout
+}
+
+// columnChooser lists all columns to show, hide, move and freeze.
+type columnChooser struct {
+	// cursor is a position in the column order
+	cursor  int
+	changed bool
+}
+
+// Layout returns the current column layout.
+func (m Model) Layout() Layout {
+	var l Layout
+	natural := true
+	for i, col := range m.order {
+		natural = natural && col == i
+		if m.hidden[col] {
+			l.Hidden = append(l.Hidden, m.cols[col].Title)
+		}
+	}
+	if !natural {
+		l.Order = make([]string, len(m.order))
+		for i, col := range m.order {
+			l.Order[i] = m.cols[col].Title
+		}
+	}
+	l.Frozen = m.frozen
+	for col, width := range m.widths {
+		if l.Widths == nil {
+			l.Widths = make(map[string]int, len(m.widths))
+		}
+		l.Widths[m.cols[col].Title] = width
+	}
+	return l
+}
+
+// SetLayout arranges the columns as in l. Titles it names that are not
+// columns are ignored, and it never hides every column.
+func (m *Model) SetLayout(l Layout) {
+	m.order = make([]int, 0, len(m.cols))
+	placed := make([]bool, len(m.cols))
+	for _, title := range l.Order {
+		for i, col := range m.cols {
+			if !placed[i] && col.Title == title {
+				m.order = append(m.order, i)
+				placed[i] = true
+				break
+			}
+		}
+	}
+	for i := range m.cols {
+		if !placed[i] {
+			m.order = append(m.order, i)
+		}
+	}
+
+	m.hidden = make(map[int]bool, len(l.Hidden))
+	for _, title := range l.Hidden {
+		for _, i := range m.order {
+			if !m.hidden[i] && m.cols[i].Title == title {
+				m.hidden[i] = true
+				break
+			}
+		}
+	}
+	if len(m.hidden) == len(m.cols) {
+		m.hidden = map[int]bool{}
+	}
+	m.frozen = max(0, l.Frozen)
+	m.widths = make(map[int]int, len(l.Widths))
+	for i, col := range m.cols {
+		if width, ok := l.Widths[col.Title]; ok {
+			m.widths[i] = max(MinColumnWidth, width)
+		}
+	}
+	m.relayout()
+}
+
+// relayout works out the shown columns after the layout changed, and moves
+// the focus off a hidden column.
+func (m *Model) relayout() {
+	m.display = make([]int, 0, len(m.order))
+	m.position = make([]int, len(m.cols))
+	for _, col := range m.order {
+		m.position[col] = -1
+		if !m.hidden[col] {
+			m.position[col] = len(m.display)
+			m.display = append(m.display, col)
+		}
+	}
+	if len(m.display) == 0 {
+		m.focusedCol = 0
+		m.scrollOffsetCol = 0
+		return
+	}
+	if !m.isShown(m.focusedCol) {
+		m.focusedCol = m.nearestShown(m.focusedCol)
+	}
+	m.scrollOffsetCol = max(m.scrollOffsetCol, m.frozenCount())
+	m.scrollOffsetCol = min(m.scrollOffsetCol, len(m.display)-1)
+	m.updateScrollCol()
+}
+
+// nearestShown returns the shown column after col in the column order, or
+// before it if there is none, or the first shown column if col is not a
+// column.
+func (m Model) nearestShown(col int) int {
+	at := slices.Index(m.order, col)
+	if at < 0 {
+		return m.display[0]
+	}
+	for _, c := range m.order[at:] {
+		if !m.hidden[c] {
+			return c
+		}
+	}
+	for i := at - 1; i >= 0; i-- {
+		if !m.hidden[m.order[i]] {
+			return m.order[i]
+		}
+	}
+	return m.display[0]
+}
+
+// frozenCount returns how many shown columns stay at the left.
+func (m Model) frozenCount() int {
+	return min(m.frozen, len(m.display))
+}
+
+// isShown reports whether a column is shown.
+func (m Model) isShown(col int) bool {
+	return m.displayPos(col) >= 0
+}
+
+// displayPos returns the position of a column among the shown columns, or
+// -1 if it is hidden or not a column.
+func (m Model) displayPos(col int) int {
+	if col < 0 || col >= len(m.position) {
+		return -1
+	}
+	return m.position[col]
+}
+
+// ChooserOpen reports whether the column chooser is open. It takes all
+// keys while open.
+func (m Model) ChooserOpen() bool {
+	return m.chooser != nil
+}
+
+// openChooser opens the column chooser at the focused column.
+func (m *Model) openChooser() {
+	if len(m.cols) == 0 {
+		return
+	}
+	m.selection = SelectNone
+	m.chooser = &columnChooser{cursor: max(0, slices.Index(m.order, m.focusedCol))}
+}
+
+// updateChooser handles a key in the column chooser. Changes show in the
+// table at once and are reported when it closes.
+func (m Model) updateChooser(msg tea.KeyMsg) (Model, tea.Cmd) {
+	c := m.chooser
+	// The layout is changed in place and may be shared with a copy of the model
+	m.order = slices.Clone(m.order)
+	m.hidden = maps.Clone(m.hidden)
+	col := m.order[c.cursor]
+	switch msg.String() {
+	case "esc", "q", "enter", "C":
+		m.chooser = nil
+		if !c.changed {
+			return m, nil
+		}
+		return m, m.layoutChanged()
+	case "up", "k":
+		c.cursor = max(0, c.cursor-1)
+		return m, nil
+	case "down", "j":
+		c.cursor = min(len(m.order)-1, c.cursor+1)
+		return m, nil
+	case "space", " ", "x":
+		if m.hidden[col] {
+			delete(m.hidden, col)
+		} else if len(m.display) > 1 {
+			m.hidden[col] = true
+		} else {
+			return m, nil
+		}
+	case "shift+up", "K":
+		if c.cursor == 0 {
+			return m, nil
+		}
+		m.order[c.cursor], m.order[c.cursor-1] = m.order[c.cursor-1], m.order[c.cursor]
+		c.cursor--
+	case "shift+down", "J":
+		if c.cursor == len(m.order)-1 {
+			return m, nil
+		}
+		m.order[c.cursor], m.order[c.cursor+1] = m.order[c.cursor+1], m.order[c.cursor]
+		c.cursor++
+	case "f":
+		// Freeze the shown columns up to the cursor, or unfreeze them
+		n := 0
+		for _, col := range m.order[:c.cursor+1] {
+			if !m.hidden[col] {
+				n++
+			}
+		}
+		if n == m.frozenCount() {
+			n = 0
+		}
+		m.frozen = n
+	case "r":
+		m.SetLayout(Layout{})
+		c.cursor = 0
+		c.changed = true
+		return m, nil
+	default:
+		return m, nil
+	}
+	m.relayout()
+	c.changed = true
+	return m, nil
+}
+
+// renderChooser renders the column chooser as a popup.
+func (m Model) renderChooser() string {
+	c := m.chooser
+	rows := max(3, m.height-8)
+	start := max(0, min(c.cursor-rows/2, len(m.order)-rows))
+	end := min(len(m.order), start+rows)
+
+	lines := []string{inspectorTitleStyle().Render(fmt.Sprintf("Columns: %d of %d shown", len(m.display), len(m.cols)))}
+	if start > 0 {
+		lines = append(lines, inspectorDimStyle().Render(fmt.Sprintf("  … %d more", start)))
+	}
+	frozen := m.frozenCount()
+	for i := start; i < end; i++ {
+		col := m.order[i]
+		check := "[x]"
+		if m.hidden[col] {
+			check = "[ ]"
+		}
+		line := " " + check + " " + m.cols[col].Title + " "
+		if i == c.cursor {
+			lines = append(lines, yankMenuSelectedStyle().Render(line))
+		} else {
+			lines = append(lines, inspectorDimStyle().Render(line))
+		}
+		if frozen > 0 && m.position[col] == frozen-1 {
+			lines = append(lines, inspectorDimStyle().Render(" ── frozen above ──"))
+		}
+	}
+	if end < len(m.order) {
+		lines = append(lines, inspectorDimStyle().Render(fmt.Sprintf("  … %d more", len(m.order)-end)))
+	}
+	lines = append(lines, "", inspectorDimStyle().Render("space show/hide · J/K move · f freeze up to here"))
+	lines = append(lines, inspectorDimStyle().Render("r reset · esc close"))
+	return inspectorStyle().Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
@@ -0,0 +1,70 @@
+package table
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayout(t *testing.T) {
+	m := New(
+		WithColumns([]Column{{Title: "id", Width: 10}, {Title: "name", Width: 10}, {Title: "email", Width: 10}, {Title: "total", Width: 10}}),
+		WithRows([]Row{{"1", "ann", "ann@example.com", "7"}}),
+		WithWidth(25),
+	)
+	m.SetLayout(Layout{Order: []string{"id", "total", "missing"}, Hidden: []string{"name"}, Frozen: 1})
+	assert.Equal(t, Layout{Order: []string{"id", "total", "name", "email"}, Hidden: []string{"name"}, Frozen: 1}, m.Layout())
+	assert.Equal(t, []int{0, 3}, m.getVisibleColumns())
+
+	// Scrolling keeps the frozen column and skips the hidden one
+	m.moveRight()
+	m.moveRight()
+	assert.Equal(t, 2, m.focusedCol)
+	assert.Equal(t, []int{0, 2}, m.getVisibleColumns())
+	m.moveRight()
+	assert.Equal(t, 2, m.focusedCol)
+
+	m.selection = SelectRows
+	cols, rows := m.selectedCells()
+	assert.Equal(t, []string{"id", "total", "email"}, columnTitles(cols))
+	assert.Equal(t, []Row{{"1", "7", "ann@example.com"}}, rows)
+	m.selection = SelectNone
+
+	// Hiding the focused column moves the focus, and the layout survives
+	// new columns with the same titles
+	m.SetLayout(Layout{Hidden: []string{"email"}})
+	assert.Equal(t, 3, m.focusedCol)
+	m.SetColumns([]Column{{Title: "id"}, {Title: "email"}, {Title: "total"}})
+	assert.Equal(t, Layout{Hidden: []string{"email"}}, m.Layout())
+
+	// Every column cannot be hidden
+	m.SetLayout(Layout{Hidden: []string{"id", "email", "total"}})
+	assert.True(t, m.Layout().Empty())
+}
+
+func TestColumnChooser(t *testing.T) {
+	m := New(
+		WithColumns([]Column{{Title: "id"}, {Title: "name"}, {Title: "email"}}),
+		WithFocused(true),
+	)
+	press := func(keys ...string) tea.Cmd {
+		var cmd tea.Cmd
+		for _, k := range keys {
+			m, cmd = m.Update(tea.KeyPressMsg{Code: []rune(k)[0], Text: k})
+		}
+		return cmd
+	}
+
+	press("C", "j", "j", "K", "space", "j", "f")
+	assert.True(t, m.ChooserOpen())
+	assert.Equal(t, []int{0, 1}, m.display)
+
+	cmd := press("C")
+	assert.False(t, m.ChooserOpen())
+	assert.Equal(t, LayoutChangedMsg{Layout: Layout{
+		Order:  []string{"id", "email", "name"},
+		Hidden: []string{"email"},
+		Frozen: 2,
+	}}, cmd())
+}
@@ -0,0 +1,109 @@
+package table
+
+import (
+	"maps"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// MinColumnWidth is the narrowest a column can be resized to: one character
+// between the cell padding.
+const MinColumnWidth = 5
+
+// resizeStep is how much a key press widens or narrows a column.
+const resizeStep = 2
+
+// TextWidth returns the width of s in terminal cells, measured by grapheme
+// cluster, so that combined characters, CJK and emoji count as shown.
+func TextWidth(s string) int {
+	return ansi.StringWidth(s)
+}
+
+// colWidth returns the width of a column, as resized or as set.
+func (m Model) colWidth(col int) int {
+	if w, ok := m.widths[col]; ok {
+		return w
+	}
+	return m.cols[col].Width
+}
+
+// ColumnWidth returns the width of a column, as resized or as set, or 0 if
+// it is not a column.
+func (m Model) ColumnWidth(col int) int {
+	if col < 0 || col >= len(m.cols) {
+		return 0
+	}
+	return m.colWidth(col)
+}
+
+// SetColumnWidth resizes a column. The width is kept when the columns are
+// set again and is part of the layout.
+func (m *Model) SetColumnWidth(col, width int) {
+	if col < 0 || col >= len(m.cols) {
+		return
+	}
+	// The widths may be shared with a copy of the model
+	widths := maps.Clone(m.widths)
+	if widths == nil {
+		widths = make(map[int]int)
+	}
+	widths[col] = max(MinColumnWidth, width)
+	m.widths = widths
+	m.updateScrollCol()
+}
+
+// ColumnEdgeAt returns the column whose right edge is at x in the header,
+// counted from the table's left edge, for resizing it with the mouse.
+func (m Model) ColumnEdgeAt(x int) (col int, ok bool) {
+	edge := 0
+	for _, c := range m.getVisibleColumns() {
+		edge += m.colWidth(c)
+		if x == edge-1 || x == edge {
+			return c, true
+		}
+		if x < edge {
+			break
+		}
+	}
+	return 0, false
+}
+
+// resizeFocused widens or narrows the focused column.
+func (m *Model) resizeFocused(delta int) tea.Cmd {
+	if !m.isShown(m.focusedCol) {
+		return nil
+	}
+	m.SetColumnWidth(m.focusedCol, m.colWidth(m.focusedCol)+delta)
+	return m.layoutChanged()
+}
+
+// autofitFocused sizes the focused column to its widest value on the page
+// and its header, up to the table's width.
+func (m *Model) autofitFocused() tea.Cmd {
+	col := m.focusedCol
+	if !m.isShown(col) {
+		return nil
+	}
+	width := TextWidth(m.headerText(col))
+	for row := range m.rows.Len() {
+		cell := m.rows.Cell(row, col)
+		if cell == Null {
+			cell = m.nullText
+		}
+		width = max(width, TextWidth(oneLine(cell)))
+	}
+	// The cell padding, see truncateOrPad
+	width += 4
+	if m.width > 0 {
+		width = min(width, max(m.width, MinColumnWidth))
+	}
+	m.SetColumnWidth(col, width)
+	return m.layoutChanged()
+}
+
+// layoutChanged reports the current layout.
+func (m Model) layoutChanged() tea.Cmd {
+	layout := m.Layout()
+	return func() tea.Msg { return LayoutChangedMsg{Layout: layout} }
+}
@@ -0,0 +1,53 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextWidth(t *testing.T) {
+	assert.Equal(t, 6, TextWidth("Привет"))
+	assert.Equal(t, 4, TextWidth("東京"))
+	assert.Equal(t, 2, TextWidth("👩‍💻"))
+	assert.Equal(t, 2, TextWidth("🇺🇦"))
+	assert.Equal(t, 1, TextWidth("é"))
+}
+
+func TestTruncateOrPad(t *testing.T) {
+	assert.Equal(t, " Привет   ", truncateOrPad("Привет", 12))
+	assert.Equal(t, " Прив… ", truncateOrPad("Привет", 9))
+	assert.Equal(t, " 東…  ", truncateOrPad("東京タワー", 8))
+	assert.Equal(t, " 👩‍💻… ", truncateOrPad("👩‍💻👩‍💻👩‍💻", 7))
+	assert.Equal(t, "  ", truncateOrPad("abc", 3))
+	assert.Equal(t, " a↵b ", truncateOrPad("a\nb", 7))
+}
+
+func TestResizeColumns(t *testing.T) {
+	m := New(
+		WithColumns([]Column{{Title: "id", Width: 6}, {Title: "name", Width: 10}}),
+		WithRows([]Row{{"1", "Zoë Ångström"}, {"2", Null}}),
+	)
+
+	col, ok := m.ColumnEdgeAt(6)
+	assert.True(t, ok)
+	assert.Equal(t, 0, col)
+	col, ok = m.ColumnEdgeAt(15)
+	assert.True(t, ok)
+	assert.Equal(t, 1, col)
+	_, ok = m.ColumnEdgeAt(10)
+	assert.False(t, ok)
+
+	m.SetColumnWidth(0, 1)
+	assert.Equal(t, MinColumnWidth, m.ColumnWidth(0))
+
+	m.SetCursor(0, 1)
+	msg := m.autofitFocused()()
+	assert.Equal(t, 16, m.ColumnWidth(1))
+	assert.Equal(t, LayoutChangedMsg{Layout: Layout{Widths: map[string]int{"id": 5, "name": 16}}}, msg)
+
+	// Widths are kept by title when the columns are set again
+	m.SetColumns([]Column{{Title: "name", Width: 10}, {Title: "id", Width: 6}})
+	assert.Equal(t, 16, m.ColumnWidth(0))
+	assert.Equal(t, 5, m.ColumnWidth(1))
+}
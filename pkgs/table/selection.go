@@ -0,0 +1,400 @@
+package table
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/SavingFrame/dbettier/pkgs/clipboard"
+)
+
+// SelectionMode is the kind of visual selection.
+type SelectionMode int
+
+const (
+	// SelectNone means no selection: yanking copies the focused cell.
+	SelectNone SelectionMode = iota
+	// SelectCells selects the rectangle between the anchor and the focused cell.
+	SelectCells
+	// SelectRows selects whole rows between the anchor and the focused row.
+	SelectRows
+	// SelectColumns selects whole columns between the anchor and the focused column.
+	SelectColumns
+)
+
+// YankFormat is a format a selection can be copied in.
+type YankFormat int
+
+const (
+	YankTSV YankFormat = iota
+	YankCSV
+	YankMarkdown
+	YankJSON
+	YankInList
+	YankInsert
+)
+
+// yankFormats lists the formats in the menu, with the key that picks each.
+var yankFormats = []struct {
+	format YankFormat
+	key    string
+	name   string
+}{
+	{YankTSV, "t", "TSV"},
+	{YankCSV, "c", "CSV with header"},
+	{YankMarkdown, "m", "Markdown table"},
+	{YankJSON, "j", "JSON array"},
+	{YankInList, "i", "SQL IN (...) list"},
+	{YankInsert, "s", "SQL INSERT statements"},
+}
+
+// yankMenu asks which format to copy the selection in.
+type yankMenu struct {
+	cursor int
+}
+
+// Selection returns the kind of visual selection, SelectNone if there is none.
+func (m Model) Selection() SelectionMode {
+	return m.selection
+}
+
+// ClearSelection ends the visual selection.
+func (m *Model) ClearSelection() {
+	m.selection = SelectNone
+}
+
+// YankMenuOpen reports whether the menu of yank formats is open. It takes
+// all keys while open.
+func (m Model) YankMenuOpen() bool {
+	return m.yankMenu != nil
+}
+
+// SetCopyRows sets the text copied for each cell instead of the text shown,
+// e.g. without the formatting added for display. Rows beyond it, modified
+// cells and Null cells are copied as shown.
+func (m *Model) SetCopyRows(rows []Row) {
+	m.SetCopySource(sliceSource(rows))
+}
+
+// SetCopySource is SetCopyRows with the text read from src as it is copied.
+func (m *Model) SetCopySource(src RowSource) {
+	m.copyRows = src
+}
+
+// SetYankTable sets the table named in yanked INSERT statements.
+func (m *Model) SetYankTable(name string) {
+	m.yankTable = name
+}
+
+// toggleSelection starts a selection of the given kind at the focused cell,
+// switches to it from another kind, or ends it.
+func (m *Model) toggleSelection(mode SelectionMode) {
+	switch m.selection {
+	case mode:
+		m.selection = SelectNone
+	case SelectNone:
+		m.selection = mode
+		m.anchor = Cell{Row: m.focusedRow, Col: m.focusedCol}
+	default:
+		m.selection = mode
+	}
+}
+
+// selectionBounds returns the first and last selected row, and the first
+// and last selected column as positions among the shown columns. With no
+// selection that is the focused cell.
+func (m Model) selectionBounds() (firstRow, lastRow, firstCol, lastCol int) {
+	focusedPos, anchorPos := m.displayPos(m.focusedCol), max(0, m.displayPos(m.anchor.Col))
+	firstRow, lastRow = m.focusedRow, m.focusedRow
+	firstCol, lastCol = focusedPos, focusedPos
+	if m.selection == SelectCells || m.selection == SelectRows {
+		firstRow, lastRow = min(m.anchor.Row, m.focusedRow), max(m.anchor.Row, m.focusedRow)
+	}
+	if m.selection == SelectCells || m.selection == SelectColumns {
+		firstCol, lastCol = min(anchorPos, focusedPos), max(anchorPos, focusedPos)
+	}
+	if m.selection == SelectRows {
+		firstCol, lastCol = 0, len(m.display)-1
+	}
+	if m.selection == SelectColumns {
+		firstRow, lastRow = 0, m.rows.Len()-1
+	}
+	// Rows may have been replaced since the selection started
+	lastRow = min(lastRow, m.rows.Len()-1)
+	lastCol = min(lastCol, len(m.display)-1)
+	return firstRow, lastRow, firstCol, lastCol
+}
+
+// isSelected reports whether a cell is in the visual selection.
+func (m Model) isSelected(rowIdx, colIdx int) bool {
+	if m.selection == SelectNone {
+		return false
+	}
+	firstRow, lastRow, firstCol, lastCol := m.selectionBounds()
+	pos := m.displayPos(colIdx)
+	return rowIdx >= firstRow && rowIdx <= lastRow && pos >= firstCol && pos <= lastCol
+}
+
+// selectedCells returns the shown columns and the cell text of the
+// selection, or of the focused cell if there is none.
+func (m Model) selectedCells() ([]Column, []Row) {
+	firstRow, lastRow, firstCol, lastCol := m.selectionBounds()
+	if firstRow > lastRow || firstCol > lastCol || firstRow < 0 || firstCol < 0 {
+		return nil, nil
+	}
+	indices := m.display[firstCol : lastCol+1]
+	cols := make([]Column, len(indices))
+	for i, c := range indices {
+		cols[i] = m.cols[c]
+	}
+	rows := make([]Row, 0, lastRow-firstRow+1)
+	for r := firstRow; r <= lastRow; r++ {
+		row := make(Row, len(cols))
+		for c := range row {
+			row[c] = m.copyText(r, indices[c])
+		}
+		rows = append(rows, row)
+	}
+	return cols, rows
+}
+
+// copyText returns the text copied for a cell.
+func (m Model) copyText(rowIdx, colIdx int) string {
+	cell := m.rows.Cell(rowIdx, colIdx)
+	if cell == Null || m.modified[Cell{Row: rowIdx, Col: colIdx}] {
+		return cell
+	}
+	if m.copyRows != nil && rowIdx < m.copyRows.Len() {
+		return m.copyRows.Cell(rowIdx, colIdx)
+	}
+	return cell
+}
+
+// openYankMenu asks for the format to copy the selection in.
+func (m *Model) openYankMenu() {
+	if m.rows.Len() == 0 || len(m.cols) == 0 {
+		return
+	}
+	m.yankMenu = &yankMenu{}
+}
+
+// updateYankMenu handles a key in the yank menu. Picking a format copies
+// the selection and ends it.
+func (m Model) updateYankMenu(msg tea.KeyMsg) (Model, tea.Cmd) {
+	pick := -1
+	switch msg.String() {
+	case "esc", "q":
+		m.yankMenu = nil
+		return m, nil
+	case "up", "k":
+		m.yankMenu.cursor = max(0, m.yankMenu.cursor-1)
+	case "down", "j":
+		m.yankMenu.cursor = min(len(yankFormats)-1, m.yankMenu.cursor+1)
+	case "enter":
+		pick = m.yankMenu.cursor
+	default:
+		for i, f := range yankFormats {
+			if msg.String() == f.key {
+				pick = i
+			}
+		}
+	}
+	if pick < 0 {
+		return m, nil
+	}
+	cols, rows := m.selectedCells()
+	text := formatYank(yankFormats[pick].format, cols, rows, m.nullText, m.yankTable)
+	m.yankMenu = nil
+	m.selection = SelectNone
+	return m, clipboard.Copy(text)
+}
+
+// renderYankMenu renders the yank menu as a popup.
+func (m Model) renderYankMenu() string {
+	firstRow, lastRow, firstCol, lastCol := m.selectionBounds()
+	rows, cols := lastRow-firstRow+1, lastCol-firstCol+1
+	lines := []string{inspectorTitleStyle().Render(fmt.Sprintf("Copy %s × %s as", plural(rows, "row"), plural(cols, "column")))}
+	for i, f := range yankFormats {
+		line := " " + f.key + "  " + f.name + " "
+		if i == m.yankMenu.cursor {
+			lines = append(lines, yankMenuSelectedStyle().Render(line))
+		} else {
+			lines = append(lines, inspectorDimStyle().Render(line))
+		}
+	}
+	lines = append(lines, "", inspectorDimStyle().Render("key/enter copy · esc cancel"))
+	return inspectorStyle().Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// plural returns n with the noun, in the plural unless n is 1.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// formatYank returns rows of cell text in a yank format. Cells of
+// right-aligned columns that read as numbers are written bare in JSON and
+// SQL, and Null as null or NULL.
+func formatYank(format YankFormat, cols []Column, rows []Row, nullText, table string) string {
+	switch format {
+	case YankTSV, YankCSV:
+		var b strings.Builder
+		w := csv.NewWriter(&b)
+		if format == YankTSV {
+			w.Comma = '\t'
+		} else {
+			_ = w.Write(columnTitles(cols))
+		}
+		for _, row := range rows {
+			record := make([]string, len(row))
+			for i, cell := range row {
+				if cell != Null {
+					record[i] = cell
+				}
+			}
+			_ = w.Write(record)
+		}
+		w.Flush()
+		return strings.TrimSuffix(b.String(), "\n")
+	case YankMarkdown:
+		lines := make([]string, 0, len(rows)+2)
+		cells := make([]string, len(cols))
+		for i, col := range cols {
+			cells[i] = markdownCell(col.Title)
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		for i, col := range cols {
+			cells[i] = "---"
+			if col.AlignRight {
+				cells[i] = "---:"
+			}
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		for _, row := range rows {
+			for i, cell := range row {
+				if cell == Null {
+					cell = nullText
+				}
+				cells[i] = markdownCell(cell)
+			}
+			lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		}
+		return strings.Join(lines, "\n")
+	case YankJSON:
+		keys := make([]string, len(cols))
+		for i, title := range columnTitles(cols) {
+			key, _ := json.Marshal(title)
+			keys[i] = string(key)
+		}
+		objects := make([]string, len(rows))
+		for r, row := range rows {
+			fields := make([]string, len(row))
+			for i, cell := range row {
+				fields[i] = keys[i] + ": " + jsonCell(cell, cols[i])
+			}
+			objects[r] = "  {" + strings.Join(fields, ", ") + "}"
+		}
+		if len(objects) == 0 {
+			return "[]"
+		}
+		return "[\n" + strings.Join(objects, ",\n") + "\n]"
+	case YankInList:
+		// NULL never matches IN, so it is left out, as are repeated values
+		seen := map[string]bool{}
+		var values []string
+		for _, row := range rows {
+			items := make([]string, 0, len(row))
+			for i, cell := range row {
+				if cell == Null {
+					items = nil
+					break
+				}
+				items = append(items, sqlCell(cell, cols[i]))
+			}
+			if items == nil {
+				continue
+			}
+			value := strings.Join(items, ", ")
+			if len(items) > 1 {
+				value = "(" + value + ")"
+			}
+			if !seen[value] {
+				seen[value] = true
+				values = append(values, value)
+			}
+		}
+		return "IN (" + strings.Join(values, ", ") + ")"
+	case YankInsert:
+		if table == "" {
+			table = "table_name"
+		}
+		names := columnTitles(cols)
+		for i, name := range names {
+			names[i] = `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+		}
+		prefix := "INSERT INTO " + table + " (" + strings.Join(names, ", ") + ") VALUES ("
+		lines := make([]string, len(rows))
+		values := make([]string, len(cols))
+		for r, row := range rows {
+			for i, cell := range row {
+				values[i] = sqlCell(cell, cols[i])
+			}
+			lines[r] = prefix + strings.Join(values, ", ") + ");"
+		}
+		return strings.Join(lines, "\n")
+	}
+	return ""
+}
+
+// columnTitles returns the titles of the columns.
+func columnTitles(cols []Column) []string {
+	titles := make([]string, len(cols))
+	for i, col := range cols {
+		titles[i] = col.Title
+	}
+	return titles
+}
+
+// isNumber reports whether a cell of a right-aligned column can be written
+// as a bare number. NaN and Infinity are not JSON numbers.
+func isNumber(cell string, col Column) bool {
+	return col.AlignRight && json.Valid([]byte(cell)) && strings.Trim(cell, "-+.0123456789eE") == ""
+}
+
+// jsonCell returns a cell as a JSON value.
+func jsonCell(cell string, col Column) string {
+	switch {
+	case cell == Null:
+		return "null"
+	case isNumber(cell, col):
+		return cell
+	case col.Type == "bool" && (cell == "true" || cell == "false"):
+		return cell
+	}
+	quoted, _ := json.Marshal(cell)
+	return string(quoted)
+}
+
+// sqlCell returns a cell as a SQL literal, quoted unless it is a number.
+func sqlCell(cell string, col Column) string {
+	switch {
+	case cell == Null:
+		return "NULL"
+	case isNumber(cell, col):
+		return cell
+	}
+	return "'" + strings.ReplaceAll(cell, "'", "''") + "'"
+}
+
+// markdownCell escapes what would end a Markdown table cell or row.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
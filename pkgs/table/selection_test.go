@@ -0,0 +1,67 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectedCells(t *testing.T) {
+	m := New(
+		WithColumns([]Column{{Title: "id"}, {Title: "name"}, {Title: "total"}}),
+		WithRows([]Row{{"1", "ann", "1,200"}, {"2", "bob", Null}, {"3", "cy", "7"}}),
+	)
+	m.SetCopyRows([]Row{{"1", "ann", "1200"}})
+
+	m.SetCursor(0, 1)
+	m.toggleSelection(SelectCells)
+	m.SetCursor(1, 2)
+	cols, rows := m.selectedCells()
+	assert.Equal(t, []Column{{Title: "name"}, {Title: "total"}}, cols)
+	assert.Equal(t, []Row{{"ann", "1200"}, {"bob", Null}}, rows)
+	assert.True(t, m.isSelected(1, 1))
+	assert.False(t, m.isSelected(2, 1))
+
+	m.toggleSelection(SelectRows)
+	cols, rows = m.selectedCells()
+	assert.Len(t, cols, 3)
+	assert.Equal(t, []Row{{"1", "ann", "1200"}, {"2", "bob", Null}}, rows)
+
+	m.toggleSelection(SelectColumns)
+	_, rows = m.selectedCells()
+	assert.Equal(t, []Row{{"ann", "1200"}, {"bob", Null}, {"cy", "7"}}, rows)
+
+	m.toggleSelection(SelectColumns)
+	_, rows = m.selectedCells()
+	assert.Equal(t, []Row{{Null}}, rows)
+}
+
+func TestFormatYank(t *testing.T) {
+	cols := []Column{{Title: "id", AlignRight: true}, {Title: "name"}, {Title: "ok", Type: "bool"}}
+	rows := []Row{{"1", "O'Brien, \"Pat\"", "true"}, {"2", Null, "false"}, {"1", "a|b", Null}}
+
+	assert.Equal(t, "1\t\"O'Brien, \"\"Pat\"\"\"\ttrue\n2\t\tfalse\n1\ta|b\t",
+		formatYank(YankTSV, cols, rows, "NULL", ""))
+	assert.Equal(t, "id,name,ok\n1,\"O'Brien, \"\"Pat\"\"\",true\n2,,false\n1,a|b,",
+		formatYank(YankCSV, cols, rows, "NULL", ""))
+	assert.Equal(t, `| id | name | ok |
+| ---: | --- | --- |
+| 1 | O'Brien, "Pat" | true |
+| 2 | NULL | false |
+| 1 | a\|b | NULL |`, formatYank(YankMarkdown, cols, rows, "NULL", ""))
+	assert.Equal(t, `[
+  {"id": 1, "name": "O'Brien, \"Pat\"", "ok": true},
+  {"id": 2, "name": null, "ok": false},
+  {"id": 1, "name": "a|b", "ok": null}
+]`, formatYank(YankJSON, cols, rows, "NULL", ""))
+	assert.Equal(t, "IN (1, 2)", formatYank(YankInList, cols[:1], []Row{{"1"}, {"2"}, {Null}, {"1"}}, "NULL", ""))
+	assert.Equal(t, "IN ((1, 'O''Brien, \"Pat\"'), (1, 'a|b'))",
+		formatYank(YankInList, cols[:2], []Row{rows[0][:2], rows[1][:2], rows[2][:2]}, "NULL", ""))
+	assert.Equal(t, `INSERT INTO "public"."people" ("id", "name", "ok") VALUES (1, 'O''Brien, "Pat"', 'true');
+INSERT INTO "public"."people" ("id", "name", "ok") VALUES (2, NULL, 'false');
+INSERT INTO "public"."people" ("id", "name", "ok") VALUES (1, 'a|b', NULL);`,
+		formatYank(YankInsert, cols, rows, "NULL", `"public"."people"`))
+
+	// NaN is not a JSON number
+	assert.Equal(t, "[\n  {\"id\": \"NaN\"}\n]", formatYank(YankJSON, cols[:1], []Row{{"NaN"}}, "NULL", ""))
+}
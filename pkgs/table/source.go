@@ -0,0 +1,65 @@
+package table
+
+// RowSource provides the text of a table's cells, so that only the cells
+// shown need to be formatted.
+type RowSource interface {
+	// Len returns the number of rows.
+	Len() int
+	// Cell returns the text of a cell, Null for SQL NULL, or "" if the row
+	// has no such cell.
+	Cell(row, col int) string
+}
+
+// sliceSource is a RowSource of rows already formatted.
+type sliceSource []Row
+
+func (s sliceSource) Len() int {
+	return len(s)
+}
+
+func (s sliceSource) Cell(row, col int) string {
+	if col < len(s[row]) {
+		return s[row][col]
+	}
+	return ""
+}
+
+// unformatted marks a cell of a CachedSource not formatted yet.
+const unformatted = "\x00\x01"
+
+// CachedSource is a RowSource that formats a cell when it is first asked
+// for and keeps the text. Rows are kept from their first formatted cell on.
+type CachedSource struct {
+	rows, cols int
+	format     func(row, col int) string
+	cache      [][]string
+}
+
+// NewCachedSource returns a source of rows×cols cells formatted by format.
+func NewCachedSource(rows, cols int, format func(row, col int) string) *CachedSource {
+	return &CachedSource{rows: rows, cols: cols, format: format, cache: make([][]string, rows)}
+}
+
+// Len returns the number of rows.
+func (s *CachedSource) Len() int {
+	return s.rows
+}
+
+// Cell returns the text of a cell, formatting it the first time.
+func (s *CachedSource) Cell(row, col int) string {
+	if col < 0 || col >= s.cols {
+		return ""
+	}
+	cells := s.cache[row]
+	if cells == nil {
+		cells = make([]string, s.cols)
+		for i := range cells {
+			cells[i] = unformatted
+		}
+		s.cache[row] = cells
+	}
+	if cells[col] == unformatted {
+		cells[col] = s.format(row, col)
+	}
+	return cells[col]
+}
@@ -0,0 +1,116 @@
+package table
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gridSource returns a source of rows×cols numbered cells, counting the
+// cells formatted
+func gridSource(rows, cols int, formatted *int) *CachedSource {
+	return NewCachedSource(rows, cols, func(row, col int) string {
+		*formatted++
+		if col == 1 && row%10 == 0 {
+			return Null
+		}
+		return "r" + strconv.Itoa(row) + "c" + strconv.Itoa(col)
+	})
+}
+
+// gridColumns returns n columns of the given width
+func gridColumns(n, width int) []Column {
+	cols := make([]Column, n)
+	for i := range cols {
+		cols[i] = Column{Title: "column_" + strconv.Itoa(i), Width: width}
+	}
+	return cols
+}
+
+func TestCachedSource(t *testing.T) {
+	formatted := 0
+	m := New(
+		WithColumns(gridColumns(200, 10)),
+		WithWidth(40),
+		WithHeight(12),
+		WithFocused(true),
+	)
+	m.SetRowSource(gridSource(10_000, 200, &formatted))
+
+	// Only the 10 rows and 4 columns shown are formatted, once
+	m.View()
+	m.View()
+	assert.Equal(t, 40, formatted)
+
+	m.SetCursor(5_000, 100)
+	m.View()
+	assert.Equal(t, 80, formatted)
+	assert.Equal(t, "r5000c100", m.SelectedCell())
+	assert.Equal(t, Row{"r10c0", Null}, m.Row(10)[:2])
+	assert.Nil(t, m.Row(10_000))
+	assert.Equal(t, "", m.rows.Cell(0, 200))
+}
+
+func BenchmarkView(b *testing.B) {
+	formatted := 0
+	m := New(
+		WithColumns(gridColumns(200, 14)),
+		WithWidth(200),
+		WithHeight(50),
+		WithFocused(true),
+	)
+	b.Run("cold", func(b *testing.B) {
+		for b.Loop() {
+			m.SetRowSource(gridSource(10_000, 200, &formatted))
+			m.View()
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		m.SetRowSource(gridSource(10_000, 200, &formatted))
+		for b.Loop() {
+			m.View()
+		}
+	})
+}
+
+func BenchmarkScroll(b *testing.B) {
+	formatted := 0
+	m := New(
+		WithColumns(gridColumns(200, 14)),
+		WithWidth(200),
+		WithHeight(50),
+		WithFocused(true),
+	)
+	m.SetRowSource(gridSource(10_000, 200, &formatted))
+	b.Run("down", func(b *testing.B) {
+		m.ScrollToTop()
+		for b.Loop() {
+			if m.IsLatestRowFocused() {
+				m.ScrollToTop()
+			}
+			m.moveDown()
+			m.View()
+		}
+	})
+	b.Run("page", func(b *testing.B) {
+		m.ScrollToTop()
+		for b.Loop() {
+			if m.IsLatestRowFocused() {
+				m.ScrollToTop()
+			}
+			m.pageDown()
+			m.View()
+		}
+	})
+	b.Run("right", func(b *testing.B) {
+		m.SetCursor(0, 0)
+		for b.Loop() {
+			if m.focusedCol == len(m.cols)-1 {
+				m.SetCursor(0, 0)
+			}
+			m.moveRight()
+			m.View()
+		}
+	})
+}
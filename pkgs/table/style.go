@@ -39,6 +39,22 @@ func DefaultStyles() Styles {
 			Background(colors.SearchActive).
 			Foreground(colors.Base).
 			Bold(true),
+		Selected: lipgloss.NewStyle().
+			Padding(0, 1).
+			Background(colors.Selection).
+			Foreground(colors.Text),
+		Null: lipgloss.NewStyle().
+			Foreground(colors.Muted).
+			Italic(true),
+		Modified: lipgloss.NewStyle().
+			Foreground(colors.Warning),
+		Deleted: lipgloss.NewStyle().
+			Foreground(colors.Error).
+			Strikethrough(true),
+		Editor: lipgloss.NewStyle().
+			Padding(0, 1).
+			Background(colors.Overlay).
+			Foreground(colors.Text),
 	}
 }
 
@@ -62,3 +78,39 @@ func scrollIndicatorStyle() lipgloss.Style {
 		Foreground(colors.Subtle).
 		Background(colors.Base)
 }
+
+// inspectorStyle returns the style for the cell inspector overlay.
+func inspectorStyle() lipgloss.Style {
+	colors := theme.Current().Colors
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colors.BorderFocused).
+		Background(colors.Surface).
+		Padding(0, 1)
+}
+
+// inspectorTitleStyle returns the style for the inspector's title.
+func inspectorTitleStyle() lipgloss.Style {
+	colors := theme.Current().Colors
+	return lipgloss.NewStyle().
+		Foreground(colors.Primary).
+		Background(colors.Surface).
+		Bold(true)
+}
+
+// inspectorDimStyle returns the style for the inspector's secondary text.
+func inspectorDimStyle() lipgloss.Style {
+	colors := theme.Current().Colors
+	return lipgloss.NewStyle().
+		Foreground(colors.Subtle).
+		Background(colors.Surface)
+}
+
+// yankMenuSelectedStyle returns the style for the chosen yank format.
+func yankMenuSelectedStyle() lipgloss.Style {
+	colors := theme.Current().Colors
+	return lipgloss.NewStyle().
+		Foreground(colors.Base).
+		Background(colors.Primary).
+		Bold(true)
+}
@@ -48,7 +48,8 @@ type SearchMatch struct {
 // Model defines the state for the table widget with cell-level focus.
 type Model struct {
 	cols []Column
-	rows []Row
+	// rows provides the text of the cells, formatted as they are shown
+	rows RowSource
 
 	// Cell-level focus
 	focusedRow int
@@ -70,20 +71,77 @@ type Model struct {
 	// Sorting
 	orderColumns []OrderCol
 
+	// showTypes shows column types in the header
+	showTypes bool
+	// nullText is shown for Null cells
+	nullText string
+
 	// Search
 	searchMode       bool          // Whether search input is active
 	searchQuery      string        // Current search query
 	searchMatches    []SearchMatch // All matching cells
 	searchMatchIndex int           // Current match index (-1 if no matches)
+	// searchIndex holds the index of each match in searchMatches
+	searchIndex map[SearchMatch]int
+
+	// inspector shows the focused cell's full value while open
+	inspector *inspector
+
+	// modified marks cells with unsaved changes
+	modified map[Cell]bool
+	// deleted marks rows to be deleted
+	deleted map[int]bool
+	// editor is drawn in place of the focused cell while it is edited
+	editor string
+
+	// Visual selection, from anchor to the focused cell
+	selection SelectionMode
+	anchor    Cell
+	// yankMenu asks for the format to copy the selection in while open
+	yankMenu *yankMenu
+	// copyRows provides the text copied instead of the text shown, see
+	// SetCopySource
+	copyRows RowSource
+	// yankTable is the table named in yanked INSERT statements
+	yankTable string
+
+	// Column layout, see Layout. order holds every column index in the
+	// order shown, display the shown ones, and position the index of each
+	// column in display, -1 if it is hidden. Column indices elsewhere in
+	// the model are indices in cols; scrollOffsetCol is a position in
+	// display.
+	order    []int
+	hidden   map[int]bool
+	frozen   int
+	display  []int
+	position []int
+	// widths holds the widths of resized columns
+	widths map[int]int
+	// chooser lists the columns to hide, move and freeze while open
+	chooser *columnChooser
+}
+
+// Cell identifies a cell by its row and column index.
+type Cell struct {
+	Row int
+	Col int
 }
 
 // Row represents one line in the table.
 type Row []string
 
+// Null is the cell value for SQL NULL. It is shown as the model's null text
+// in the Null style, so it cannot be mistaken for the text "NULL".
+const Null = "\x00NULL"
+
 // Column defines the table structure.
 type Column struct {
 	Title string
 	Width int
+	// Type is shown next to the title when column types are shown
+	Type string
+	// AlignRight right-aligns the column's cells and header, e.g. for numbers
+	AlignRight bool
 }
 
 // Styles contains style definitions for the table component.
@@ -95,6 +153,11 @@ type Styles struct {
 	SelectedCol       lipgloss.Style
 	SearchMatch       lipgloss.Style // Highlighted search match
 	SearchMatchActive lipgloss.Style // Currently focused search match
+	Selected          lipgloss.Style // Cells in the visual selection
+	Null              lipgloss.Style // Foreground and italics of NULL cells
+	Modified          lipgloss.Style // Foreground of cells with unsaved changes
+	Deleted           lipgloss.Style // Foreground and strikethrough of rows to be deleted
+	Editor            lipgloss.Style // The focused cell while it is edited
 }
 
 // Option is used to set options in New.
@@ -109,11 +172,14 @@ func New(opts ...Option) Model {
 		scrollOffsetCol: 0,
 		focused:         false,
 		styles:          DefaultStyles(),
+		nullText:        "NULL",
+		rows:            sliceSource(nil),
 	}
 
 	for _, opt := range opts {
 		opt(&m)
 	}
+	m.SetLayout(Layout{})
 
 	return m
 }
@@ -128,7 +194,7 @@ func WithColumns(cols []Column) Option {
 // WithRows sets the table rows (data).
 func WithRows(rows []Row) Option {
 	return func(m *Model) {
-		m.rows = rows
+		m.rows = sliceSource(rows)
 	}
 }
 
@@ -160,26 +226,61 @@ func WithStyles(s Styles) Option {
 	}
 }
 
+// WithShowTypes shows column types in the header.
+func WithShowTypes(s bool) Option {
+	return func(m *Model) {
+		m.showTypes = s
+	}
+}
+
+// WithNullText sets the text shown for Null cells.
+func WithNullText(text string) Option {
+	return func(m *Model) {
+		m.nullText = text
+	}
+}
+
 func WithScrollIndicator(s bool) Option {
 	return func(m *Model) {
 		m.scrollIndicator = s
 	}
 }
 
-// SetColumns updates the table columns.
+// ShowTypes reports whether column types are shown in the header.
+func (m Model) ShowTypes() bool {
+	return m.showTypes
+}
+
+// SetShowTypes shows or hides column types in the header.
+func (m *Model) SetShowTypes(show bool) {
+	m.showTypes = show
+}
+
+// SetColumns updates the table columns, keeping the layout of columns
+// with the same titles.
 func (m *Model) SetColumns(cols []Column) {
+	layout := m.Layout()
 	m.cols = cols
 	// Reset column focus if out of bounds
 	if m.focusedCol >= len(m.cols) {
 		m.focusedCol = 0
 	}
+	m.SetLayout(layout)
 }
 
 // SetRows updates the table rows.
 func (m *Model) SetRows(rows []Row) {
-	m.rows = rows
+	m.SetRowSource(sliceSource(rows))
+}
+
+// SetRowSource updates the table rows, read from src as they are shown.
+func (m *Model) SetRowSource(src RowSource) {
+	if src == nil {
+		src = sliceSource(nil)
+	}
+	m.rows = src
 	// Reset row focus if out of bounds
-	if m.focusedRow >= len(m.rows) {
+	if m.focusedRow >= m.rows.Len() {
 		m.focusedRow = 0
 	}
 }
@@ -187,11 +288,92 @@ func (m *Model) SetRows(rows []Row) {
 // SetHeight updates the table height.
 func (m *Model) SetHeight(h int) {
 	m.height = h
+	if m.inspector != nil {
+		m.inspector.resize(m.inspectorSize())
+	}
 }
 
 // SetWidth updates the table width.
 func (m *Model) SetWidth(w int) {
 	m.width = w
+	if m.inspector != nil {
+		m.inspector.resize(m.inspectorSize())
+	}
+}
+
+// OpenInspector shows the full value of the focused cell in an overlay.
+func (m *Model) OpenInspector() {
+	if m.focusedRow < 0 || m.focusedRow >= m.rows.Len() || m.focusedCol < 0 || m.focusedCol >= len(m.cols) {
+		return
+	}
+	value := m.rows.Cell(m.focusedRow, m.focusedCol)
+	width, height := m.inspectorSize()
+	m.inspector = newInspector(m.cols[m.focusedCol], value, m.nullText, width, height)
+}
+
+// CloseInspector hides the cell inspector.
+func (m *Model) CloseInspector() {
+	m.inspector = nil
+}
+
+// Inspecting reports whether the cell inspector is open.
+func (m Model) Inspecting() bool {
+	return m.inspector != nil
+}
+
+// InspectorSearching reports whether a search is being typed in the cell
+// inspector.
+func (m Model) InspectorSearching() bool {
+	return m.inspector != nil && m.inspector.searching()
+}
+
+// inspectorSize returns the size of the inspector overlay for the table's
+// size.
+func (m Model) inspectorSize() (width, height int) {
+	width, height = m.width, m.height
+	if width <= 0 {
+		width = 80
+	}
+	if height <= 0 {
+		height = 20
+	}
+	return width * 9 / 10, height - 2
+}
+
+// SetModifiedCells marks the given cells as changed but not yet saved.
+func (m *Model) SetModifiedCells(cells []Cell) {
+	m.modified = make(map[Cell]bool, len(cells))
+	for _, c := range cells {
+		m.modified[c] = true
+	}
+}
+
+// SetDeletedRows marks the given rows as to be deleted.
+func (m *Model) SetDeletedRows(rows []int) {
+	m.deleted = make(map[int]bool, len(rows))
+	for _, r := range rows {
+		m.deleted[r] = true
+	}
+}
+
+// SetCellEditor draws view, usually a text input's view, in place of the
+// focused cell. It is cut to the cell's width.
+func (m *Model) SetCellEditor(view string) {
+	m.editor = view
+}
+
+// ClearCellEditor draws the focused cell's value again.
+func (m *Model) ClearCellEditor() {
+	m.editor = ""
+}
+
+// FocusedCellWidth returns the width available for text in the focused
+// cell.
+func (m Model) FocusedCellWidth() int {
+	if m.focusedCol < 0 || m.focusedCol >= len(m.cols) {
+		return 0
+	}
+	return max(1, m.colWidth(m.focusedCol)-4)
 }
 
 // SetStyles updates the table styles.
@@ -219,25 +401,34 @@ func (m Model) Columns() []Column {
 	return m.cols
 }
 
-// Rows returns the current rows.
-func (m Model) Rows() []Row {
-	return m.rows
+// RowCount returns the number of rows.
+func (m Model) RowCount() int {
+	return m.rows.Len()
+}
+
+// Row returns the text of a row's cells, or nil if there is no such row.
+func (m Model) Row(i int) Row {
+	if i < 0 || i >= m.rows.Len() {
+		return nil
+	}
+	row := make(Row, len(m.cols))
+	for col := range row {
+		row[col] = m.rows.Cell(i, col)
+	}
+	return row
 }
 
 // SelectedRow returns the currently focused row data.
 func (m Model) SelectedRow() Row {
-	if m.focusedRow >= 0 && m.focusedRow < len(m.rows) {
-		return m.rows[m.focusedRow]
-	}
-	return nil
+	return m.Row(m.focusedRow)
 }
 
-// SelectedCell returns the currently focused cell data.
+// SelectedCell returns the currently focused cell data, or an empty string
+// for NULL.
 func (m Model) SelectedCell() string {
-	if m.focusedRow >= 0 && m.focusedRow < len(m.rows) {
-		row := m.rows[m.focusedRow]
-		if m.focusedCol >= 0 && m.focusedCol < len(row) {
-			return row[m.focusedCol]
+	if m.focusedRow >= 0 && m.focusedRow < m.rows.Len() && m.focusedCol >= 0 && m.focusedCol < len(m.cols) {
+		if cell := m.rows.Cell(m.focusedRow, m.focusedCol); cell != Null {
+			return cell
 		}
 	}
 	return ""
@@ -248,6 +439,17 @@ func (m Model) FocusedPosition() (row, col int) {
 	return m.focusedRow, m.focusedCol
 }
 
+// SetCursor focuses the cell at row and col, clamped to the table, and
+// scrolls it into view. The column stays as it is if col is hidden.
+func (m *Model) SetCursor(row, col int) {
+	m.focusedRow = max(0, min(row, m.rows.Len()-1))
+	if col = max(0, min(col, len(m.cols)-1)); m.isShown(col) {
+		m.focusedCol = col
+	}
+	m.updateScrollRow()
+	m.updateScrollCol()
+}
+
 // OrderColumns returns the current sort orders.
 func (m Model) OrderColumns() []OrderCol {
 	return m.orderColumns
@@ -265,7 +467,7 @@ func (m Model) GetHeight() int {
 
 // IsLatestRowFocused checks if the latest row is focused.
 func (m Model) IsLatestRowFocused() bool {
-	return m.focusedRow == len(m.rows)-1
+	return m.focusedRow == m.rows.Len()-1
 }
 
 // IsFirstRowFocused checks if the first row is focused.
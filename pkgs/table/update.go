@@ -1,10 +1,11 @@
 package table
 
 import (
-	"os/exec"
+	"slices"
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/SavingFrame/dbettier/pkgs/clipboard"
 )
 
 // SearchExitMsg is sent when search mode is exited.
@@ -24,6 +25,23 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// The inspector takes all keys while open
+		if m.inspector != nil {
+			open, cmd := m.inspector.update(msg)
+			if !open {
+				m.inspector = nil
+			}
+			return m, cmd
+		}
+
+		// So do the menu of yank formats and the column chooser
+		if m.yankMenu != nil {
+			return m.updateYankMenu(msg)
+		}
+		if m.chooser != nil {
+			return m.updateChooser(msg)
+		}
+
 		// Handle search mode input
 		if m.searchMode {
 			return m.handleSearchInput(msg)
@@ -39,6 +57,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m, nil
 
 		case "esc":
+			m.selection = SelectNone
 			m.searchMode = false
 			m.searchQuery = ""
 			m.searchMatches = nil
@@ -85,9 +104,41 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		case "ctrl+d":
 			m.halfPageDown()
 
-		// Yank (copy) cell value - vim key
+		// Visual selection of cells, rows or columns - vim keys
+		case "v":
+			m.toggleSelection(SelectCells)
+		case "V":
+			m.toggleSelection(SelectRows)
+		case "ctrl+v":
+			m.toggleSelection(SelectColumns)
+
+		// Yank (copy) cell value - vim key; a selection is copied in a
+		// format picked from a menu
 		case "y":
+			if m.selection != SelectNone {
+				m.openYankMenu()
+				return m, nil
+			}
 			return m, m.yankCell()
+		case "Y":
+			m.openYankMenu()
+			return m, nil
+
+		// Inspect the full cell value
+		case "i":
+			m.OpenInspector()
+
+		// Choose the columns shown, their order and the frozen columns
+		case "C":
+			m.openChooser()
+
+		// Resize the focused column, or fit it to its values
+		case "<":
+			return m, m.resizeFocused(-resizeStep)
+		case ">":
+			return m, m.resizeFocused(resizeStep)
+		case "=":
+			return m, m.autofitFocused()
 
 		// Sort by focused column - 's' key
 		case "s":
@@ -160,9 +211,10 @@ func (m *Model) updateSearchMatches() {
 
 	query := strings.ToLower(m.searchQuery)
 
-	for rowIdx, row := range m.rows {
-		for colIdx, cell := range row {
-			if strings.Contains(strings.ToLower(cell), query) {
+	for rowIdx := range m.rows.Len() {
+		// Shown columns only, in the order shown
+		for _, colIdx := range m.display {
+			if cell := m.rows.Cell(rowIdx, colIdx); cell != Null && strings.Contains(strings.ToLower(cell), query) {
 				m.searchMatches = append(m.searchMatches, SearchMatch{
 					Row: rowIdx,
 					Col: colIdx,
@@ -171,6 +223,11 @@ func (m *Model) updateSearchMatches() {
 		}
 	}
 
+	m.searchIndex = make(map[SearchMatch]int, len(m.searchMatches))
+	for i, match := range m.searchMatches {
+		m.searchIndex[match] = i
+	}
+
 	// If we have matches, set index to first match
 	if len(m.searchMatches) > 0 {
 		m.searchMatchIndex = 0
@@ -232,24 +289,24 @@ func (m *Model) moveUp() {
 
 // moveDown moves the focus down one row.
 func (m *Model) moveDown() {
-	if m.focusedRow < len(m.rows)-1 {
+	if m.focusedRow < m.rows.Len()-1 {
 		m.focusedRow++
 		m.updateScrollRow()
 	}
 }
 
-// moveLeft moves the focus left one column.
+// moveLeft moves the focus left one shown column.
 func (m *Model) moveLeft() {
-	if m.focusedCol > 0 {
-		m.focusedCol--
+	if pos := m.displayPos(m.focusedCol); pos > 0 {
+		m.focusedCol = m.display[pos-1]
 		m.updateScrollCol()
 	}
 }
 
-// moveRight moves the focus right one column.
+// moveRight moves the focus right one shown column.
 func (m *Model) moveRight() {
-	if m.focusedCol < len(m.cols)-1 {
-		m.focusedCol++
+	if pos := m.displayPos(m.focusedCol); pos >= 0 && pos < len(m.display)-1 {
+		m.focusedCol = m.display[pos+1]
 		m.updateScrollCol()
 	}
 }
@@ -278,8 +335,8 @@ func (m *Model) pageDown() {
 
 	visibleRows := m.height - 2 // Subtract header and border
 	m.focusedRow += visibleRows
-	if m.focusedRow >= len(m.rows) {
-		m.focusedRow = len(m.rows) - 1
+	if m.focusedRow >= m.rows.Len() {
+		m.focusedRow = m.rows.Len() - 1
 	}
 	m.updateScrollRow()
 }
@@ -308,39 +365,27 @@ func (m *Model) updateScrollRow() {
 	}
 }
 
-// updateScrollCol adjusts the scroll offset to keep the focused column visible.
+// updateScrollCol adjusts the scroll offset to keep the focused column
+// visible. Frozen columns are always visible.
 func (m *Model) updateScrollCol() {
-	if m.width <= 0 || len(m.cols) == 0 {
+	if m.width <= 0 || len(m.display) == 0 {
 		return
 	}
 
-	// If focused column is before scroll offset, scroll left
-	if m.focusedCol < m.scrollOffsetCol {
-		m.scrollOffsetCol = m.focusedCol
+	pos := m.displayPos(m.focusedCol)
+	if pos < 0 || pos < m.frozenCount() {
 		return
 	}
 
-	// Calculate how many columns fit in the available width
-	currentWidth := 0
-	visibleColCount := 0
-
-	for i := m.scrollOffsetCol; i < len(m.cols); i++ {
-		colWidth := m.cols[i].Width
-		if currentWidth+colWidth > m.width && visibleColCount > 0 {
-			break
-		}
-		currentWidth += colWidth
-		visibleColCount++
-
-		// If we've reached the focused column, we're done
-		if i == m.focusedCol {
-			return
-		}
+	// If focused column is before scroll offset, scroll left
+	if pos < m.scrollOffsetCol {
+		m.scrollOffsetCol = pos
+		return
 	}
 
 	// If focused column is not visible (after the visible columns), scroll right
-	if m.focusedCol >= m.scrollOffsetCol+visibleColCount {
-		m.scrollOffsetCol = m.focusedCol
+	if !slices.Contains(m.columnsFrom(m.scrollOffsetCol), m.focusedCol) {
+		m.scrollOffsetCol = pos
 	}
 }
 
@@ -368,8 +413,8 @@ func (m *Model) halfPageDown() {
 
 	visibleRows := (m.height - 2) / 2 // Half of visible rows
 	m.focusedRow += visibleRows
-	if m.focusedRow >= len(m.rows) {
-		m.focusedRow = len(m.rows) - 1
+	if m.focusedRow >= m.rows.Len() {
+		m.focusedRow = m.rows.Len() - 1
 	}
 	m.updateScrollRow()
 }
@@ -380,28 +425,7 @@ func (m Model) yankCell() tea.Cmd {
 	if cellValue == "" {
 		return nil
 	}
-
-	// Escape single quotes in the cell value
-	escapedValue := strings.ReplaceAll(cellValue, "'", "'\\''")
-
-	// Try xclip (Linux) first, fall back to pbcopy (macOS), then wl-copy (Wayland)
-	cmd := exec.Command("sh", "-c",
-		"printf '%s' '"+escapedValue+"' | xclip -selection clipboard 2>/dev/null || "+
-			"printf '%s' '"+escapedValue+"' | pbcopy 2>/dev/null || "+
-			"printf '%s' '"+escapedValue+"' | wl-copy 2>/dev/null")
-
-	return tea.ExecProcess(cmd, func(err error) tea.Msg {
-		if err != nil {
-			return YankMsg{Success: false, Value: cellValue}
-		}
-		return YankMsg{Success: true, Value: cellValue}
-	})
-}
-
-// YankMsg is sent when a cell value is yanked (copied).
-type YankMsg struct {
-	Success bool
-	Value   string
+	return clipboard.Copy(m.copyText(m.focusedRow, m.focusedCol))
 }
 
 // SortChangeMsg is sent when the sort order changes.
@@ -465,8 +489,8 @@ func (m Model) clearSort() (Model, tea.Cmd) {
 }
 
 func (m *Model) ScrollToBottom() {
-	if len(m.rows) > 0 {
-		m.focusedRow = len(m.rows) - 1
+	if m.rows.Len() > 0 {
+		m.focusedRow = m.rows.Len() - 1
 		m.updateScrollRow()
 	}
 }
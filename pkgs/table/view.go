@@ -2,10 +2,11 @@ package table
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"charm.land/lipgloss/v2"
-	"github.com/mattn/go-runewidth"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // View renders the table.
@@ -35,9 +36,33 @@ func (m Model) View() string {
 		s.WriteString(m.renderSearchBar())
 	}
 
+	if m.inspector != nil {
+		return m.renderPopup(s.String(), m.inspector.view())
+	}
+	if m.yankMenu != nil {
+		return m.renderPopup(s.String(), m.renderYankMenu())
+	}
+	if m.chooser != nil {
+		return m.renderPopup(s.String(), m.renderChooser())
+	}
 	return s.String()
 }
 
+// renderPopup draws a popup, such as the cell inspector, centered over the
+// table.
+func (m Model) renderPopup(base, popup string) string {
+	width := max(m.width, lipgloss.Width(base), lipgloss.Width(popup))
+	height := max(m.height, lipgloss.Height(base), lipgloss.Height(popup))
+	base = lipgloss.NewStyle().Width(width).Height(height).Render(base)
+
+	x := max(0, (width-lipgloss.Width(popup))/2)
+	y := max(0, (height-lipgloss.Height(popup))/2)
+	return lipgloss.NewCompositor(
+		lipgloss.NewLayer(base),
+		lipgloss.NewLayer(popup).X(x).Y(y),
+	).Render()
+}
+
 // renderSearchBar renders the search input bar.
 func (m Model) renderSearchBar() string {
 	searchStyle := searchBarStyle()
@@ -72,16 +97,9 @@ func (m Model) renderHeader() string {
 
 	for _, colIdx := range visibleCols {
 		col := m.cols[colIdx]
-		header := col.Title
-
-		// Add sort indicator if this column is sorted
-		sortIndicator := m.getSortIndicator(colIdx)
-		if sortIndicator != "" {
-			header = header + " " + sortIndicator
-		}
 
 		// Truncate or pad header to fit column width
-		header = truncateOrPad(header, col.Width)
+		header := alignCell(m.headerText(colIdx), m.colWidth(colIdx), col.AlignRight)
 
 		// Apply header style
 		style := m.styles.Header
@@ -97,6 +115,20 @@ func (m Model) renderHeader() string {
 	return lipgloss.JoinHorizontal(lipgloss.Top, headers...)
 }
 
+// headerText returns a column's title, with its type when types are shown
+// and its sort indicator.
+func (m Model) headerText(colIdx int) string {
+	col := m.cols[colIdx]
+	header := col.Title
+	if m.showTypes && col.Type != "" {
+		header += " " + col.Type
+	}
+	if sortIndicator := m.getSortIndicator(colIdx); sortIndicator != "" {
+		header += " " + sortIndicator
+	}
+	return header
+}
+
 // getSortIndicator returns the sort indicator for a column.
 // Returns "↑" for ascending, "↓" for descending, with number prefix for multi-column sorts.
 func (m Model) getSortIndicator(colIdx int) string {
@@ -122,9 +154,10 @@ func (m Model) getSortIndicator(colIdx int) string {
 	return ""
 }
 
-// renderRows renders the table rows (with scrolling).
+// renderRows renders the table rows (with scrolling). Only the cells shown
+// are read from the row source.
 func (m Model) renderRows() string {
-	if len(m.rows) == 0 {
+	if m.rows.Len() == 0 {
 		return m.styles.Cell.Render("No data")
 	}
 
@@ -133,56 +166,70 @@ func (m Model) renderRows() string {
 	// Calculate visible rows based on height
 	visibleRows := m.height - 2 // Subtract header and border
 	if visibleRows <= 0 {
-		visibleRows = len(m.rows)
+		visibleRows = m.rows.Len()
 	}
 
 	// Calculate end index for rendering
-	endIdx := m.scrollOffsetRow + visibleRows
-	if endIdx > len(m.rows) {
-		endIdx = len(m.rows)
-	}
+	endIdx := min(m.scrollOffsetRow+visibleRows, m.rows.Len())
+
+	// Calculate visible columns, the same for every row
+	visibleCols := m.getVisibleColumns()
 
 	// Render visible rows
 	for rowIdx := m.scrollOffsetRow; rowIdx < endIdx; rowIdx++ {
-		if rowIdx >= len(m.rows) {
-			break
-		}
-
-		row := m.rows[rowIdx]
-		rowStrings = append(rowStrings, m.renderRow(row, rowIdx))
+		rowStrings = append(rowStrings, m.renderRow(rowIdx, visibleCols))
 	}
 
 	return strings.Join(rowStrings, "\n")
 }
 
-// renderRow renders a single row (with horizontal scrolling).
-func (m Model) renderRow(row Row, rowIdx int) string {
-	var cells []string
-
-	// Calculate visible columns
-	visibleCols := m.getVisibleColumns()
+// renderRow renders the visible columns of a single row.
+func (m Model) renderRow(rowIdx int, visibleCols []int) string {
+	cells := make([]string, 0, len(visibleCols))
 
 	for _, colIdx := range visibleCols {
 		col := m.cols[colIdx]
-		var cellValue string
+		cellValue := m.rows.Cell(rowIdx, colIdx)
 
-		// Get cell value (handle cases where row has fewer cells than columns)
-		if colIdx < len(row) {
-			cellValue = row[colIdx]
-		} else {
-			cellValue = ""
+		isFocusedCell := m.focused && rowIdx == m.focusedRow && colIdx == m.focusedCol
+		if isFocusedCell && m.editor != "" {
+			cells = append(cells, m.styles.Editor.Render(fitEditor(m.editor, m.colWidth(colIdx))))
+			continue
 		}
 
-		// Truncate or pad cell to fit column width
-		cellValue = truncateOrPad(cellValue, col.Width)
-
 		// Apply appropriate style based on focus
 		style := m.getCellStyle(rowIdx, colIdx)
+		if m.modified[Cell{Row: rowIdx, Col: colIdx}] {
+			if isFocusedCell {
+				style = style.Underline(true)
+			} else {
+				style = style.Foreground(m.styles.Modified.GetForeground())
+			}
+		}
+		if m.deleted[rowIdx] {
+			style = style.Strikethrough(m.styles.Deleted.GetStrikethrough())
+			if !isFocusedCell {
+				style = style.Foreground(m.styles.Deleted.GetForeground())
+			}
+		}
+		if cellValue == Null {
+			cellValue = m.nullText
+			if isFocusedCell {
+				// Keep the focused cell's colors readable
+				style = style.Italic(true)
+			} else {
+				style = style.Foreground(m.styles.Null.GetForeground()).Italic(m.styles.Null.GetItalic())
+			}
+		}
+
+		// Truncate or pad cell to fit column width
+		cellValue = alignCell(cellValue, m.colWidth(colIdx), col.AlignRight)
 
 		cells = append(cells, style.Render(cellValue))
 	}
 
-	return lipgloss.JoinHorizontal(lipgloss.Top, cells...)
+	// Cells are one line each, so they join end to end
+	return strings.Join(cells, "")
 }
 
 // getCellStyle returns the appropriate style for a cell based on focus state.
@@ -209,6 +256,10 @@ func (m Model) getCellStyle(rowIdx, colIdx int) lipgloss.Style {
 		return m.styles.SearchMatch
 	}
 
+	if m.isSelected(rowIdx, colIdx) {
+		return m.styles.Selected
+	}
+
 	// Cell is in the focused row
 	if rowIdx == m.focusedRow {
 		return m.styles.SelectedRow
@@ -229,56 +280,91 @@ func (m Model) isSearchMatch(rowIdx, colIdx int) (isMatch, isActive bool) {
 		return false, false
 	}
 
-	for i, match := range m.searchMatches {
-		if match.Row == rowIdx && match.Col == colIdx {
-			return true, i == m.searchMatchIndex
-		}
+	i, ok := m.searchIndex[SearchMatch{Row: rowIdx, Col: colIdx}]
+	return ok, ok && i == m.searchMatchIndex
+}
+
+// lineBreaks shows line breaks and tabs in a cell as single characters.
+var lineBreaks = strings.NewReplacer("\r\n", "↵", "\n", "↵", "\r", "↵", "\t", " ")
+
+// oneLine keeps a cell's text on one line.
+func oneLine(s string) string {
+	if strings.ContainsAny(s, "\r\n\t") {
+		return lineBreaks.Replace(s)
 	}
-	return false, false
+	return s
 }
 
-// truncateOrPad truncates or pads a string to the specified width.
-// It handles multi-byte characters properly using runewidth.
+// truncateOrPad truncates or pads a string to the specified width. Widths
+// are measured by grapheme cluster, and a cut is marked with an ellipsis.
 func truncateOrPad(s string, width int) string {
 	// Account for padding (1 space on each side)
-	contentWidth := width - 4
-	if contentWidth < 0 {
-		contentWidth = 0
-	}
-
-	strWidth := runewidth.StringWidth(s)
+	contentWidth := max(width-4, 0)
 
+	s = oneLine(s)
+	strWidth := TextWidth(s)
 	if strWidth > contentWidth {
-		// Truncate with ellipsis
-		if contentWidth <= 3 {
-			return " " + runewidth.Truncate(s, contentWidth, "") + " "
-		}
-		return " " + runewidth.Truncate(s, contentWidth-3, "") + "..." + " "
+		s = ansi.Truncate(s, contentWidth, "…")
+		strWidth = TextWidth(s)
 	}
 
 	// Pad with spaces
-	padding := contentWidth - strWidth
-	return " " + s + strings.Repeat(" ", padding) + " "
+	return " " + s + strings.Repeat(" ", contentWidth-strWidth) + " "
+}
+
+// fitEditor cuts or pads an editor's view to the column width, keeping
+// the same outer padding as truncateOrPad.
+func fitEditor(view string, width int) string {
+	contentWidth := max(width-4, 1)
+	view = ansi.Truncate(view, contentWidth, "")
+	return " " + view + strings.Repeat(" ", max(0, contentWidth-ansi.StringWidth(view))) + " "
+}
+
+// alignCell truncates or pads s to the column width, padding on the left
+// when the column is right-aligned.
+func alignCell(s string, width int, alignRight bool) string {
+	if !alignRight {
+		return truncateOrPad(s, width)
+	}
+	contentWidth := max(width-4, 0)
+	s = oneLine(s)
+	strWidth := TextWidth(s)
+	if strWidth > contentWidth {
+		return truncateOrPad(s, width)
+	}
+	// Same outer padding as truncateOrPad, with the gap moved to the left
+	return " " + strings.Repeat(" ", contentWidth-strWidth) + s + " "
 }
 
-// getVisibleColumns returns the indices of columns that should be visible
-// based on the current scroll offset and available width.
+// getVisibleColumns returns the indices of columns that should be visible:
+// the frozen columns, then the shown columns from the scroll offset on, as
+// many as fit in the available width.
 func (m Model) getVisibleColumns() []int {
 	if m.width <= 0 || len(m.cols) == 0 {
-		// If no width constraint, show all columns
-		result := make([]int, len(m.cols))
-		for i := range m.cols {
-			result[i] = i
-		}
-		return result
+		// If no width constraint, show all shown columns
+		return m.display
+	}
+
+	visibleCols := m.columnsFrom(m.scrollOffsetCol)
+
+	// If focused column is not visible, show at least the focused column
+	if !slices.Contains(visibleCols, m.focusedCol) {
+		visibleCols = []int{m.focusedCol}
 	}
 
+	return visibleCols
+}
+
+// columnsFrom returns the frozen columns and the shown columns from display
+// position offset on that fit in the width.
+func (m Model) columnsFrom(offset int) []int {
 	var visibleCols []int
 	currentWidth := 0
 
-	// Start from scroll offset and add columns until we run out of width
-	for i := m.scrollOffsetCol; i < len(m.cols); i++ {
-		colWidth := m.cols[i].Width
+	frozen := m.frozenCount()
+	candidates := slices.Concat(m.display[:frozen], m.display[max(frozen, offset):])
+	for _, i := range candidates {
+		colWidth := m.colWidth(i)
 
 		// Check if we have room for this column
 		if currentWidth+colWidth > m.width && len(visibleCols) > 0 {
@@ -288,20 +374,12 @@ func (m Model) getVisibleColumns() []int {
 		visibleCols = append(visibleCols, i)
 		currentWidth += colWidth
 	}
-
-	// If focused column is not visible, show at least the focused column
-	if len(visibleCols) == 0 {
-		visibleCols = []int{m.focusedCol}
-	} else if visibleCols[0] > m.focusedCol || visibleCols[len(visibleCols)-1] < m.focusedCol {
-		visibleCols = []int{m.focusedCol}
-	}
-
 	return visibleCols
 }
 
 // renderScrollIndicators renders scroll position indicators.
 func (m Model) renderScrollIndicators() string {
-	if len(m.rows) == 0 {
+	if m.rows.Len() == 0 {
 		return ""
 	}
 
@@ -311,7 +389,7 @@ func (m Model) renderScrollIndicators() string {
 	// Vertical scroll indicator
 	if m.height > 2 {
 		visibleRows := m.height - 2
-		totalRows := len(m.rows)
+		totalRows := m.rows.Len()
 
 		if totalRows > visibleRows {
 			currentPos := m.focusedRow + 1
@@ -320,10 +398,10 @@ func (m Model) renderScrollIndicators() string {
 		}
 	}
 
-	// Horizontal scroll indicator
-	if len(m.cols) > 0 {
-		currentCol := m.focusedCol + 1
-		totalCols := len(m.cols)
+	// Horizontal scroll indicator, by position among the shown columns
+	if len(m.display) > 0 {
+		currentCol := m.displayPos(m.focusedCol) + 1
+		totalCols := len(m.display)
 
 		if totalCols > 1 {
 			indicator := style.Render("Col " + formatNumber(currentCol) + "/" + formatNumber(totalCols))